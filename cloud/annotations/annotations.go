@@ -9,6 +9,10 @@ const (
 	AnnLinodeDefaultAlgorithm     = "service.beta.kubernetes.io/linode-loadbalancer-default-algorithm"
 	AnnLinodeDefaultStickiness    = "service.beta.kubernetes.io/linode-loadbalancer-default-stickiness"
 
+	// AnnLinodeLoadBalancerConfig is the annotation naming a LinodeLoadBalancerConfig in the
+	// Service's namespace. Other annotations on the Service override the settings it provides.
+	AnnLinodeLoadBalancerConfig = "service.beta.kubernetes.io/linode-loadbalancer-config"
+
 	AnnLinodeCheckPath       = "service.beta.kubernetes.io/linode-loadbalancer-check-path"
 	AnnLinodeCheckBody       = "service.beta.kubernetes.io/linode-loadbalancer-check-body"
 	AnnLinodeHealthCheckType = "service.beta.kubernetes.io/linode-loadbalancer-check-type"
@@ -33,9 +37,23 @@ const (
 	// should be retained when the NodeBalancer is deleted. Defaults to "true".
 	AnnLinodeLoadBalancerRetainReservedIPv4 = "service.beta.kubernetes.io/linode-loadbalancer-retain-reserved-ipv4"
 
+	// AnnLinodeLoadBalancerReserveIPv4 is the annotation used to have the CCM reserve an IPv4 address
+	// for the NodeBalancer when it is created. The address is recorded in AnnLinodeLoadBalancerReservedIPv4.
+	AnnLinodeLoadBalancerReserveIPv4 = "service.beta.kubernetes.io/linode-loadbalancer-reserve-ipv4"
+
+	// AnnLinodeLoadBalancerReservedIPv4Handover allows the CCM to move a service to a new NodeBalancer
+	// when the reserved-ipv4 annotation no longer matches the IPv4 address of its NodeBalancer.
+	AnnLinodeLoadBalancerReservedIPv4Handover = "service.beta.kubernetes.io/linode-loadbalancer-reserved-ipv4-handover"
+
 	AnnLinodeLoadBalancerPreserve = "service.beta.kubernetes.io/linode-loadbalancer-preserve"
 	AnnLinodeNodeBalancerID       = "service.beta.kubernetes.io/linode-loadbalancer-nodebalancer-id"
 	AnnLinodeNodeBalancerType     = "service.beta.kubernetes.io/linode-loadbalancer-nodebalancer-type"
+	// AnnLinodeNodeBalancerTypeMigration allows the CCM to replace the NodeBalancer of a service
+	// with a new one when the nodebalancer-type annotation no longer matches its type.
+	AnnLinodeNodeBalancerTypeMigration = "service.beta.kubernetes.io/linode-loadbalancer-allow-type-migration"
+	// AnnLinodeNodeBalancerTypeMigrationDowntime additionally allows a type migration that keeps the
+	// reserved IPv4 address of a service, which deletes the NodeBalancer before creating the new one.
+	AnnLinodeNodeBalancerTypeMigrationDowntime = "service.beta.kubernetes.io/linode-loadbalancer-allow-type-migration-downtime"
 
 	AnnLinodeHostnameOnlyIngress = "service.beta.kubernetes.io/linode-loadbalancer-hostname-only-ingress"
 	AnnLinodeLoadBalancerTags    = "service.beta.kubernetes.io/linode-loadbalancer-tags"
@@ -47,6 +65,12 @@ const (
 	AnnLinodeEnableIPv6Ingress = "service.beta.kubernetes.io/linode-loadbalancer-enable-ipv6-ingress"
 	// AnnLinodeEnableIPv6Backends controls whether a NodeBalancer service should use public IPv6 backend nodes.
 	AnnLinodeEnableIPv6Backends = "service.beta.kubernetes.io/linode-loadbalancer-enable-ipv6-backends"
+	// AnnLinodeBackendNodeSelector is a label selector, e.g. "pool=ingress", limiting the
+	// NodeBalancer backends of a service to the matching nodes.
+	AnnLinodeBackendNodeSelector = "service.beta.kubernetes.io/linode-loadbalancer-backend-node-selector"
+	// AnnLinodeEndpointAwareBackends limits the NodeBalancer backends of a service to the nodes that
+	// host its ready endpoints when set to "true".
+	AnnLinodeEndpointAwareBackends = "service.beta.kubernetes.io/linode-loadbalancer-endpoint-aware-backends"
 
 	AnnLinodeNodePrivateIP  = "node.k8s.linode.com/private-ip"
 	AnnLinodeHostUUID       = "node.k8s.linode.com/host-uuid"
@@ -54,6 +78,14 @@ const (
 
 	AnnLinodeNodeIPSharingUpdated = "node.k8s.linode.com/ip-sharing-updated"
 
+	// AnnLinodeNodeBalancerWeight sets the weight (1-255) of a node when it is a NodeBalancer
+	// backend. It can be set as a node annotation or label. Defaults to 100.
+	AnnLinodeNodeBalancerWeight = "node.k8s.linode.com/nodebalancer-weight"
+	// AnnLinodeNodeBalancerDrain moves a node to drain mode on all NodeBalancers when set to
+	// "true", so it receives no new connections. Cordoned nodes are drained as well.
+	// It can be set as a node annotation or label.
+	AnnLinodeNodeBalancerDrain = "node.k8s.linode.com/nodebalancer-drain"
+
 	NodeBalancerBackendIPv4Range = "service.beta.kubernetes.io/linode-loadbalancer-backend-ipv4-range"
 
 	NodeBalancerBackendVPCName    = "service.beta.kubernetes.io/linode-loadbalancer-backend-vpc-name"
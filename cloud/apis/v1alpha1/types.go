@@ -0,0 +1,146 @@
+// Package v1alpha1 contains the LinodeLoadBalancerConfig custom resource, which
+// groups NodeBalancer settings that would otherwise be spread across Service annotations.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	GroupName = "ccm.linode.com"
+	Version   = "v1alpha1"
+
+	LinodeLoadBalancerConfigKind     = "LinodeLoadBalancerConfig"
+	LinodeLoadBalancerConfigListKind = "LinodeLoadBalancerConfigList"
+	LinodeLoadBalancerConfigResource = "linodeloadbalancerconfigs"
+)
+
+var (
+	// SchemeGroupVersion is the group version used for the custom resources in this package.
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
+	// LinodeLoadBalancerConfigGVR identifies the LinodeLoadBalancerConfig resource for dynamic clients.
+	LinodeLoadBalancerConfigGVR = SchemeGroupVersion.WithResource(LinodeLoadBalancerConfigResource)
+)
+
+// LinodeLoadBalancerConfig holds NodeBalancer settings for the Services that reference it
+// by name. Settings made through Service annotations take precedence.
+type LinodeLoadBalancerConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LinodeLoadBalancerConfigSpec   `json:"spec,omitempty"`
+	Status LinodeLoadBalancerConfigStatus `json:"status,omitempty"`
+}
+
+// LinodeLoadBalancerConfigList is a list of LinodeLoadBalancerConfig resources.
+type LinodeLoadBalancerConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []LinodeLoadBalancerConfig `json:"items"`
+}
+
+// LinodeLoadBalancerConfigSpec describes the desired NodeBalancer configuration.
+type LinodeLoadBalancerConfigSpec struct {
+	// DefaultProtocol is used for ports without a protocol in Ports.
+	DefaultProtocol string `json:"defaultProtocol,omitempty"`
+	// DefaultProxyProtocol is used for ports without a proxy protocol in Ports.
+	DefaultProxyProtocol string `json:"defaultProxyProtocol,omitempty"`
+	// DefaultAlgorithm is used for ports without an algorithm in Ports.
+	DefaultAlgorithm string `json:"defaultAlgorithm,omitempty"`
+	// DefaultStickiness is used for ports without a stickiness in Ports.
+	DefaultStickiness string `json:"defaultStickiness,omitempty"`
+	// Ports holds per-port overrides keyed by the Service port number.
+	Ports []PortConfig `json:"ports,omitempty"`
+
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+
+	// Throttle is the client connection throttle, 0-20. 0 disables it.
+	Throttle *int `json:"throttle,omitempty"`
+	// Tags are added to the NodeBalancer alongside the cluster and default tags.
+	Tags []string `json:"tags,omitempty"`
+	// NodeBalancerType is the NodeBalancer type to create, e.g. common or premium.
+	NodeBalancerType string `json:"nodeBalancerType,omitempty"`
+
+	Firewall    *Firewall    `json:"firewall,omitempty"`
+	BackendVPC  *BackendVPC  `json:"backendVPC,omitempty"`
+	FrontendVPC *FrontendVPC `json:"frontendVPC,omitempty"`
+}
+
+// PortConfig configures a single NodeBalancer port.
+type PortConfig struct {
+	Port          int    `json:"port"`
+	Protocol      string `json:"protocol,omitempty"`
+	ProxyProtocol string `json:"proxyProtocol,omitempty"`
+	Algorithm     string `json:"algorithm,omitempty"`
+	Stickiness    string `json:"stickiness,omitempty"`
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// TLSCertField and TLSKeyField are the keys of the certificate chain and private key in the
+	// TLS secret. They default to tls.crt and tls.key.
+	TLSCertField string `json:"tlsCertField,omitempty"`
+	TLSKeyField  string `json:"tlsKeyField,omitempty"`
+	UDPCheckPort *int   `json:"udpCheckPort,omitempty"`
+}
+
+// HealthCheck configures how the NodeBalancer checks its backends.
+type HealthCheck struct {
+	Type         string `json:"type,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Body         string `json:"body,omitempty"`
+	Interval     *int   `json:"interval,omitempty"`
+	Timeout      *int   `json:"timeout,omitempty"`
+	Attempts     *int   `json:"attempts,omitempty"`
+	Passive      *bool  `json:"passive,omitempty"`
+	UDPCheckPort *int   `json:"udpCheckPort,omitempty"`
+}
+
+// Firewall attaches an existing Cloud Firewall by ID, or has the CCM manage one from an ACL.
+type Firewall struct {
+	ID  *int         `json:"id,omitempty"`
+	ACL *FirewallACL `json:"acl,omitempty"`
+}
+
+// FirewallACL is either an allow list or a deny list of addresses.
+type FirewallACL struct {
+	AllowList *NetworkAddresses `json:"allowList,omitempty"`
+	DenyList  *NetworkAddresses `json:"denyList,omitempty"`
+}
+
+// NetworkAddresses is a set of IPv4 and IPv6 addresses or CIDRs.
+type NetworkAddresses struct {
+	IPv4 []string `json:"ipv4,omitempty"`
+	IPv6 []string `json:"ipv6,omitempty"`
+}
+
+// BackendVPC selects the VPC subnet used for NodeBalancer backends.
+type BackendVPC struct {
+	VPCName    string `json:"vpcName,omitempty"`
+	SubnetName string `json:"subnetName,omitempty"`
+	SubnetID   *int   `json:"subnetID,omitempty"`
+	IPv4Range  string `json:"ipv4Range,omitempty"`
+}
+
+// FrontendVPC places the NodeBalancer frontend in a VPC subnet.
+type FrontendVPC struct {
+	VPCName    string `json:"vpcName,omitempty"`
+	SubnetName string `json:"subnetName,omitempty"`
+	SubnetID   *int   `json:"subnetID,omitempty"`
+	IPv4Range  string `json:"ipv4Range,omitempty"`
+	IPv6Range  string `json:"ipv6Range,omitempty"`
+}
+
+// LinodeLoadBalancerConfigStatus reports the state of the Services using the config.
+type LinodeLoadBalancerConfigStatus struct {
+	Services []ServiceStatus `json:"services,omitempty"`
+}
+
+// ServiceStatus is the result of the last reconcile of a Service referencing the config.
+// LastSyncTime is the time the entry last changed, not the time of the last resync.
+type ServiceStatus struct {
+	Name               string      `json:"name"`
+	NodeBalancerID     int         `json:"nodeBalancerID,omitempty"`
+	Error              string      `json:"error,omitempty"`
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	LastSyncTime       metav1.Time `json:"lastSyncTime,omitempty"`
+}
@@ -0,0 +1,117 @@
+package linode
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+// Subsystems of the CCM that can use a Linode API token of their own, configured with
+// LINODE_API_TOKEN_FILE_<SUBSYSTEM> or LINODE_API_TOKEN_<SUBSYSTEM>.
+const (
+	defaultCredential       = "default"
+	loadBalancersCredential = "loadbalancers"
+	instancesCredential     = "instances"
+	routesCredential        = "routes"
+)
+
+// apiCredential is a Linode API token of the CCM along with the client using it.
+type apiCredential struct {
+	// name is the subsystem the token is configured for, or defaultCredential
+	name   string
+	source string
+	client client.Client
+}
+
+func (c apiCredential) String() string {
+	return fmt.Sprintf("%s token from %s", c.name, c.source)
+}
+
+// linodeClients are the Linode API clients of the subsystems of the CCM. Every subsystem uses the
+// default token unless it is configured with a token of its own, so that each token can be
+// limited to the scopes its subsystem needs:
+//   - loadBalancers manages NodeBalancers and their firewalls, and is also used by the
+//     NodeBalancer garbage collector
+//   - instances looks up Linodes for the node controller and the instances of the cloud provider
+//   - routes updates the VPC interfaces of Linodes for the route controller and node IPAM
+//
+// All clients share one rate limiter, the Linode API budget is not split by token.
+type linodeClients struct {
+	defaultClient client.Client
+	loadBalancers client.Client
+	instances     client.Client
+	routes        client.Client
+
+	// credentials are the distinct tokens of the clients, starting with the default token
+	credentials []apiCredential
+}
+
+// newLinodeClients creates the clients of the default token and of every subsystem configured
+// with a token of its own.
+func newLinodeClients(opts *options.Config, timeout time.Duration) (linodeClients, error) {
+	clients := linodeClients{}
+	rateLimitOptions, err := linodeAPIRateLimitOptions(opts)
+	if err != nil {
+		return clients, err
+	}
+	limiter := client.NewRateLimiter(rateLimitOptions)
+	// The clients of all tokens share one cache, so that a write through one of them drops the
+	// reads cached by the others
+	var cache *client.ClientCache
+	if opts.LinodeAPICacheTTL > 0 {
+		cache = client.NewClientCache(opts.LinodeAPICacheTTL)
+	}
+
+	tokenProvider, tokenSource, err := tokenProviderFromFileOrEnv(opts)
+	if err != nil {
+		return clients, err
+	}
+	clients.defaultClient, err = newLinodeClientWithPrometheus(opts, timeout, tokenProvider, limiter, cache)
+	if err != nil {
+		return clients, err
+	}
+	clients.credentials = []apiCredential{{name: defaultCredential, source: tokenSource, client: clients.defaultClient}}
+
+	for _, subsystem := range []struct {
+		name   string
+		client *client.Client
+	}{
+		{name: loadBalancersCredential, client: &clients.loadBalancers},
+		{name: instancesCredential, client: &clients.instances},
+		{name: routesCredential, client: &clients.routes},
+	} {
+		*subsystem.client = clients.defaultClient
+		tokenProvider, tokenSource, err = subsystemTokenProvider(opts, subsystem.name)
+		if err != nil {
+			return clients, err
+		}
+		if tokenProvider == nil {
+			continue
+		}
+		if *subsystem.client, err = newLinodeClientWithPrometheus(opts, timeout, tokenProvider, limiter, cache); err != nil {
+			return clients, err
+		}
+		clients.credentials = append(clients.credentials, apiCredential{name: subsystem.name, source: tokenSource, client: *subsystem.client})
+	}
+	return clients, nil
+}
+
+// subsystemTokenProvider returns a provider of the token configured for the subsystem name, or a
+// nil provider if the subsystem uses the default token.
+func subsystemTokenProvider(opts *options.Config, name string) (client.TokenProvider, string, error) {
+	suffix := "_" + strings.ToUpper(name)
+	fileEnv, tokenEnv := tokenFilePathEnv+suffix, accessTokenEnv+suffix
+
+	if tokenFilePath := strings.TrimSpace(os.Getenv(fileEnv)); tokenFilePath != "" {
+		return tokenProviderFromFile(opts, fileEnv, tokenFilePath, tokenEnv)
+	}
+	if envToken := strings.TrimSpace(os.Getenv(tokenEnv)); envToken != "" {
+		envProvider := staticTokenProvider{token: envToken}
+		return envProvider.GetToken, fmt.Sprintf("environment variable %q", tokenEnv), nil
+	}
+	return nil, "", nil
+}
@@ -0,0 +1,54 @@
+package linode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_newLinodeClients(t *testing.T) {
+	opts := &options.Config{LinodeAPICacheTTL: time.Minute}
+	configureTokenFile(t, "default-token")
+
+	t.Run("subsystems use the default token", func(t *testing.T) {
+		clients, err := newLinodeClients(opts, client.DefaultClientTimeout)
+		require.NoError(t, err)
+		assert.Len(t, clients.credentials, 1)
+		assert.Equal(t, defaultCredential, clients.credentials[0].name)
+		assert.Same(t, clients.defaultClient, clients.loadBalancers)
+		assert.Same(t, clients.defaultClient, clients.instances)
+		assert.Same(t, clients.defaultClient, clients.routes)
+	})
+
+	t.Run("subsystems use their own tokens", func(t *testing.T) {
+		tokenFilePath := filepath.Join(t.TempDir(), "loadbalancers-token")
+		require.NoError(t, os.WriteFile(tokenFilePath, []byte("loadbalancers-token"), 0o600))
+		t.Setenv(tokenFilePathEnv+"_LOADBALANCERS", tokenFilePath)
+		t.Setenv(accessTokenEnv+"_ROUTES", "routes-token")
+
+		clients, err := newLinodeClients(opts, client.DefaultClientTimeout)
+		require.NoError(t, err)
+		require.Len(t, clients.credentials, 3)
+		assert.Equal(t, `loadbalancers token from file "`+tokenFilePath+`"`, clients.credentials[1].String())
+		assert.Equal(t, `routes token from environment variable "LINODE_API_TOKEN_ROUTES"`, clients.credentials[2].String())
+		assert.NotSame(t, clients.defaultClient, clients.loadBalancers)
+		assert.Same(t, clients.loadBalancers, clients.credentials[1].client)
+		assert.Same(t, clients.defaultClient, clients.instances)
+		assert.NotSame(t, clients.defaultClient, clients.routes)
+	})
+
+	t.Run("errors when the token file of a subsystem cannot be read", func(t *testing.T) {
+		t.Setenv(tokenFilePathEnv+"_INSTANCES", filepath.Join(t.TempDir(), "missing-token-file"))
+
+		_, err := newLinodeClients(opts, client.DefaultClientTimeout)
+		require.ErrorContains(t, err, "LINODE_API_TOKEN_FILE_INSTANCES")
+		require.ErrorContains(t, err, "fallback LINODE_API_TOKEN_INSTANCES is not set")
+	})
+}
@@ -0,0 +1,61 @@
+package linode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+)
+
+var (
+	errNoNodesMatchSelector       = errors.New("no nodes match backend node selector")
+	eventNoNodesMatchSelectorName = "nodebalancer-no-nodes-match-selector"
+)
+
+// getBackendNodeSelector parses the backend node selector annotation of service.
+// It returns nil if the annotation is not set.
+func getBackendNodeSelector(service *v1.Service) (labels.Selector, error) {
+	raw, ok := service.GetAnnotations()[annotations.AnnLinodeBackendNodeSelector]
+	if !ok {
+		return nil, nil
+	}
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend node selector %q specified in annotation %q: %w", raw, annotations.AnnLinodeBackendNodeSelector, err)
+	}
+	return selector, nil
+}
+
+// selectBackendNodes returns the nodes that should be NodeBalancer backends of service: those
+// matching its backend node selector, limited to nodes hosting ready endpoints if enabled.
+func (l *loadbalancers) selectBackendNodes(ctx context.Context, service *v1.Service, nodes []*v1.Node) ([]*v1.Node, error) {
+	selector, err := getBackendNodeSelector(service)
+	if err != nil {
+		return nil, err
+	}
+
+	if selector != nil && len(nodes) > 0 {
+		selected := make([]*v1.Node, 0, len(nodes))
+		for _, node := range nodes {
+			if selector.Matches(labels.Set(node.Labels)) {
+				selected = append(selected, node)
+			}
+		}
+		if len(selected) == 0 {
+			l.createNoNodesMatchSelectorEvent(ctx, service, selector)
+			return nil, fmt.Errorf("%w %q: service %s", errNoNodesMatchSelector, selector.String(), getServiceNn(service))
+		}
+		nodes = selected
+	}
+
+	return l.filterNodesByEndpoints(ctx, service, nodes), nil
+}
+
+func (l *loadbalancers) createNoNodesMatchSelectorEvent(ctx context.Context, service *v1.Service, selector labels.Selector) {
+	l.createServiceEvent(ctx, service, eventNoNodesMatchSelectorName, "Warning", "NoNodesMatchSelector",
+		fmt.Sprintf("No nodes match the backend node selector %q, NodeBalancer backends were not updated", selector.String()))
+}
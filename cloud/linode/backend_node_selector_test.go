@@ -0,0 +1,120 @@
+package linode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_selectBackendNodes(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ingress-1", Labels: map[string]string{"pool": "ingress"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ingress-2", Labels: map[string]string{"pool": "ingress", "zone": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"pool": "workers"}}},
+	}
+
+	testcases := []struct {
+		name        string
+		selector    *string
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name:     "no selector",
+			expected: []string{"ingress-1", "ingress-2", "worker-1"},
+		},
+		{
+			name:     "equality selector",
+			selector: ptr.To("pool=ingress"),
+			expected: []string{"ingress-1", "ingress-2"},
+		},
+		{
+			name:     "set based selector",
+			selector: ptr.To("pool in (ingress),zone"),
+			expected: []string{"ingress-2"},
+		},
+		{
+			name:        "no matching nodes",
+			selector:    ptr.To("pool=gpu"),
+			expectedErr: errNoNodesMatchSelector,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeClient := fake.NewClientset()
+			lb := &loadbalancers{kubeClient: kubeClient, options: &options.Config{}}
+			service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: map[string]string{}}}
+			if tc.selector != nil {
+				service.Annotations[annotations.AnnLinodeBackendNodeSelector] = *tc.selector
+			}
+
+			selected, err := lb.selectBackendNodes(t.Context(), service, nodes)
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedErr))
+
+				events, listErr := kubeClient.CoreV1().Events("default").List(t.Context(), metav1.ListOptions{})
+				require.NoError(t, listErr)
+				require.Len(t, events.Items, 1)
+				assert.Equal(t, "NoNodesMatchSelector", events.Items[0].Reason)
+				return
+			}
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, node := range selected {
+				names = append(names, node.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func Test_getBackendNodeSelectorInvalid(t *testing.T) {
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{annotations.AnnLinodeBackendNodeSelector: "pool in ingress"},
+	}}
+	_, err := getBackendNodeSelector(service)
+	assert.Error(t, err)
+}
+
+func Test_serviceResyncController_enqueueBackendNodeSelectorServices(t *testing.T) {
+	kubeClient := fake.NewClientset()
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+	for name, selector := range map[string]string{"ingress": "pool=ingress", "workers": "pool=workers"} {
+		require.NoError(t, serviceInformer.Informer().GetStore().Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{annotations.AnnLinodeBackendNodeSelector: selector},
+			},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			}},
+		}))
+	}
+
+	controller := newServiceResyncController(&loadbalancers{options: &options.Config{}}, serviceInformer, factory.Core().V1().Nodes(), factory.Discovery().V1().EndpointSlices(), kubeClient)
+	defer controller.queue.ShutDown()
+
+	oldNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"pool": "default"}}}
+	newNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"pool": "ingress"}}}
+	controller.enqueueBackendNodeSelectorServices(oldNode, newNode)
+
+	require.Equal(t, 1, controller.queue.Len())
+	key, _ := controller.queue.Get()
+	assert.Equal(t, "default/ingress", key)
+}
@@ -0,0 +1,425 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	cacheResourceNodeBalancer = "nodebalancer"
+	cacheResourceConfig       = "config"
+	cacheResourceNode         = "node"
+	cacheResourceFirewall     = "firewall"
+	cacheResourceVPC          = "vpc"
+	cacheResourceSubnet       = "subnet"
+)
+
+var ClientCacheRequestsCounterVec = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ccm_linode_client_cache_requests_total",
+		Help: "client cache lookups for each resource and whether they were served from the cache",
+	},
+	[]string{"resource", "result"})
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// ClientCache holds the reads cached by ClientWithCache, whose entries expire after a TTL. The
+// clients of all API tokens of the CCM share one, as the tokens belong to the same account: a
+// write through the client of one token then drops the entries read through the others.
+type ClientCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewClientCache returns a cache whose entries expire after ttl
+func NewClientCache(ttl time.Duration) *ClientCache {
+	return &ClientCache{ttl: ttl, now: time.Now, entries: make(map[string]cacheEntry)}
+}
+
+// ClientWithCache implements Client by serving NodeBalancer, config, node, firewall, VPC and
+// subnet reads from a ClientCache. Writes are passed to base and drop the entries they affect,
+// so that reads following a write see its result. Only reads of the first page are cached, and
+// other reads are passed to base.
+//
+// Cached results are shallow copies shared with other callers, which must not modify the slices
+// and pointers they contain.
+type ClientWithCache struct {
+	base  Client
+	cache *ClientCache
+}
+
+var _ Client = (*ClientWithCache)(nil)
+
+// NewClientWithCache returns an instance of the Client that caches reads in cache
+func NewClientWithCache(base Client, cache *ClientCache) *ClientWithCache {
+	return &ClientWithCache{base: base, cache: cache}
+}
+
+// cacheKey returns the key of the result of listing path with opts, and whether it is cached.
+func cacheKey(path string, opts *linodego.ListOptions) (string, bool) {
+	if opts == nil {
+		return path + "?", true
+	}
+	if (opts.PageOptions != nil && opts.Page > 1) || opts.QueryParams != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s?%d&%s", path, opts.PageSize, opts.Filter), true
+}
+
+func (c *ClientCache) lookup(resource, key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok && c.now().Before(entry.expires) {
+		ClientCacheRequestsCounterVec.WithLabelValues(resource, "hit").Inc()
+		return entry.value, true
+	}
+	delete(c.entries, key)
+	ClientCacheRequestsCounterVec.WithLabelValues(resource, "miss").Inc()
+	return nil, false
+}
+
+func (c *ClientCache) store(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{value: value, expires: c.now().Add(c.ttl)}
+}
+
+// invalidate drops the entries of paths and of everything below them.
+func (c *ClientCache) invalidate(paths ...string) {
+	c.invalidateFunc(func(key string) bool {
+		return slices.ContainsFunc(paths, func(path string) bool {
+			return strings.HasPrefix(key, path+"?") || strings.HasPrefix(key, path+"/") || key == path
+		})
+	})
+}
+
+// invalidateFunc drops the entries whose key matches.
+func (c *ClientCache) invalidateFunc(matches func(key string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if matches(key) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// invalidateNodeBalancerFirewalls drops the firewall lists of all NodeBalancers, for writes that
+// change firewall devices without naming the NodeBalancer.
+func (c *ClientCache) invalidateNodeBalancerFirewalls() {
+	c.invalidateFunc(func(key string) bool {
+		return strings.HasPrefix(key, "nodebalancer/") && strings.Contains(key, "/firewalls?")
+	})
+}
+
+// cachedGet returns a copy of the cached result of read for key, calling read on a miss.
+func cachedGet[T any](cache *ClientCache, resource, key string, read func() (*T, error)) (*T, error) {
+	if value, ok := cache.lookup(resource, key); ok {
+		result := value.(T)
+		return &result, nil
+	}
+	result, err := read()
+	if err != nil {
+		return nil, err
+	}
+	cache.store(key, *result)
+	cached := *result
+	return &cached, nil
+}
+
+// cachedList returns a copy of the cached result of read for path and opts, calling read on a
+// miss or when the listing is not cached.
+func cachedList[T any](cache *ClientCache, resource, path string, opts *linodego.ListOptions, read func() ([]T, error)) ([]T, error) {
+	key, ok := cacheKey(path, opts)
+	if !ok {
+		return read()
+	}
+	if value, ok := cache.lookup(resource, key); ok {
+		return slices.Clone(value.([]T)), nil
+	}
+	result, err := read()
+	if err != nil {
+		return nil, err
+	}
+	cache.store(key, slices.Clone(result))
+	return result, nil
+}
+
+func nodeBalancerPath(nodeBalancerID int) string {
+	return fmt.Sprintf("nodebalancer/%d", nodeBalancerID)
+}
+
+func firewallPath(firewallID int) string {
+	return fmt.Sprintf("firewall/%d", firewallID)
+}
+
+// GetInstance implements Client
+func (c *ClientWithCache) GetInstance(ctx context.Context, linodeID int) (*linodego.Instance, error) {
+	return c.base.GetInstance(ctx, linodeID)
+}
+
+// ListInstances implements Client
+func (c *ClientWithCache) ListInstances(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Instance, error) {
+	return c.base.ListInstances(ctx, opts)
+}
+
+// CreateInstance implements Client
+func (c *ClientWithCache) CreateInstance(ctx context.Context, opts linodego.InstanceCreateOptions) (*linodego.Instance, error) {
+	return c.base.CreateInstance(ctx, opts)
+}
+
+// ListInstanceConfigs implements Client
+func (c *ClientWithCache) ListInstanceConfigs(ctx context.Context, linodeID int, opts *linodego.ListOptions) ([]linodego.InstanceConfig, error) {
+	return c.base.ListInstanceConfigs(ctx, linodeID, opts)
+}
+
+// GetInstanceIPAddresses implements Client
+func (c *ClientWithCache) GetInstanceIPAddresses(ctx context.Context, linodeID int) (*linodego.InstanceIPAddressResponse, error) {
+	return c.base.GetInstanceIPAddresses(ctx, linodeID)
+}
+
+// AddInstanceIPAddress implements Client
+func (c *ClientWithCache) AddInstanceIPAddress(ctx context.Context, linodeID int, options linodego.InstanceIPAddOptions) (*linodego.InstanceIP, error) {
+	return c.base.AddInstanceIPAddress(ctx, linodeID, options)
+}
+
+// DeleteInstanceIPAddress implements Client
+func (c *ClientWithCache) DeleteInstanceIPAddress(ctx context.Context, linodeID int, ipAddress string) error {
+	return c.base.DeleteInstanceIPAddress(ctx, linodeID, ipAddress)
+}
+
+// ShareIPAddresses implements Client
+func (c *ClientWithCache) ShareIPAddresses(ctx context.Context, opts linodego.IPAddressesShareOptions) error {
+	return c.base.ShareIPAddresses(ctx, opts)
+}
+
+// UpdateInstanceConfigInterface implements Client
+func (c *ClientWithCache) UpdateInstanceConfigInterface(ctx context.Context, linodeID int, configID int, interfaceID int, opts linodego.InstanceConfigInterfaceUpdateOptions) (*linodego.InstanceConfigInterface, error) {
+	return c.base.UpdateInstanceConfigInterface(ctx, linodeID, configID, interfaceID, opts)
+}
+
+// ListInterfaces implements Client
+func (c *ClientWithCache) ListInterfaces(ctx context.Context, linodeID int, opts *linodego.ListOptions) ([]linodego.LinodeInterface, error) {
+	return c.base.ListInterfaces(ctx, linodeID, opts)
+}
+
+// UpdateInterface implements Client
+func (c *ClientWithCache) UpdateInterface(ctx context.Context, linodeID int, interfaceID int, opts linodego.LinodeInterfaceUpdateOptions) (*linodego.LinodeInterface, error) {
+	return c.base.UpdateInterface(ctx, linodeID, interfaceID, opts)
+}
+
+// GetVPC implements Client
+func (c *ClientWithCache) GetVPC(ctx context.Context, vpcID int) (*linodego.VPC, error) {
+	return cachedGet(c.cache, cacheResourceVPC, fmt.Sprintf("vpc/%d", vpcID), func() (*linodego.VPC, error) {
+		return c.base.GetVPC(ctx, vpcID)
+	})
+}
+
+// GetVPCSubnet implements Client
+func (c *ClientWithCache) GetVPCSubnet(ctx context.Context, vpcID int, subnetID int) (*linodego.VPCSubnet, error) {
+	return cachedGet(c.cache, cacheResourceSubnet, fmt.Sprintf("vpc/%d/subnet/%d", vpcID, subnetID), func() (*linodego.VPCSubnet, error) {
+		return c.base.GetVPCSubnet(ctx, vpcID, subnetID)
+	})
+}
+
+// ListVPCs implements Client
+func (c *ClientWithCache) ListVPCs(ctx context.Context, opts *linodego.ListOptions) ([]linodego.VPC, error) {
+	return cachedList(c.cache, cacheResourceVPC, "vpcs", opts, func() ([]linodego.VPC, error) {
+		return c.base.ListVPCs(ctx, opts)
+	})
+}
+
+// ListVPCIPAddresses implements Client
+func (c *ClientWithCache) ListVPCIPAddresses(ctx context.Context, vpcID int, opts *linodego.ListOptions) ([]linodego.VPCIP, error) {
+	return c.base.ListVPCIPAddresses(ctx, vpcID, opts)
+}
+
+// ListVPCIPv6Addresses implements Client
+func (c *ClientWithCache) ListVPCIPv6Addresses(ctx context.Context, vpcID int, opts *linodego.ListOptions) ([]linodego.VPCIP, error) {
+	return c.base.ListVPCIPv6Addresses(ctx, vpcID, opts)
+}
+
+// ListVPCSubnets implements Client
+func (c *ClientWithCache) ListVPCSubnets(ctx context.Context, vpcID int, opts *linodego.ListOptions) ([]linodego.VPCSubnet, error) {
+	return cachedList(c.cache, cacheResourceSubnet, fmt.Sprintf("vpc/%d/subnets", vpcID), opts, func() ([]linodego.VPCSubnet, error) {
+		return c.base.ListVPCSubnets(ctx, vpcID, opts)
+	})
+}
+
+// CreateNodeBalancer implements Client
+func (c *ClientWithCache) CreateNodeBalancer(ctx context.Context, opts linodego.NodeBalancerCreateOptions) (*linodego.NodeBalancer, error) {
+	defer func() {
+		c.cache.invalidate("nodebalancers")
+		if opts.FirewallID != 0 {
+			c.cache.invalidate(firewallPath(opts.FirewallID))
+		}
+	}()
+	return c.base.CreateNodeBalancer(ctx, opts)
+}
+
+// GetNodeBalancer implements Client
+func (c *ClientWithCache) GetNodeBalancer(ctx context.Context, nodeBalancerID int) (*linodego.NodeBalancer, error) {
+	return cachedGet(c.cache, cacheResourceNodeBalancer, nodeBalancerPath(nodeBalancerID), func() (*linodego.NodeBalancer, error) {
+		return c.base.GetNodeBalancer(ctx, nodeBalancerID)
+	})
+}
+
+// UpdateNodeBalancer implements Client
+func (c *ClientWithCache) UpdateNodeBalancer(ctx context.Context, nodeBalancerID int, opts linodego.NodeBalancerUpdateOptions) (*linodego.NodeBalancer, error) {
+	defer c.cache.invalidate("nodebalancers", nodeBalancerPath(nodeBalancerID))
+	return c.base.UpdateNodeBalancer(ctx, nodeBalancerID, opts)
+}
+
+// DeleteNodeBalancer implements Client
+func (c *ClientWithCache) DeleteNodeBalancer(ctx context.Context, nodeBalancerID int) error {
+	defer func() {
+		c.cache.invalidate("nodebalancers", nodeBalancerPath(nodeBalancerID))
+		// Deleting a NodeBalancer removes it from its firewalls
+		c.cache.invalidateFunc(func(key string) bool { return strings.Contains(key, "/devices?") })
+	}()
+	return c.base.DeleteNodeBalancer(ctx, nodeBalancerID)
+}
+
+// ListNodeBalancers implements Client
+func (c *ClientWithCache) ListNodeBalancers(ctx context.Context, opts *linodego.ListOptions) ([]linodego.NodeBalancer, error) {
+	return cachedList(c.cache, cacheResourceNodeBalancer, "nodebalancers", opts, func() ([]linodego.NodeBalancer, error) {
+		return c.base.ListNodeBalancers(ctx, opts)
+	})
+}
+
+// ListNodeBalancerNodes implements Client
+func (c *ClientWithCache) ListNodeBalancerNodes(ctx context.Context, nodeBalancerID int, configID int, opts *linodego.ListOptions) ([]linodego.NodeBalancerNode, error) {
+	return cachedList(c.cache, cacheResourceNode, fmt.Sprintf("%s/configs/%d/nodes", nodeBalancerPath(nodeBalancerID), configID), opts, func() ([]linodego.NodeBalancerNode, error) {
+		return c.base.ListNodeBalancerNodes(ctx, nodeBalancerID, configID, opts)
+	})
+}
+
+// GetNodeBalancerStats implements Client
+func (c *ClientWithCache) GetNodeBalancerStats(ctx context.Context, nodeBalancerID int) (*linodego.NodeBalancerStats, error) {
+	return c.base.GetNodeBalancerStats(ctx, nodeBalancerID)
+}
+
+// CreateNodeBalancerConfig implements Client
+func (c *ClientWithCache) CreateNodeBalancerConfig(ctx context.Context, nodeBalancerID int, opts linodego.NodeBalancerConfigCreateOptions) (*linodego.NodeBalancerConfig, error) {
+	defer c.cache.invalidate(nodeBalancerPath(nodeBalancerID) + "/configs")
+	return c.base.CreateNodeBalancerConfig(ctx, nodeBalancerID, opts)
+}
+
+// DeleteNodeBalancerConfig implements Client
+func (c *ClientWithCache) DeleteNodeBalancerConfig(ctx context.Context, nodeBalancerID int, configID int) error {
+	defer c.cache.invalidate(nodeBalancerPath(nodeBalancerID) + "/configs")
+	return c.base.DeleteNodeBalancerConfig(ctx, nodeBalancerID, configID)
+}
+
+// ListNodeBalancerConfigs implements Client
+func (c *ClientWithCache) ListNodeBalancerConfigs(ctx context.Context, nodeBalancerID int, opts *linodego.ListOptions) ([]linodego.NodeBalancerConfig, error) {
+	return cachedList(c.cache, cacheResourceConfig, nodeBalancerPath(nodeBalancerID)+"/configs", opts, func() ([]linodego.NodeBalancerConfig, error) {
+		return c.base.ListNodeBalancerConfigs(ctx, nodeBalancerID, opts)
+	})
+}
+
+// RebuildNodeBalancerConfig implements Client
+func (c *ClientWithCache) RebuildNodeBalancerConfig(ctx context.Context, nodeBalancerID int, configID int, opts linodego.NodeBalancerConfigRebuildOptions) (*linodego.NodeBalancerConfig, error) {
+	defer c.cache.invalidate(nodeBalancerPath(nodeBalancerID) + "/configs")
+	return c.base.RebuildNodeBalancerConfig(ctx, nodeBalancerID, configID, opts)
+}
+
+// ListNodeBalancerFirewalls implements Client
+func (c *ClientWithCache) ListNodeBalancerFirewalls(ctx context.Context, nodeBalancerID int, opts *linodego.ListOptions) ([]linodego.Firewall, error) {
+	return cachedList(c.cache, cacheResourceFirewall, nodeBalancerPath(nodeBalancerID)+"/firewalls", opts, func() ([]linodego.Firewall, error) {
+		return c.base.ListNodeBalancerFirewalls(ctx, nodeBalancerID, opts)
+	})
+}
+
+// ListFirewallDevices implements Client
+func (c *ClientWithCache) ListFirewallDevices(ctx context.Context, firewallID int, opts *linodego.ListOptions) ([]linodego.FirewallDevice, error) {
+	return cachedList(c.cache, cacheResourceFirewall, firewallPath(firewallID)+"/devices", opts, func() ([]linodego.FirewallDevice, error) {
+		return c.base.ListFirewallDevices(ctx, firewallID, opts)
+	})
+}
+
+// DeleteFirewallDevice implements Client
+func (c *ClientWithCache) DeleteFirewallDevice(ctx context.Context, firewallID int, deviceID int) error {
+	defer func() {
+		c.cache.invalidate(firewallPath(firewallID) + "/devices")
+		c.cache.invalidateNodeBalancerFirewalls()
+	}()
+	return c.base.DeleteFirewallDevice(ctx, firewallID, deviceID)
+}
+
+// CreateFirewallDevice implements Client
+func (c *ClientWithCache) CreateFirewallDevice(ctx context.Context, firewallID int, opts linodego.FirewallDeviceCreateOptions) (*linodego.FirewallDevice, error) {
+	defer func() {
+		c.cache.invalidate(firewallPath(firewallID) + "/devices")
+		if opts.Type == linodego.FirewallDeviceNodeBalancer {
+			c.cache.invalidate(nodeBalancerPath(opts.ID) + "/firewalls")
+		}
+	}()
+	return c.base.CreateFirewallDevice(ctx, firewallID, opts)
+}
+
+// CreateFirewall implements Client
+func (c *ClientWithCache) CreateFirewall(ctx context.Context, opts linodego.FirewallCreateOptions) (*linodego.Firewall, error) {
+	defer func() {
+		for _, nodeBalancerID := range opts.Devices.NodeBalancers {
+			c.cache.invalidate(nodeBalancerPath(nodeBalancerID) + "/firewalls")
+		}
+	}()
+	return c.base.CreateFirewall(ctx, opts)
+}
+
+// DeleteFirewall implements Client
+func (c *ClientWithCache) DeleteFirewall(ctx context.Context, firewallID int) error {
+	defer func() {
+		c.cache.invalidate(firewallPath(firewallID))
+		c.cache.invalidateNodeBalancerFirewalls()
+	}()
+	return c.base.DeleteFirewall(ctx, firewallID)
+}
+
+// GetFirewall implements Client
+func (c *ClientWithCache) GetFirewall(ctx context.Context, firewallID int) (*linodego.Firewall, error) {
+	return cachedGet(c.cache, cacheResourceFirewall, firewallPath(firewallID), func() (*linodego.Firewall, error) {
+		return c.base.GetFirewall(ctx, firewallID)
+	})
+}
+
+// UpdateFirewallRules implements Client
+func (c *ClientWithCache) UpdateFirewallRules(ctx context.Context, firewallID int, opts linodego.FirewallRulesUpdateOptions) (*linodego.FirewallRules, error) {
+	defer func() {
+		c.cache.invalidate(firewallPath(firewallID))
+		// NodeBalancer firewall lists include the rules of each firewall
+		c.cache.invalidateNodeBalancerFirewalls()
+	}()
+	return c.base.UpdateFirewallRules(ctx, firewallID, opts)
+}
+
+// ReserveIPAddress implements Client
+func (c *ClientWithCache) ReserveIPAddress(ctx context.Context, opts linodego.ReserveIPOptions) (*linodego.InstanceIP, error) {
+	return c.base.ReserveIPAddress(ctx, opts)
+}
+
+// DeleteReservedIPAddress implements Client
+func (c *ClientWithCache) DeleteReservedIPAddress(ctx context.Context, ipAddress string) error {
+	return c.base.DeleteReservedIPAddress(ctx, ipAddress)
+}
+
+// GetProfile implements Client
+func (c *ClientWithCache) GetProfile(ctx context.Context) (*linodego.Profile, error) {
+	return c.base.GetProfile(ctx)
+}
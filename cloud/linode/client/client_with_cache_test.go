@@ -0,0 +1,145 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client/mocks"
+)
+
+func TestClientWithCache(t *testing.T) {
+	t.Run("serves reads from the cache until they expire", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithCache(base, NewClientCache(time.Minute))
+		now := time.Now()
+		client.cache.now = func() time.Time { return now }
+		hits := testutil.ToFloat64(ClientCacheRequestsCounterVec.WithLabelValues(cacheResourceNodeBalancer, "hit"))
+
+		base.EXPECT().GetNodeBalancer(gomock.Any(), 1).Return(&linodego.NodeBalancer{ID: 1}, nil).Times(2)
+		for range 2 {
+			nb, err := client.GetNodeBalancer(t.Context(), 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, nb.ID)
+		}
+		assert.InDelta(t, hits+1, testutil.ToFloat64(ClientCacheRequestsCounterVec.WithLabelValues(cacheResourceNodeBalancer, "hit")), 0)
+
+		now = now.Add(time.Minute)
+		_, err := client.GetNodeBalancer(t.Context(), 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("returns copies of cached results", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithCache(base, NewClientCache(time.Minute))
+
+		base.EXPECT().ListNodeBalancerConfigs(gomock.Any(), 1, nil).Return([]linodego.NodeBalancerConfig{{ID: 2, Port: 80}}, nil)
+		configs, err := client.ListNodeBalancerConfigs(t.Context(), 1, nil)
+		require.NoError(t, err)
+		configs[0].Port = 443
+
+		configs, err = client.ListNodeBalancerConfigs(t.Context(), 1, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 80, configs[0].Port)
+	})
+
+	t.Run("does not cache errors and later pages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithCache(base, NewClientCache(time.Minute))
+
+		gomock.InOrder(
+			base.EXPECT().GetFirewall(gomock.Any(), 1).Return(nil, &linodego.Error{Code: http.StatusNotFound}),
+			base.EXPECT().GetFirewall(gomock.Any(), 1).Return(&linodego.Firewall{ID: 1}, nil),
+		)
+		_, err := client.GetFirewall(t.Context(), 1)
+		require.Error(t, err)
+		_, err = client.GetFirewall(t.Context(), 1)
+		require.NoError(t, err)
+
+		opts := linodego.NewListOptions(2, "")
+		base.EXPECT().ListVPCs(gomock.Any(), opts).Return(nil, nil).Times(2)
+		for range 2 {
+			_, err = client.ListVPCs(t.Context(), opts)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("keys lists by filter", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithCache(base, NewClientCache(time.Minute))
+
+		first := linodego.NewListOptions(0, `{"label":"first"}`)
+		second := linodego.NewListOptions(0, `{"label":"second"}`)
+		base.EXPECT().ListVPCs(gomock.Any(), first).Return([]linodego.VPC{{ID: 1}}, nil)
+		base.EXPECT().ListVPCs(gomock.Any(), second).Return([]linodego.VPC{{ID: 2}}, nil)
+		for range 2 {
+			vpcs, err := client.ListVPCs(t.Context(), first)
+			require.NoError(t, err)
+			assert.Equal(t, 1, vpcs[0].ID)
+			vpcs, err = client.ListVPCs(t.Context(), second)
+			require.NoError(t, err)
+			assert.Equal(t, 2, vpcs[0].ID)
+		}
+	})
+
+	t.Run("writes invalidate affected entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithCache(base, NewClientCache(time.Minute))
+
+		base.EXPECT().ListNodeBalancerConfigs(gomock.Any(), 1, nil).Return(nil, nil).Times(2)
+		base.EXPECT().ListNodeBalancerConfigs(gomock.Any(), 11, nil).Return(nil, nil)
+		base.EXPECT().ListNodeBalancerFirewalls(gomock.Any(), 1, nil).Return(nil, nil).Times(2)
+		base.EXPECT().ListFirewallDevices(gomock.Any(), 3, nil).Return(nil, nil).Times(2)
+		base.EXPECT().RebuildNodeBalancerConfig(gomock.Any(), 1, 2, gomock.Any()).Return(&linodego.NodeBalancerConfig{}, nil)
+		base.EXPECT().CreateFirewallDevice(gomock.Any(), 3, gomock.Any()).Return(&linodego.FirewallDevice{}, nil)
+
+		read := func() {
+			t.Helper()
+			_, err := client.ListNodeBalancerConfigs(t.Context(), 1, nil)
+			require.NoError(t, err)
+			_, err = client.ListNodeBalancerConfigs(t.Context(), 11, nil)
+			require.NoError(t, err)
+			_, err = client.ListNodeBalancerFirewalls(t.Context(), 1, nil)
+			require.NoError(t, err)
+			_, err = client.ListFirewallDevices(t.Context(), 3, nil)
+			require.NoError(t, err)
+		}
+		read()
+		_, err := client.RebuildNodeBalancerConfig(t.Context(), 1, 2, linodego.NodeBalancerConfigRebuildOptions{})
+		require.NoError(t, err)
+		_, err = client.CreateFirewallDevice(t.Context(), 3, linodego.FirewallDeviceCreateOptions{ID: 1, Type: linodego.FirewallDeviceNodeBalancer})
+		require.NoError(t, err)
+		// Only the configs of NodeBalancer 1 and the firewall lists are read again
+		read()
+	})
+
+	t.Run("writes through one client invalidate the entries of clients sharing the cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		cache := NewClientCache(time.Minute)
+		reader := NewClientWithCache(base, cache)
+		writer := NewClientWithCache(base, cache)
+
+		base.EXPECT().GetNodeBalancer(gomock.Any(), 1).Return(&linodego.NodeBalancer{ID: 1}, nil).Times(2)
+		base.EXPECT().UpdateNodeBalancer(gomock.Any(), 1, gomock.Any()).Return(&linodego.NodeBalancer{ID: 1}, nil)
+		for range 2 {
+			_, err := reader.GetNodeBalancer(t.Context(), 1)
+			require.NoError(t, err)
+		}
+		_, err := writer.UpdateNodeBalancer(t.Context(), 1, linodego.NodeBalancerUpdateOptions{})
+		require.NoError(t, err)
+		_, err = reader.GetNodeBalancer(t.Context(), 1)
+		require.NoError(t, err)
+	})
+}
@@ -6,6 +6,9 @@ package client
 
 import (
 	"context"
+	"net/http"
+	"strconv"
+	"time"
 
 	_ "github.com/hexdigest/gowrap"
 	"github.com/linode/linodego/v2"
@@ -14,7 +17,7 @@ import (
 )
 
 // ClientWithPrometheus implements Client interface with all methods wrapped
-// with Prometheus counters
+// with Prometheus counters, latency histograms and in-flight gauges
 type ClientWithPrometheus struct {
 	base Client
 }
@@ -26,6 +29,28 @@ var ClientMethodCounterVec = promauto.NewCounterVec(
 	},
 	[]string{"method", "result"})
 
+var ClientMethodDurationHistogramVec = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "ccm_linode_client_request_duration_seconds",
+		Help:    "client latency of each operation, by HTTP status code or linodego error code",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+	},
+	[]string{"method", "code"})
+
+var ClientMethodRateLimitedCounterVec = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ccm_linode_client_rate_limited_total",
+		Help: "client operations rejected with 429 Too Many Requests",
+	},
+	[]string{"method"})
+
+var ClientMethodInFlightGaugeVec = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_client_requests_in_flight",
+		Help: "client operations in progress",
+	},
+	[]string{"method"})
+
 // NewClientWithPrometheus returns an instance of the Client decorated with prometheus metrics
 func NewClientWithPrometheus(base Client) ClientWithPrometheus {
 	return ClientWithPrometheus{
@@ -35,494 +60,858 @@ func NewClientWithPrometheus(base Client) ClientWithPrometheus {
 
 // AddInstanceIPAddress implements Client
 func (_d ClientWithPrometheus) AddInstanceIPAddress(ctx context.Context, linodeID int, options linodego.InstanceIPAddOptions) (ip1 *linodego.InstanceIP, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("AddInstanceIPAddress").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("AddInstanceIPAddress").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("AddInstanceIPAddress").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("AddInstanceIPAddress", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("AddInstanceIPAddress", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.AddInstanceIPAddress(ctx, linodeID, options)
 }
 
 // CreateFirewall implements Client
 func (_d ClientWithPrometheus) CreateFirewall(ctx context.Context, opts linodego.FirewallCreateOptions) (fp1 *linodego.Firewall, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("CreateFirewall").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("CreateFirewall").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("CreateFirewall").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("CreateFirewall", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("CreateFirewall", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.CreateFirewall(ctx, opts)
 }
 
 // CreateFirewallDevice implements Client
 func (_d ClientWithPrometheus) CreateFirewallDevice(ctx context.Context, firewallID int, opts linodego.FirewallDeviceCreateOptions) (fp1 *linodego.FirewallDevice, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("CreateFirewallDevice").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("CreateFirewallDevice").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("CreateFirewallDevice").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("CreateFirewallDevice", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("CreateFirewallDevice", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.CreateFirewallDevice(ctx, firewallID, opts)
 }
 
 // CreateInstance implements Client
 func (_d ClientWithPrometheus) CreateInstance(ctx context.Context, opts linodego.InstanceCreateOptions) (ip1 *linodego.Instance, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("CreateInstance").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("CreateInstance").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("CreateInstance").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("CreateInstance", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("CreateInstance", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.CreateInstance(ctx, opts)
 }
 
 // CreateNodeBalancer implements Client
 func (_d ClientWithPrometheus) CreateNodeBalancer(ctx context.Context, n1 linodego.NodeBalancerCreateOptions) (np1 *linodego.NodeBalancer, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("CreateNodeBalancer").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("CreateNodeBalancer").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("CreateNodeBalancer").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("CreateNodeBalancer", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("CreateNodeBalancer", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.CreateNodeBalancer(ctx, n1)
 }
 
 // CreateNodeBalancerConfig implements Client
 func (_d ClientWithPrometheus) CreateNodeBalancerConfig(ctx context.Context, i1 int, n1 linodego.NodeBalancerConfigCreateOptions) (np1 *linodego.NodeBalancerConfig, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("CreateNodeBalancerConfig").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("CreateNodeBalancerConfig").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("CreateNodeBalancerConfig").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("CreateNodeBalancerConfig", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("CreateNodeBalancerConfig", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.CreateNodeBalancerConfig(ctx, i1, n1)
 }
 
 // DeleteFirewall implements Client
 func (_d ClientWithPrometheus) DeleteFirewall(ctx context.Context, fwid int) (err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("DeleteFirewall").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("DeleteFirewall").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("DeleteFirewall").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("DeleteFirewall", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("DeleteFirewall", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.DeleteFirewall(ctx, fwid)
 }
 
 // DeleteFirewallDevice implements Client
 func (_d ClientWithPrometheus) DeleteFirewallDevice(ctx context.Context, firewallID int, deviceID int) (err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("DeleteFirewallDevice").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("DeleteFirewallDevice").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("DeleteFirewallDevice").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("DeleteFirewallDevice", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("DeleteFirewallDevice", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.DeleteFirewallDevice(ctx, firewallID, deviceID)
 }
 
 // DeleteInstanceIPAddress implements Client
 func (_d ClientWithPrometheus) DeleteInstanceIPAddress(ctx context.Context, linodeID int, ipAddress string) (err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("DeleteInstanceIPAddress").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("DeleteInstanceIPAddress").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("DeleteInstanceIPAddress").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("DeleteInstanceIPAddress", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("DeleteInstanceIPAddress", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.DeleteInstanceIPAddress(ctx, linodeID, ipAddress)
 }
 
 // DeleteNodeBalancer implements Client
 func (_d ClientWithPrometheus) DeleteNodeBalancer(ctx context.Context, i1 int) (err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("DeleteNodeBalancer").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("DeleteNodeBalancer").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("DeleteNodeBalancer").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("DeleteNodeBalancer", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("DeleteNodeBalancer", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.DeleteNodeBalancer(ctx, i1)
 }
 
 // DeleteNodeBalancerConfig implements Client
 func (_d ClientWithPrometheus) DeleteNodeBalancerConfig(ctx context.Context, i1 int, i2 int) (err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("DeleteNodeBalancerConfig").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("DeleteNodeBalancerConfig").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("DeleteNodeBalancerConfig").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("DeleteNodeBalancerConfig", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("DeleteNodeBalancerConfig", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.DeleteNodeBalancerConfig(ctx, i1, i2)
 }
 
 // DeleteReservedIPAddress implements Client
 func (_d ClientWithPrometheus) DeleteReservedIPAddress(ctx context.Context, ipAddress string) (err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("DeleteReservedIPAddress").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("DeleteReservedIPAddress").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("DeleteReservedIPAddress").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("DeleteReservedIPAddress", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("DeleteReservedIPAddress", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.DeleteReservedIPAddress(ctx, ipAddress)
 }
 
 // GetFirewall implements Client
 func (_d ClientWithPrometheus) GetFirewall(ctx context.Context, i1 int) (fp1 *linodego.Firewall, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetFirewall").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetFirewall").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetFirewall").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("GetFirewall", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetFirewall", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.GetFirewall(ctx, i1)
 }
 
 // GetInstance implements Client
 func (_d ClientWithPrometheus) GetInstance(ctx context.Context, i1 int) (ip1 *linodego.Instance, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetInstance").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetInstance").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetInstance").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("GetInstance", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetInstance", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.GetInstance(ctx, i1)
 }
 
 // GetInstanceIPAddresses implements Client
 func (_d ClientWithPrometheus) GetInstanceIPAddresses(ctx context.Context, i1 int) (ip1 *linodego.InstanceIPAddressResponse, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetInstanceIPAddresses").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetInstanceIPAddresses").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetInstanceIPAddresses").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("GetInstanceIPAddresses", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetInstanceIPAddresses", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.GetInstanceIPAddresses(ctx, i1)
 }
 
 // GetNodeBalancer implements Client
 func (_d ClientWithPrometheus) GetNodeBalancer(ctx context.Context, i1 int) (np1 *linodego.NodeBalancer, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetNodeBalancer").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetNodeBalancer").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetNodeBalancer").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("GetNodeBalancer", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetNodeBalancer", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.GetNodeBalancer(ctx, i1)
 }
 
+// GetNodeBalancerStats implements Client
+func (_d ClientWithPrometheus) GetNodeBalancerStats(ctx context.Context, i1 int) (np1 *linodego.NodeBalancerStats, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetNodeBalancerStats").Inc()
+	start := time.Now()
+	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetNodeBalancerStats").Dec()
+		result := "ok"
+		code := "ok"
+		if err != nil {
+			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetNodeBalancerStats").Inc()
+		}
+
+		ClientMethodCounterVec.WithLabelValues("GetNodeBalancerStats", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetNodeBalancerStats", code).Observe(time.Since(start).Seconds())
+	}()
+	return _d.base.GetNodeBalancerStats(ctx, i1)
+}
+
 // GetProfile implements Client
 func (_d ClientWithPrometheus) GetProfile(ctx context.Context) (pp1 *linodego.Profile, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetProfile").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetProfile").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetProfile").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("GetProfile", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetProfile", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.GetProfile(ctx)
 }
 
 // GetVPC implements Client
 func (_d ClientWithPrometheus) GetVPC(ctx context.Context, i1 int) (vp1 *linodego.VPC, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetVPC").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetVPC").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetVPC").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("GetVPC", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetVPC", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.GetVPC(ctx, i1)
 }
 
 // GetVPCSubnet implements Client
 func (_d ClientWithPrometheus) GetVPCSubnet(ctx context.Context, i1 int, i2 int) (vp1 *linodego.VPCSubnet, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("GetVPCSubnet").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("GetVPCSubnet").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("GetVPCSubnet").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("GetVPCSubnet", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("GetVPCSubnet", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.GetVPCSubnet(ctx, i1, i2)
 }
 
 // ListFirewallDevices implements Client
 func (_d ClientWithPrometheus) ListFirewallDevices(ctx context.Context, firewallID int, opts *linodego.ListOptions) (fa1 []linodego.FirewallDevice, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListFirewallDevices").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListFirewallDevices").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListFirewallDevices").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListFirewallDevices", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListFirewallDevices", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListFirewallDevices(ctx, firewallID, opts)
 }
 
 // ListInstanceConfigs implements Client
 func (_d ClientWithPrometheus) ListInstanceConfigs(ctx context.Context, linodeID int, opts *linodego.ListOptions) (ia1 []linodego.InstanceConfig, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListInstanceConfigs").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListInstanceConfigs").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListInstanceConfigs").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListInstanceConfigs", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListInstanceConfigs", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListInstanceConfigs(ctx, linodeID, opts)
 }
 
 // ListInstances implements Client
 func (_d ClientWithPrometheus) ListInstances(ctx context.Context, lp1 *linodego.ListOptions) (ia1 []linodego.Instance, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListInstances").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListInstances").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListInstances").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListInstances", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListInstances", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListInstances(ctx, lp1)
 }
 
 // ListInterfaces implements Client
 func (_d ClientWithPrometheus) ListInterfaces(ctx context.Context, linodeID int, opts *linodego.ListOptions) (la1 []linodego.LinodeInterface, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListInterfaces").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListInterfaces").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListInterfaces").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListInterfaces", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListInterfaces", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListInterfaces(ctx, linodeID, opts)
 }
 
 // ListNodeBalancerConfigs implements Client
 func (_d ClientWithPrometheus) ListNodeBalancerConfigs(ctx context.Context, i1 int, lp1 *linodego.ListOptions) (na1 []linodego.NodeBalancerConfig, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancerConfigs").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancerConfigs").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListNodeBalancerConfigs").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListNodeBalancerConfigs", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListNodeBalancerConfigs", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListNodeBalancerConfigs(ctx, i1, lp1)
 }
 
 // ListNodeBalancerFirewalls implements Client
 func (_d ClientWithPrometheus) ListNodeBalancerFirewalls(ctx context.Context, nodebalancerID int, opts *linodego.ListOptions) (fa1 []linodego.Firewall, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancerFirewalls").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancerFirewalls").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListNodeBalancerFirewalls").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListNodeBalancerFirewalls", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListNodeBalancerFirewalls", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListNodeBalancerFirewalls(ctx, nodebalancerID, opts)
 }
 
 // ListNodeBalancerNodes implements Client
 func (_d ClientWithPrometheus) ListNodeBalancerNodes(ctx context.Context, i1 int, i2 int, lp1 *linodego.ListOptions) (na1 []linodego.NodeBalancerNode, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancerNodes").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancerNodes").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListNodeBalancerNodes").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListNodeBalancerNodes", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListNodeBalancerNodes", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListNodeBalancerNodes(ctx, i1, i2, lp1)
 }
 
 // ListNodeBalancers implements Client
 func (_d ClientWithPrometheus) ListNodeBalancers(ctx context.Context, lp1 *linodego.ListOptions) (na1 []linodego.NodeBalancer, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancers").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListNodeBalancers").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListNodeBalancers").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListNodeBalancers", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListNodeBalancers", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListNodeBalancers(ctx, lp1)
 }
 
 // ListVPCIPAddresses implements Client
 func (_d ClientWithPrometheus) ListVPCIPAddresses(ctx context.Context, i1 int, lp1 *linodego.ListOptions) (va1 []linodego.VPCIP, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCIPAddresses").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCIPAddresses").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListVPCIPAddresses").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListVPCIPAddresses", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListVPCIPAddresses", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListVPCIPAddresses(ctx, i1, lp1)
 }
 
 // ListVPCIPv6Addresses implements Client
 func (_d ClientWithPrometheus) ListVPCIPv6Addresses(ctx context.Context, i1 int, lp1 *linodego.ListOptions) (va1 []linodego.VPCIP, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCIPv6Addresses").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCIPv6Addresses").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListVPCIPv6Addresses").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListVPCIPv6Addresses", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListVPCIPv6Addresses", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListVPCIPv6Addresses(ctx, i1, lp1)
 }
 
 // ListVPCSubnets implements Client
 func (_d ClientWithPrometheus) ListVPCSubnets(ctx context.Context, i1 int, lp1 *linodego.ListOptions) (va1 []linodego.VPCSubnet, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCSubnets").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCSubnets").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListVPCSubnets").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListVPCSubnets", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListVPCSubnets", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListVPCSubnets(ctx, i1, lp1)
 }
 
 // ListVPCs implements Client
 func (_d ClientWithPrometheus) ListVPCs(ctx context.Context, lp1 *linodego.ListOptions) (va1 []linodego.VPC, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCs").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ListVPCs").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ListVPCs").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ListVPCs", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ListVPCs", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ListVPCs(ctx, lp1)
 }
 
 // RebuildNodeBalancerConfig implements Client
 func (_d ClientWithPrometheus) RebuildNodeBalancerConfig(ctx context.Context, i1 int, i2 int, n1 linodego.NodeBalancerConfigRebuildOptions) (np1 *linodego.NodeBalancerConfig, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("RebuildNodeBalancerConfig").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("RebuildNodeBalancerConfig").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("RebuildNodeBalancerConfig").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("RebuildNodeBalancerConfig", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("RebuildNodeBalancerConfig", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.RebuildNodeBalancerConfig(ctx, i1, i2, n1)
 }
 
 // ReserveIPAddress implements Client
 func (_d ClientWithPrometheus) ReserveIPAddress(ctx context.Context, opts linodego.ReserveIPOptions) (ip1 *linodego.InstanceIP, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ReserveIPAddress").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ReserveIPAddress").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ReserveIPAddress").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ReserveIPAddress", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ReserveIPAddress", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ReserveIPAddress(ctx, opts)
 }
 
 // ShareIPAddresses implements Client
 func (_d ClientWithPrometheus) ShareIPAddresses(ctx context.Context, opts linodego.IPAddressesShareOptions) (err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("ShareIPAddresses").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("ShareIPAddresses").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("ShareIPAddresses").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("ShareIPAddresses", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("ShareIPAddresses", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.ShareIPAddresses(ctx, opts)
 }
 
 // UpdateFirewallRules implements Client
 func (_d ClientWithPrometheus) UpdateFirewallRules(ctx context.Context, i1 int, f1 linodego.FirewallRulesUpdateOptions) (fp1 *linodego.FirewallRules, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("UpdateFirewallRules").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("UpdateFirewallRules").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("UpdateFirewallRules").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("UpdateFirewallRules", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("UpdateFirewallRules", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.UpdateFirewallRules(ctx, i1, f1)
 }
 
 // UpdateInstanceConfigInterface implements Client
 func (_d ClientWithPrometheus) UpdateInstanceConfigInterface(ctx context.Context, i1 int, i2 int, i3 int, i4 linodego.InstanceConfigInterfaceUpdateOptions) (ip1 *linodego.InstanceConfigInterface, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("UpdateInstanceConfigInterface").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("UpdateInstanceConfigInterface").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("UpdateInstanceConfigInterface").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("UpdateInstanceConfigInterface", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("UpdateInstanceConfigInterface", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.UpdateInstanceConfigInterface(ctx, i1, i2, i3, i4)
 }
 
 // UpdateInterface implements Client
 func (_d ClientWithPrometheus) UpdateInterface(ctx context.Context, linodeID int, interfaceID int, opts linodego.LinodeInterfaceUpdateOptions) (lp1 *linodego.LinodeInterface, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("UpdateInterface").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("UpdateInterface").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("UpdateInterface").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("UpdateInterface", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("UpdateInterface", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.UpdateInterface(ctx, linodeID, interfaceID, opts)
 }
 
 // UpdateNodeBalancer implements Client
 func (_d ClientWithPrometheus) UpdateNodeBalancer(ctx context.Context, i1 int, n1 linodego.NodeBalancerUpdateOptions) (np1 *linodego.NodeBalancer, err error) {
+	ClientMethodInFlightGaugeVec.WithLabelValues("UpdateNodeBalancer").Inc()
+	start := time.Now()
 	defer func() {
+		ClientMethodInFlightGaugeVec.WithLabelValues("UpdateNodeBalancer").Dec()
 		result := "ok"
+		code := "ok"
 		if err != nil {
 			result = "error"
+			code = errorCode(err)
+		}
+		if code == strconv.Itoa(http.StatusTooManyRequests) {
+			ClientMethodRateLimitedCounterVec.WithLabelValues("UpdateNodeBalancer").Inc()
 		}
 
 		ClientMethodCounterVec.WithLabelValues("UpdateNodeBalancer", result).Inc()
+		ClientMethodDurationHistogramVec.WithLabelValues("UpdateNodeBalancer", code).Observe(time.Since(start).Seconds())
 	}()
 	return _d.base.UpdateNodeBalancer(ctx, i1, n1)
 }
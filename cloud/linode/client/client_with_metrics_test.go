@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client/mocks"
+)
+
+func TestClientWithPrometheus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	base := mocks.NewMockClient(ctrl)
+	client := NewClientWithPrometheus(base)
+
+	gomock.InOrder(
+		base.EXPECT().GetNodeBalancer(gomock.Any(), 1).DoAndReturn(func(_, _ any) (*linodego.NodeBalancer, error) {
+			assert.InDelta(t, 1, testutil.ToFloat64(ClientMethodInFlightGaugeVec.WithLabelValues("GetNodeBalancer")), 0)
+			return &linodego.NodeBalancer{ID: 1}, nil
+		}),
+		base.EXPECT().GetNodeBalancer(gomock.Any(), 2).Return(nil, &linodego.Error{Code: http.StatusNotFound}),
+		base.EXPECT().GetNodeBalancer(gomock.Any(), 3).Return(nil, linodego.Error{Code: http.StatusTooManyRequests}),
+	)
+
+	_, err := client.GetNodeBalancer(t.Context(), 1)
+	require.NoError(t, err)
+	_, err = client.GetNodeBalancer(t.Context(), 2)
+	require.Error(t, err)
+	_, err = client.GetNodeBalancer(t.Context(), 3)
+	require.Error(t, err)
+
+	assert.InDelta(t, 1, testutil.ToFloat64(ClientMethodCounterVec.WithLabelValues("GetNodeBalancer", "ok")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(ClientMethodCounterVec.WithLabelValues("GetNodeBalancer", "error")), 0)
+	assert.Equal(t, 3, testutil.CollectAndCount(ClientMethodDurationHistogramVec))
+	assert.InDelta(t, 1, testutil.ToFloat64(ClientMethodRateLimitedCounterVec.WithLabelValues("GetNodeBalancer")), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(ClientMethodInFlightGaugeVec.WithLabelValues("GetNodeBalancer")), 0)
+}
+
+func Test_errorCode(t *testing.T) {
+	assert.Equal(t, "404", errorCode(&linodego.Error{Code: http.StatusNotFound}))
+	assert.Equal(t, "429", errorCode(linodego.Error{Code: http.StatusTooManyRequests}))
+	assert.Equal(t, "2", errorCode(linodego.NewError(errors.New("connection refused"))))
+	assert.Equal(t, "unknown", errorCode(errors.New("context canceled")))
+}
@@ -0,0 +1,450 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/linode/linodego/v2"
+)
+
+// PlannedOperation is the kind of change a blocked write would have made.
+type PlannedOperation string
+
+const (
+	PlannedCreate  PlannedOperation = "Create"
+	PlannedUpdate  PlannedOperation = "Update"
+	PlannedRebuild PlannedOperation = "Rebuild"
+	PlannedDelete  PlannedOperation = "Delete"
+
+	redactedValue = "<redacted>"
+)
+
+// plannedNodeBalancer is a NodeBalancer created by the plan. It only exists in the plan, so reads
+// of it are answered from the state built by the planned writes.
+type plannedNodeBalancer struct {
+	nodeBalancer linodego.NodeBalancer
+	configs      []linodego.NodeBalancerConfig
+	// nodes holds the backends of each config, by config ID
+	nodes      map[int][]linodego.NodeBalancerNode
+	firewallID int
+}
+
+// PlannedCall is a write that ClientWithDryRun recorded instead of sending to the Linode API.
+type PlannedCall struct {
+	Operation PlannedOperation `json:"operation"`
+	Method    string           `json:"method"`
+	Target    string           `json:"target,omitempty"`
+	Current   interface{}      `json:"current,omitempty"`
+	Desired   interface{}      `json:"desired,omitempty"`
+}
+
+var _ Client = (*ClientWithDryRun)(nil)
+
+// Calls returns the writes recorded since the last Reset.
+func (_d *ClientWithDryRun) Calls() []PlannedCall {
+	_d.mu.Lock()
+	defer _d.mu.Unlock()
+	return append([]PlannedCall(nil), _d.calls...)
+}
+
+// Reset forgets the recorded writes and the NodeBalancers they created.
+func (_d *ClientWithDryRun) Reset() {
+	_d.mu.Lock()
+	defer _d.mu.Unlock()
+	_d.calls = nil
+	_d.nodeBalancers = nil
+}
+
+// IsPlannedNodeBalancer reports whether the NodeBalancer with id was created by the plan.
+// Planned NodeBalancers get negative IDs, which the Linode API never assigns.
+func (_d *ClientWithDryRun) IsPlannedNodeBalancer(id int) bool {
+	_, ok := _d.plannedNodeBalancer(id)
+	return ok
+}
+
+// plannedNodeBalancer returns a copy of the NodeBalancer with id created by the plan, if any.
+func (_d *ClientWithDryRun) plannedNodeBalancer(id int) (plannedNodeBalancer, bool) {
+	_d.mu.Lock()
+	defer _d.mu.Unlock()
+	planned, ok := _d.nodeBalancers[id]
+	if !ok {
+		return plannedNodeBalancer{}, false
+	}
+	return *planned, true
+}
+
+// updatePlannedNodeBalancer applies update to the NodeBalancer with id if the plan created it.
+func (_d *ClientWithDryRun) updatePlannedNodeBalancer(id int, update func(*plannedNodeBalancer)) {
+	_d.mu.Lock()
+	defer _d.mu.Unlock()
+	if planned, ok := _d.nodeBalancers[id]; ok {
+		update(planned)
+	}
+}
+
+// nextPlannedID returns a negative ID below those in ids for an object created by the plan.
+func nextPlannedID(ids []int) int {
+	id := -1
+	for _, used := range ids {
+		if used <= id {
+			id = used - 1
+		}
+	}
+	return id
+}
+
+// plannedNodes returns the backends built from opts for the config with configID of the
+// NodeBalancer with nodeBalancerID.
+func plannedNodes(nodeBalancerID, configID int, opts []linodego.NodeBalancerNodeCreateOptions) []linodego.NodeBalancerNode {
+	nodes := make([]linodego.NodeBalancerNode, 0, len(opts))
+	for _, node := range opts {
+		nodes = append(nodes, linodego.NodeBalancerNode{
+			Address:        node.Address,
+			Label:          node.Label,
+			Weight:         node.Weight,
+			Mode:           node.Mode,
+			ConfigID:       configID,
+			NodeBalancerID: nodeBalancerID,
+		})
+	}
+	return nodes
+}
+
+// readGetNodeBalancer answers GetNodeBalancer for NodeBalancers created by the plan
+func (_d *ClientWithDryRun) readGetNodeBalancer(ctx context.Context, nodeBalancerID int) (*linodego.NodeBalancer, error) {
+	if planned, ok := _d.plannedNodeBalancer(nodeBalancerID); ok {
+		return &planned.nodeBalancer, nil
+	}
+	return _d.base.GetNodeBalancer(ctx, nodeBalancerID)
+}
+
+// readGetNodeBalancerStats answers GetNodeBalancerStats for NodeBalancers created by the plan,
+// which have not served any traffic
+func (_d *ClientWithDryRun) readGetNodeBalancerStats(ctx context.Context, nodeBalancerID int) (*linodego.NodeBalancerStats, error) {
+	if _, ok := _d.plannedNodeBalancer(nodeBalancerID); ok {
+		return &linodego.NodeBalancerStats{}, nil
+	}
+	return _d.base.GetNodeBalancerStats(ctx, nodeBalancerID)
+}
+
+// readListNodeBalancerConfigs answers ListNodeBalancerConfigs for NodeBalancers created by the plan
+func (_d *ClientWithDryRun) readListNodeBalancerConfigs(ctx context.Context, nodeBalancerID int, opts *linodego.ListOptions) ([]linodego.NodeBalancerConfig, error) {
+	if planned, ok := _d.plannedNodeBalancer(nodeBalancerID); ok {
+		return slices.Clone(planned.configs), nil
+	}
+	return _d.base.ListNodeBalancerConfigs(ctx, nodeBalancerID, opts)
+}
+
+// readListNodeBalancerFirewalls answers ListNodeBalancerFirewalls for NodeBalancers created by the
+// plan, which are attached to the existing firewall they were created with, if any
+func (_d *ClientWithDryRun) readListNodeBalancerFirewalls(ctx context.Context, nodeBalancerID int, opts *linodego.ListOptions) ([]linodego.Firewall, error) {
+	planned, ok := _d.plannedNodeBalancer(nodeBalancerID)
+	if !ok {
+		return _d.base.ListNodeBalancerFirewalls(ctx, nodeBalancerID, opts)
+	}
+	if planned.firewallID == 0 {
+		return []linodego.Firewall{}, nil
+	}
+	firewall, err := _d.base.GetFirewall(ctx, planned.firewallID)
+	if err != nil {
+		return nil, err
+	}
+	return []linodego.Firewall{*firewall}, nil
+}
+
+// readListNodeBalancerNodes answers ListNodeBalancerNodes for NodeBalancers created by the plan
+func (_d *ClientWithDryRun) readListNodeBalancerNodes(ctx context.Context, nodeBalancerID, configID int, opts *linodego.ListOptions) ([]linodego.NodeBalancerNode, error) {
+	if planned, ok := _d.plannedNodeBalancer(nodeBalancerID); ok {
+		return slices.Clone(planned.nodes[configID]), nil
+	}
+	return _d.base.ListNodeBalancerNodes(ctx, nodeBalancerID, configID, opts)
+}
+
+func (_d *ClientWithDryRun) record(operation PlannedOperation, method, target string, current, desired interface{}) {
+	_d.mu.Lock()
+	defer _d.mu.Unlock()
+	_d.calls = append(_d.calls, PlannedCall{
+		Operation: operation,
+		Method:    method,
+		Target:    target,
+		Current:   current,
+		Desired:   desired,
+	})
+}
+
+// redactConfigCreateOptions keeps TLS keys out of recorded calls.
+func redactConfigCreateOptions(opts linodego.NodeBalancerConfigCreateOptions) linodego.NodeBalancerConfigCreateOptions {
+	if opts.SSLKey != "" {
+		opts.SSLKey = redactedValue
+	}
+	return opts
+}
+
+// planCreateInstance records CreateInstance
+func (_d *ClientWithDryRun) planCreateInstance(ctx context.Context, opts linodego.InstanceCreateOptions) (ip1 *linodego.Instance, err error) {
+	_d.record(PlannedCreate, "CreateInstance", "", nil, opts)
+	return &linodego.Instance{Label: opts.Label, Region: opts.Region, Type: opts.Type}, nil
+}
+
+// planAddInstanceIPAddress records AddInstanceIPAddress
+func (_d *ClientWithDryRun) planAddInstanceIPAddress(ctx context.Context, linodeID int, options linodego.InstanceIPAddOptions) (ip1 *linodego.InstanceIP, err error) {
+	_d.record(PlannedCreate, "AddInstanceIPAddress", fmt.Sprintf("instance %d", linodeID), nil, options)
+	return &linodego.InstanceIP{LinodeID: linodeID, Public: options.Public}, nil
+}
+
+// planDeleteInstanceIPAddress records DeleteInstanceIPAddress
+func (_d *ClientWithDryRun) planDeleteInstanceIPAddress(ctx context.Context, linodeID int, ipAddress string) (err error) {
+	_d.record(PlannedDelete, "DeleteInstanceIPAddress", fmt.Sprintf("instance %d address %s", linodeID, ipAddress), nil, nil)
+	return nil
+}
+
+// planShareIPAddresses records ShareIPAddresses
+func (_d *ClientWithDryRun) planShareIPAddresses(ctx context.Context, opts linodego.IPAddressesShareOptions) (err error) {
+	_d.record(PlannedUpdate, "ShareIPAddresses", fmt.Sprintf("instance %d", opts.LinodeID), nil, opts)
+	return nil
+}
+
+// planUpdateInstanceConfigInterface records UpdateInstanceConfigInterface
+func (_d *ClientWithDryRun) planUpdateInstanceConfigInterface(ctx context.Context, i1 int, i2 int, i3 int, i4 linodego.InstanceConfigInterfaceUpdateOptions) (ip1 *linodego.InstanceConfigInterface, err error) {
+	_d.record(PlannedUpdate, "UpdateInstanceConfigInterface", fmt.Sprintf("instance %d config %d interface %d", i1, i2, i3), nil, i4)
+	return &linodego.InstanceConfigInterface{ID: i3}, nil
+}
+
+// planUpdateInterface records UpdateInterface
+func (_d *ClientWithDryRun) planUpdateInterface(ctx context.Context, linodeID int, interfaceID int, opts linodego.LinodeInterfaceUpdateOptions) (lp1 *linodego.LinodeInterface, err error) {
+	_d.record(PlannedUpdate, "UpdateInterface", fmt.Sprintf("instance %d interface %d", linodeID, interfaceID), nil, opts)
+	return &linodego.LinodeInterface{ID: interfaceID}, nil
+}
+
+// planCreateNodeBalancer records CreateNodeBalancer
+func (_d *ClientWithDryRun) planCreateNodeBalancer(ctx context.Context, n1 linodego.NodeBalancerCreateOptions) (np1 *linodego.NodeBalancer, err error) {
+	desired := n1
+	desired.Configs = make([]linodego.NodeBalancerConfigCreateOptions, 0, len(n1.Configs))
+	for _, config := range n1.Configs {
+		desired.Configs = append(desired.Configs, redactConfigCreateOptions(config))
+	}
+	_d.record(PlannedCreate, "CreateNodeBalancer", "", nil, desired)
+
+	// Addresses are only known once the NodeBalancer exists
+	hostname, ipv4 := "", ""
+	if n1.IPv4 != nil {
+		ipv4 = *n1.IPv4
+	}
+	nb := linodego.NodeBalancer{
+		Label:    n1.Label,
+		Region:   n1.Region,
+		Tags:     n1.Tags,
+		Type:     n1.Type,
+		Hostname: &hostname,
+		IPv4:     &ipv4,
+	}
+	if n1.ClientConnThrottle != nil {
+		nb.ClientConnThrottle = *n1.ClientConnThrottle
+	}
+
+	// Later reads and writes of the NodeBalancer by its ID are answered from the plan
+	_d.mu.Lock()
+	defer _d.mu.Unlock()
+	if _d.nodeBalancers == nil {
+		_d.nodeBalancers = make(map[int]*plannedNodeBalancer)
+	}
+	nb.ID = nextPlannedID(slices.Collect(maps.Keys(_d.nodeBalancers)))
+	planned := &plannedNodeBalancer{nodeBalancer: nb, nodes: make(map[int][]linodego.NodeBalancerNode), firewallID: n1.FirewallID}
+	for i, opts := range n1.Configs {
+		config := plannedNodeBalancerConfig(nb.ID, -i-1, opts)
+		planned.configs = append(planned.configs, config)
+		planned.nodes[config.ID] = plannedNodes(nb.ID, config.ID, opts.Nodes)
+	}
+	_d.nodeBalancers[nb.ID] = planned
+	return &nb, nil
+}
+
+// planUpdateNodeBalancer records UpdateNodeBalancer
+func (_d *ClientWithDryRun) planUpdateNodeBalancer(ctx context.Context, i1 int, n1 linodego.NodeBalancerUpdateOptions) (np1 *linodego.NodeBalancer, err error) {
+	current, err := _d.GetNodeBalancer(ctx, i1)
+	if err != nil {
+		return nil, err
+	}
+	_d.record(PlannedUpdate, "UpdateNodeBalancer", fmt.Sprintf("nodebalancer %d", i1), current, n1)
+
+	updated := *current
+	if n1.Label != nil {
+		updated.Label = n1.Label
+	}
+	if n1.ClientConnThrottle != nil {
+		updated.ClientConnThrottle = *n1.ClientConnThrottle
+	}
+	if n1.ClientUDPSessThrottle != nil {
+		updated.ClientUDPSessThrottle = *n1.ClientUDPSessThrottle
+	}
+	if n1.Tags != nil {
+		updated.Tags = n1.Tags
+	}
+	_d.updatePlannedNodeBalancer(i1, func(planned *plannedNodeBalancer) {
+		planned.nodeBalancer = updated
+	})
+	return &updated, nil
+}
+
+// planDeleteNodeBalancer records DeleteNodeBalancer
+func (_d *ClientWithDryRun) planDeleteNodeBalancer(ctx context.Context, i1 int) (err error) {
+	_d.record(PlannedDelete, "DeleteNodeBalancer", fmt.Sprintf("nodebalancer %d", i1), nil, nil)
+
+	_d.mu.Lock()
+	defer _d.mu.Unlock()
+	delete(_d.nodeBalancers, i1)
+	return nil
+}
+
+// planCreateNodeBalancerConfig records CreateNodeBalancerConfig
+func (_d *ClientWithDryRun) planCreateNodeBalancerConfig(ctx context.Context, i1 int, n1 linodego.NodeBalancerConfigCreateOptions) (np1 *linodego.NodeBalancerConfig, err error) {
+	_d.record(PlannedCreate, "CreateNodeBalancerConfig", fmt.Sprintf("nodebalancer %d", i1), nil, redactConfigCreateOptions(n1))
+	config := plannedNodeBalancerConfig(i1, 0, n1)
+	_d.updatePlannedNodeBalancer(i1, func(planned *plannedNodeBalancer) {
+		ids := make([]int, 0, len(planned.configs))
+		for _, existing := range planned.configs {
+			ids = append(ids, existing.ID)
+		}
+		config.ID = nextPlannedID(ids)
+		planned.configs = append(slices.Clone(planned.configs), config)
+	})
+	return &config, nil
+}
+
+// plannedNodeBalancerConfig returns the config with id of the NodeBalancer with nodeBalancerID
+// that opts would create.
+func plannedNodeBalancerConfig(nodeBalancerID, id int, opts linodego.NodeBalancerConfigCreateOptions) linodego.NodeBalancerConfig {
+	return linodego.NodeBalancerConfig{
+		ID:             id,
+		Port:           opts.Port,
+		Protocol:       opts.Protocol,
+		ProxyProtocol:  opts.ProxyProtocol,
+		Algorithm:      opts.Algorithm,
+		Stickiness:     opts.Stickiness,
+		Check:          opts.Check,
+		CheckInterval:  opts.CheckInterval,
+		CheckAttempts:  opts.CheckAttempts,
+		CheckPath:      opts.CheckPath,
+		CheckBody:      opts.CheckBody,
+		CheckTimeout:   opts.CheckTimeout,
+		NodeBalancerID: nodeBalancerID,
+	}
+}
+
+// planDeleteNodeBalancerConfig records DeleteNodeBalancerConfig
+func (_d *ClientWithDryRun) planDeleteNodeBalancerConfig(ctx context.Context, i1 int, i2 int) (err error) {
+	_d.record(PlannedDelete, "DeleteNodeBalancerConfig", fmt.Sprintf("nodebalancer %d config %d", i1, i2), nil, nil)
+	_d.updatePlannedNodeBalancer(i1, func(planned *plannedNodeBalancer) {
+		planned.configs = slices.DeleteFunc(slices.Clone(planned.configs), func(config linodego.NodeBalancerConfig) bool {
+			return config.ID == i2
+		})
+	})
+	return nil
+}
+
+// planRebuildNodeBalancerConfig records RebuildNodeBalancerConfig
+func (_d *ClientWithDryRun) planRebuildNodeBalancerConfig(ctx context.Context, i1 int, i2 int, n1 linodego.NodeBalancerConfigRebuildOptions) (np1 *linodego.NodeBalancerConfig, err error) {
+	// The current config and its nodes are included so the planned rebuild can be compared
+	var current *linodego.NodeBalancerConfig
+	configs, err := _d.ListNodeBalancerConfigs(ctx, i1, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range configs {
+		if configs[i].ID == i2 {
+			current = &configs[i]
+		}
+	}
+	var currentNodes []linodego.NodeBalancerNode
+	if current != nil {
+		if currentNodes, err = _d.ListNodeBalancerNodes(ctx, i1, i2, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	desired := n1
+	if desired.SSLKey != "" {
+		desired.SSLKey = redactedValue
+	}
+	_d.record(PlannedRebuild, "RebuildNodeBalancerConfig", fmt.Sprintf("nodebalancer %d config %d", i1, i2),
+		map[string]interface{}{"config": current, "nodes": currentNodes}, desired)
+
+	rebuilt := linodego.NodeBalancerConfig{
+		ID:             i2,
+		Port:           n1.Port,
+		Protocol:       n1.Protocol,
+		ProxyProtocol:  n1.ProxyProtocol,
+		Algorithm:      n1.Algorithm,
+		Stickiness:     n1.Stickiness,
+		Check:          n1.Check,
+		CheckInterval:  n1.CheckInterval,
+		CheckAttempts:  n1.CheckAttempts,
+		CheckPath:      n1.CheckPath,
+		CheckBody:      n1.CheckBody,
+		CheckTimeout:   n1.CheckTimeout,
+		NodeBalancerID: i1,
+	}
+	_d.updatePlannedNodeBalancer(i1, func(planned *plannedNodeBalancer) {
+		planned.configs = slices.Clone(planned.configs)
+		for i := range planned.configs {
+			if planned.configs[i].ID == i2 {
+				planned.configs[i] = rebuilt
+			}
+		}
+		nodes := make([]linodego.NodeBalancerNodeCreateOptions, 0, len(n1.Nodes))
+		for _, node := range n1.Nodes {
+			nodes = append(nodes, node.NodeBalancerNodeCreateOptions)
+		}
+		planned.nodes = maps.Clone(planned.nodes)
+		planned.nodes[i2] = plannedNodes(i1, i2, nodes)
+	})
+	return &rebuilt, nil
+}
+
+// planDeleteFirewallDevice records DeleteFirewallDevice
+func (_d *ClientWithDryRun) planDeleteFirewallDevice(ctx context.Context, firewallID int, deviceID int) (err error) {
+	_d.record(PlannedDelete, "DeleteFirewallDevice", fmt.Sprintf("firewall %d device %d", firewallID, deviceID), nil, nil)
+	return nil
+}
+
+// planCreateFirewallDevice records CreateFirewallDevice
+func (_d *ClientWithDryRun) planCreateFirewallDevice(ctx context.Context, firewallID int, opts linodego.FirewallDeviceCreateOptions) (fp1 *linodego.FirewallDevice, err error) {
+	_d.record(PlannedCreate, "CreateFirewallDevice", fmt.Sprintf("firewall %d", firewallID), nil, opts)
+	return &linodego.FirewallDevice{Entity: linodego.FirewallDeviceEntity{ID: opts.ID, Type: opts.Type}}, nil
+}
+
+// planCreateFirewall records CreateFirewall
+func (_d *ClientWithDryRun) planCreateFirewall(ctx context.Context, opts linodego.FirewallCreateOptions) (fp1 *linodego.Firewall, err error) {
+	_d.record(PlannedCreate, "CreateFirewall", "", nil, opts)
+	return &linodego.Firewall{Label: opts.Label, Tags: opts.Tags}, nil
+}
+
+// planDeleteFirewall records DeleteFirewall
+func (_d *ClientWithDryRun) planDeleteFirewall(ctx context.Context, fwid int) (err error) {
+	_d.record(PlannedDelete, "DeleteFirewall", fmt.Sprintf("firewall %d", fwid), nil, nil)
+	return nil
+}
+
+// planUpdateFirewallRules records UpdateFirewallRules
+func (_d *ClientWithDryRun) planUpdateFirewallRules(ctx context.Context, i1 int, f1 linodego.FirewallRulesUpdateOptions) (fp1 *linodego.FirewallRules, err error) {
+	current, err := _d.base.GetFirewall(ctx, i1)
+	if err != nil {
+		return nil, err
+	}
+	_d.record(PlannedUpdate, "UpdateFirewallRules", fmt.Sprintf("firewall %d", i1), current.Rules, f1)
+	return &linodego.FirewallRules{}, nil
+}
+
+// planReserveIPAddress records ReserveIPAddress
+func (_d *ClientWithDryRun) planReserveIPAddress(ctx context.Context, opts linodego.ReserveIPOptions) (ip1 *linodego.InstanceIP, err error) {
+	_d.record(PlannedCreate, "ReserveIPAddress", "", nil, opts)
+	return &linodego.InstanceIP{Region: opts.Region, Reserved: true, Public: true}, nil
+}
+
+// planDeleteReservedIPAddress records DeleteReservedIPAddress
+func (_d *ClientWithDryRun) planDeleteReservedIPAddress(ctx context.Context, ipAddress string) (err error) {
+	_d.record(PlannedDelete, "DeleteReservedIPAddress", fmt.Sprintf("address %s", ipAddress), nil, nil)
+	return nil
+}
@@ -0,0 +1,17 @@
+package client
+
+import (
+	"errors"
+	"strconv"
+)
+
+// errorCode returns the HTTP status code of a failed Linode API call, or the linodego error code
+// identifying why no response was received, for labelling metrics. Errors that do not come from
+// linodego are labelled "unknown".
+func errorCode(err error) string {
+	var coded interface{ StatusCode() int }
+	if errors.As(err, &coded) {
+		return strconv.Itoa(coded.StatusCode())
+	}
+	return "unknown"
+}
@@ -269,6 +269,21 @@ func (mr *MockClientMockRecorder) GetNodeBalancer(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeBalancer", reflect.TypeOf((*MockClient)(nil).GetNodeBalancer), arg0, arg1)
 }
 
+// GetNodeBalancerStats mocks base method.
+func (m *MockClient) GetNodeBalancerStats(arg0 context.Context, arg1 int) (*linodego.NodeBalancerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeBalancerStats", arg0, arg1)
+	ret0, _ := ret[0].(*linodego.NodeBalancerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeBalancerStats indicates an expected call of GetNodeBalancerStats.
+func (mr *MockClientMockRecorder) GetNodeBalancerStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeBalancerStats", reflect.TypeOf((*MockClient)(nil).GetNodeBalancerStats), arg0, arg1)
+}
+
 // GetProfile mocks base method.
 func (m *MockClient) GetProfile(arg0 context.Context) (*linodego.Profile, error) {
 	m.ctrl.T.Helper()
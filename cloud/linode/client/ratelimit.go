@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+)
+
+const (
+	retryAfterHeader      = "Retry-After"
+	maintenanceModeHeader = "X-Maintenance-Mode"
+)
+
+// RateLimitOptions configures the RateLimiter of ClientWithRateLimit.
+type RateLimitOptions struct {
+	// RequestsPerSecond and Burst size the token bucket shared by all calls. A RequestsPerSecond
+	// of 0 disables it.
+	RequestsPerSecond float64
+	Burst             int
+	// MethodRequestsPerSecond sizes an additional token bucket for the calls of each named Client
+	// method, so that calls to endpoints with tighter limits cannot use up the shared budget.
+	MethodRequestsPerSecond map[string]float64
+
+	// MaxRetries is how many times a call rejected as rate limited or busy is retried.
+	MaxRetries int
+	// RetryBaseDelay is the delay before the first retry, doubled for every further retry up to
+	// RetryMaxDelay. A Retry-After header sent by the Linode API takes precedence.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// RateLimiter throttles the calls of ClientWithRateLimit with token buckets and decides whether
+// rejected calls are retried.
+type RateLimiter struct {
+	shared  flowcontrol.RateLimiter
+	methods map[string]flowcontrol.RateLimiter
+	opts    RateLimitOptions
+}
+
+// NewRateLimiter returns a RateLimiter with the token buckets and retry policy of opts.
+func NewRateLimiter(opts RateLimitOptions) *RateLimiter {
+	r := &RateLimiter{methods: make(map[string]flowcontrol.RateLimiter, len(opts.MethodRequestsPerSecond)), opts: opts}
+	if opts.RequestsPerSecond > 0 {
+		r.shared = flowcontrol.NewTokenBucketRateLimiter(float32(opts.RequestsPerSecond), max(opts.Burst, 1))
+	}
+	for method, rps := range opts.MethodRequestsPerSecond {
+		r.methods[method] = flowcontrol.NewTokenBucketRateLimiter(float32(rps), int(math.Max(math.Ceil(rps), 1)))
+	}
+	return r
+}
+
+// Wait blocks until a call of method fits the budget of method and the shared budget.
+func (r *RateLimiter) Wait(ctx context.Context, method string) error {
+	if limiter, ok := r.methods[method]; ok {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if r.shared != nil {
+		return r.shared.Wait(ctx)
+	}
+	return nil
+}
+
+// Retry reports whether a call of method that failed with err should be attempted again, after
+// waiting for the delay requested by the Linode API or the backoff of the attempt. attempt counts
+// the retries made so far.
+func (r *RateLimiter) Retry(ctx context.Context, method string, attempt int, err error) bool {
+	if err == nil || attempt >= r.opts.MaxRetries || !isRetryable(err) {
+		return false
+	}
+
+	delay := r.backoff(attempt)
+	if retryAfter, ok := retryAfter(err); ok {
+		delay = min(retryAfter, r.opts.RetryMaxDelay)
+	}
+	klog.V(3).Infof("Linode API call %s failed with %s, retrying in %s", method, err, delay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (r *RateLimiter) backoff(attempt int) time.Duration {
+	delay := r.opts.RetryBaseDelay
+	for range attempt {
+		delay *= 2
+		if delay >= r.opts.RetryMaxDelay {
+			return r.opts.RetryMaxDelay
+		}
+	}
+	return min(delay, r.opts.RetryMaxDelay)
+}
+
+// isRetryable reports whether err is a response the Linode API expects to be retried: rate
+// limiting, busy Linodes, request timeouts and service unavailability outside of maintenance.
+// These are the responses linodego retries on its own unless its retries are disabled.
+func isRetryable(err error) bool {
+	var apiErr *linodego.Error
+	if !errors.As(err, &apiErr) {
+		var valueErr linodego.Error
+		if !errors.As(err, &valueErr) {
+			return false
+		}
+		apiErr = &valueErr
+	}
+
+	switch apiErr.Code {
+	case http.StatusTooManyRequests, http.StatusRequestTimeout:
+		return true
+	case http.StatusServiceUnavailable:
+		return apiErr.Response == nil || apiErr.Response.Header.Get(maintenanceModeHeader) == ""
+	case http.StatusBadRequest:
+		return apiErr.Message == "Linode busy."
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by the Retry-After header of the response that failed
+// with err.
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *linodego.Error
+	if !errors.As(err, &apiErr) || apiErr.Response == nil {
+		return 0, false
+	}
+	seconds, parseErr := strconv.Atoi(apiErr.Response.Header.Get(retryAfterHeader))
+	if parseErr != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
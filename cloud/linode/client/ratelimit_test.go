@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client/mocks"
+)
+
+func responseError(code int, header http.Header) *linodego.Error {
+	return &linodego.Error{Code: code, Response: &http.Response{StatusCode: code, Header: header}}
+}
+
+func TestClientWithRateLimit(t *testing.T) {
+	opts := RateLimitOptions{MaxRetries: 2, RetryBaseDelay: time.Millisecond, RetryMaxDelay: 10 * time.Millisecond}
+
+	t.Run("retries rate limited calls", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithRateLimit(base, NewRateLimiter(opts))
+
+		gomock.InOrder(
+			base.EXPECT().GetNodeBalancer(gomock.Any(), 1).Return(nil, responseError(http.StatusTooManyRequests, http.Header{"Retry-After": {"0"}})),
+			base.EXPECT().GetNodeBalancer(gomock.Any(), 1).Return(&linodego.NodeBalancer{ID: 1}, nil),
+		)
+		nb, err := client.GetNodeBalancer(t.Context(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, nb.ID)
+	})
+
+	t.Run("gives up after the maximum retries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithRateLimit(base, NewRateLimiter(opts))
+
+		base.EXPECT().DeleteNodeBalancer(gomock.Any(), 1).Return(responseError(http.StatusTooManyRequests, nil)).Times(3)
+		err := client.DeleteNodeBalancer(t.Context(), 1)
+		assert.True(t, linodego.ErrHasStatus(err, http.StatusTooManyRequests))
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		client := NewClientWithRateLimit(base, NewRateLimiter(opts))
+
+		base.EXPECT().GetNodeBalancer(gomock.Any(), 1).Return(nil, responseError(http.StatusNotFound, nil))
+		_, err := client.GetNodeBalancer(t.Context(), 1)
+		assert.True(t, linodego.IsNotFound(err))
+	})
+
+	t.Run("waits for the method budget", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		base := mocks.NewMockClient(ctrl)
+		limiter := NewRateLimiter(RateLimitOptions{MethodRequestsPerSecond: map[string]float64{"ListInstances": 0.001}})
+		client := NewClientWithRateLimit(base, limiter)
+
+		base.EXPECT().ListInstances(gomock.Any(), nil).Return(nil, nil)
+		base.EXPECT().GetInstance(gomock.Any(), 1).Return(&linodego.Instance{ID: 1}, nil)
+		_, err := client.ListInstances(t.Context(), nil)
+		require.NoError(t, err)
+
+		// The budget of ListInstances is used up, other methods are not affected
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+		_, err = client.ListInstances(ctx, nil)
+		require.Error(t, err)
+		_, err = client.GetInstance(t.Context(), 1)
+		require.NoError(t, err)
+	})
+}
+
+func Test_isRetryable(t *testing.T) {
+	testcases := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "rate limited", err: responseError(http.StatusTooManyRequests, nil), retryable: true},
+		{name: "rate limited without response", err: linodego.Error{Code: http.StatusTooManyRequests}, retryable: true},
+		{name: "service unavailable", err: responseError(http.StatusServiceUnavailable, http.Header{}), retryable: true},
+		{name: "maintenance", err: responseError(http.StatusServiceUnavailable, http.Header{"X-Maintenance-Mode": {"1"}})},
+		{name: "linode busy", err: &linodego.Error{Code: http.StatusBadRequest, Message: "Linode busy."}, retryable: true},
+		{name: "bad request", err: &linodego.Error{Code: http.StatusBadRequest, Message: "[label] invalid"}},
+		{name: "not found", err: responseError(http.StatusNotFound, nil)},
+		{name: "context canceled", err: context.Canceled},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.retryable, isRetryable(tc.err))
+		})
+	}
+}
+
+func TestRateLimiter_backoff(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitOptions{RetryBaseDelay: time.Second, RetryMaxDelay: 5 * time.Second})
+	assert.Equal(t, time.Second, limiter.backoff(0))
+	assert.Equal(t, 4*time.Second, limiter.backoff(2))
+	assert.Equal(t, 5*time.Second, limiter.backoff(3))
+	assert.Equal(t, 5*time.Second, limiter.backoff(100))
+}
@@ -3,8 +3,8 @@ package linode
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"golang.org/x/exp/slices"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
@@ -28,7 +29,6 @@ const (
 	regionEnv                = "LINODE_REGION"
 	tokenFilePathEnv         = "LINODE_API_TOKEN_FILE"
 	defaultTokenFilePath     = "/var/run/secrets/linode/api-token"
-	tokenCacheTTLEnv         = "LINODE_API_TOKEN_CACHE_TTL_SECONDS"
 	defaultTokenFileCacheTTL = time.Minute
 	ciliumLBType             = "cilium-bgp"
 	nodeBalancerLBType       = "nodebalancer"
@@ -38,22 +38,23 @@ const (
 var supportedLoadBalancerTypes = []string{nodeBalancerLBType}
 
 type linodeCloud struct {
-	client                   client.Client
-	instances                cloudprovider.InstancesV2
+	clients                  linodeClients
+	options                  *options.Config
+	instances                *services.Instances
 	loadbalancers            cloudprovider.LoadBalancer
 	routes                   cloudprovider.Routes
+	vpcs                     *services.VPCCache
+	k8sNodes                 *k8sNodeCache
 	linodeTokenHealthChecker *healthChecker
+	configReloader           *configReloader
 }
 
-var (
-	instanceCache               *services.Instances
-	NodeBalancerPrefixCharLimit int = 19
-)
+var NodeBalancerPrefixCharLimit int = 19
 
 type tokenFileProvider struct {
-	path     string
-	now      func() time.Time
-	cacheTTL time.Duration
+	path    string
+	now     func() time.Time
+	options *options.Config
 
 	mu          sync.RWMutex
 	cachedToken string
@@ -86,7 +87,7 @@ func (t *tokenFileProvider) nowTime() time.Time {
 
 func (t *tokenFileProvider) GetToken(_ context.Context) (string, error) {
 	now := t.nowTime()
-	cacheTTL := t.cacheTTL
+	cacheTTL := t.options.Current().LinodeAPITokenCacheTTL
 	if cacheTTL <= 0 {
 		cacheTTL = defaultTokenFileCacheTTL
 	}
@@ -119,48 +120,96 @@ func (t *tokenFileProvider) GetToken(_ context.Context) (string, error) {
 
 func init() {
 	registerMetrics()
-	cloudprovider.RegisterCloudProvider(
-		ProviderName,
-		func(io.Reader) (cloudprovider.Interface, error) {
-			return newCloud()
-		})
+}
+
+// NewCloud creates the Linode cloud provider from opts, which are completed with the
+// configuration file at configFile, which may be empty, and the environment. opts are owned by
+// the cloud provider afterwards. Changes of the reloadable settings in the configuration file are
+// put into effect every opts.ConfigReloadInterval once the cloud provider is initialized.
+func NewCloud(opts *options.Config, configFile string) (cloudprovider.Interface, error) {
+	reloader, err := newConfigReloader(opts, configFile)
+	if err != nil {
+		return nil, err
+	}
+	cloud, err := newCloud(opts)
+	if err != nil {
+		return nil, err
+	}
+	cloud.(*linodeCloud).configReloader = reloader
+	return cloud, nil
 }
 
 // newLinodeClientWithPrometheus creates a new client kept in its own local
-// scope and returns an instrumented one that should be used and passed around
-func newLinodeClientWithPrometheus(timeout time.Duration, tokenProvider client.TokenProvider) (client.Client, error) {
+// scope and returns an instrumented one rate limited by limiter that should be used
+// and passed around, so that all controllers share the same API budget. Reads are
+// cached in cache unless it is nil.
+func newLinodeClientWithPrometheus(opts *options.Config, timeout time.Duration, tokenProvider client.TokenProvider, limiter *client.RateLimiter, cache *client.ClientCache) (client.Client, error) {
 	linodeClient, err := client.New(timeout, tokenProvider)
 	if err != nil {
 		return nil, fmt.Errorf("client was not created successfully: %w", err)
 	}
 
-	if options.Options.LinodeGoDebug {
+	if opts.LinodeGoDebug {
 		linodeClient.SetDebug(true)
 	}
+	// ClientWithRateLimit retries rejected calls with backoff, linodego would otherwise retry
+	// them on its own before they reach it
+	linodeClient.SetRetryCount(0)
 
-	return client.NewClientWithPrometheus(linodeClient), nil
+	var linodeAPIClient client.Client = client.NewClientWithRateLimit(client.NewClientWithPrometheus(linodeClient), limiter)
+	if cache != nil {
+		// Reads served from the cache are neither rate limited nor counted as API calls
+		linodeAPIClient = client.NewClientWithCache(linodeAPIClient, cache)
+	}
+	return linodeAPIClient, nil
 }
 
-func tokenFileCacheTTLFromEnv() time.Duration {
-	tokenCacheTTL := defaultTokenFileCacheTTL
-	if raw, ok := os.LookupEnv(tokenCacheTTLEnv); ok {
-		if ttlSeconds, err := strconv.Atoi(raw); err == nil && ttlSeconds > 0 {
-			tokenCacheTTL = time.Duration(ttlSeconds) * time.Second
-		}
+// linodeAPIRateLimitOptions validates the rate limiting, retry and caching flags of the Linode client.
+func linodeAPIRateLimitOptions(o *options.Config) (client.RateLimitOptions, error) {
+	opts := client.RateLimitOptions{
+		RequestsPerSecond:       o.LinodeAPIRequestsPerSecond,
+		Burst:                   o.LinodeAPIBurst,
+		MethodRequestsPerSecond: make(map[string]float64, len(o.LinodeAPIMethodRequestsPerSecond)),
+		MaxRetries:              o.LinodeAPIMaxRetries,
+		RetryBaseDelay:          o.LinodeAPIRetryBaseDelay,
+		RetryMaxDelay:           o.LinodeAPIRetryMaxDelay,
+	}
+	if o.LinodeAPICacheTTL < 0 {
+		return opts, fmt.Errorf("linode-api-cache-ttl must not be negative")
+	}
+	if opts.RequestsPerSecond < 0 || opts.MaxRetries < 0 || opts.RetryBaseDelay < 0 || opts.RetryMaxDelay < opts.RetryBaseDelay {
+		return opts, fmt.Errorf("linode-api-requests-per-second and linode-api-max-retries must not be negative and linode-api-retry-max-delay must not be below linode-api-retry-base-delay")
 	}
 
-	return tokenCacheTTL
+	clientType := reflect.TypeFor[client.Client]()
+	for method, raw := range o.LinodeAPIMethodRequestsPerSecond {
+		if _, ok := clientType.MethodByName(method); !ok {
+			return opts, fmt.Errorf("unknown client method %q in linode-api-method-requests-per-second", method)
+		}
+		rps, err := strconv.ParseFloat(raw, 64)
+		if err != nil || rps <= 0 {
+			return opts, fmt.Errorf("invalid budget %q for %s in linode-api-method-requests-per-second, must be a positive number of requests per second", raw, method)
+		}
+		opts.MethodRequestsPerSecond[method] = rps
+	}
+	return opts, nil
 }
 
-func tokenProviderFromFileOrEnv() (client.TokenProvider, string, error) {
+func tokenProviderFromFileOrEnv(opts *options.Config) (client.TokenProvider, string, error) {
 	tokenFilePath := strings.TrimSpace(os.Getenv(tokenFilePathEnv))
 	if tokenFilePath == "" {
 		tokenFilePath = defaultTokenFilePath
 	}
+	return tokenProviderFromFile(opts, tokenFilePathEnv, tokenFilePath, accessTokenEnv)
+}
 
+// tokenProviderFromFile returns a provider of the token in the file at tokenFilePath, which is
+// configured with the environment variable fileEnv, falling back to the token in the environment
+// variable tokenEnv if the file cannot be read.
+func tokenProviderFromFile(opts *options.Config, fileEnv, tokenFilePath, tokenEnv string) (client.TokenProvider, string, error) {
 	fileProvider := tokenFileProvider{
-		path:     tokenFilePath,
-		cacheTTL: tokenFileCacheTTLFromEnv(),
+		path:    tokenFilePath,
+		options: opts,
 	}
 
 	_, fileErr := fileProvider.GetToken(context.Background())
@@ -168,123 +217,149 @@ func tokenProviderFromFileOrEnv() (client.TokenProvider, string, error) {
 		return fileProvider.GetToken, fmt.Sprintf("file %q", fileProvider.String()), nil
 	}
 
-	if envToken := strings.TrimSpace(os.Getenv(accessTokenEnv)); envToken != "" {
+	if envToken := strings.TrimSpace(os.Getenv(tokenEnv)); envToken != "" {
 		envProvider := staticTokenProvider{token: envToken}
-		return envProvider.GetToken, fmt.Sprintf("environment variable %q", accessTokenEnv), nil
+		return envProvider.GetToken, fmt.Sprintf("environment variable %q", tokenEnv), nil
 	}
 
-	return nil, "", fmt.Errorf("failed to load linode api token from %s=%q: %w; fallback %s is not set", tokenFilePathEnv, tokenFilePath, fileErr, accessTokenEnv)
+	return nil, "", fmt.Errorf("failed to load linode api token from %s=%q: %w; fallback %s is not set", fileEnv, tokenFilePath, fileErr, tokenEnv)
 }
 
-func newCloud() (cloudprovider.Interface, error) {
+func newCloud(opts *options.Config) (cloudprovider.Interface, error) {
 	region := os.Getenv(regionEnv)
 	if region == "" {
 		return nil, fmt.Errorf("%s must be set in the environment (use a k8s secret)", regionEnv)
 	}
 
-	tokenProvider, tokenSourceDescription, err := tokenProviderFromFileOrEnv()
-	if err != nil {
-		return nil, err
-	}
-
 	// set timeout used by linodeclient for API calls
 	timeout := client.DefaultClientTimeout
-	if raw, ok := os.LookupEnv("LINODE_REQUEST_TIMEOUT_SECONDS"); ok {
-		if t, atoiErr := strconv.Atoi(raw); atoiErr == nil && t > 0 {
-			timeout = time.Duration(t) * time.Second
-		}
+	if opts.LinodeAPIRequestTimeout > 0 {
+		timeout = opts.LinodeAPIRequestTimeout
 	}
 
-	linodeClient, err := newLinodeClientWithPrometheus(timeout, tokenProvider)
+	clients, err := newLinodeClients(opts, timeout)
 	if err != nil {
 		return nil, err
 	}
 
 	var healthChecker *healthChecker
 
-	if options.Options.EnableTokenHealthChecker {
-		var authenticated bool
-		authenticated, err = client.CheckClientAuthenticated(context.TODO(), linodeClient)
-		if err != nil {
-			return nil, fmt.Errorf("linode client authenticated connection error: %w", err)
+	if opts.EnableTokenHealthChecker {
+		for _, credential := range clients.credentials {
+			var authenticated bool
+			authenticated, err = client.CheckClientAuthenticated(context.TODO(), credential.client)
+			if err != nil {
+				return nil, fmt.Errorf("linode client authenticated connection error for %s: %w", credential, err)
+			}
+
+			if !authenticated {
+				return nil, fmt.Errorf("linode api %s is invalid", credential)
+			}
 		}
 
-		if !authenticated {
-			return nil, fmt.Errorf("linode api token from %s is invalid", tokenSourceDescription)
-		}
-
-		healthChecker = newHealthChecker(linodeClient, tokenHealthCheckPeriod, options.Options.GlobalStopChannel)
+		healthChecker = newHealthChecker(clients.credentials, tokenHealthCheckPeriod, opts.GlobalStopChannel)
 	}
 
-	err = services.ValidateAndSetVPCSubnetFlags(linodeClient)
+	vpcs := services.NewVPCCache(opts)
+	err = vpcs.ValidateAndSetVPCSubnetFlags(clients.routes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to validate VPC and subnet flags: %w", err)
 	}
 
-	if options.Options.NodeBalancerBackendIPv4SubnetID != 0 && options.Options.NodeBalancerBackendIPv4SubnetName != "" {
+	if opts.NodeBalancerBackendIPv4SubnetID != 0 && opts.NodeBalancerBackendIPv4SubnetName != "" {
 		return nil, fmt.Errorf("cannot have both --nodebalancer-backend-ipv4-subnet-id and --nodebalancer-backend-ipv4-subnet-name set")
 	}
 
-	if options.Options.DisableNodeBalancerVPCBackends {
+	if opts.DisableNodeBalancerVPCBackends {
 		klog.Infof("NodeBalancer VPC backends are disabled, no VPC backends will be created for NodeBalancers")
-		options.Options.NodeBalancerBackendIPv4SubnetID = 0
-		options.Options.NodeBalancerBackendIPv4SubnetName = ""
-	} else if options.Options.NodeBalancerBackendIPv4SubnetName != "" {
-		options.Options.NodeBalancerBackendIPv4SubnetID, err = services.GetNodeBalancerBackendIPv4SubnetID(linodeClient)
+		opts.NodeBalancerBackendIPv4SubnetID = 0
+		opts.NodeBalancerBackendIPv4SubnetName = ""
+	} else if opts.NodeBalancerBackendIPv4SubnetName != "" {
+		opts.NodeBalancerBackendIPv4SubnetID, err = vpcs.GetNodeBalancerBackendIPv4SubnetID(clients.loadBalancers)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get backend IPv4 subnet ID for subnet name %s: %w", options.Options.NodeBalancerBackendIPv4SubnetName, err)
+			return nil, fmt.Errorf("failed to get backend IPv4 subnet ID for subnet name %s: %w", opts.NodeBalancerBackendIPv4SubnetName, err)
 		}
-		klog.Infof("Using NodeBalancer backend IPv4 subnet ID %d for subnet name %s", options.Options.NodeBalancerBackendIPv4SubnetID, options.Options.NodeBalancerBackendIPv4SubnetName)
+		klog.Infof("Using NodeBalancer backend IPv4 subnet ID %d for subnet name %s", opts.NodeBalancerBackendIPv4SubnetID, opts.NodeBalancerBackendIPv4SubnetName)
 	}
 
-	instanceCache = services.NewInstances(linodeClient)
-	routes, err := newRoutes(linodeClient, instanceCache)
+	instanceCache := services.NewInstances(clients.instances, opts, vpcs)
+	k8sNodes := newK8sNodeCache(opts)
+	routes, err := newRoutes(clients.routes, instanceCache, opts, vpcs, k8sNodes)
 	if err != nil {
 		return nil, fmt.Errorf("routes client was not created successfully: %w", err)
 	}
 
-	if options.Options.LoadBalancerType == ciliumLBType {
+	if opts.LoadBalancerType == ciliumLBType {
 		klog.Warningf("--load-balancer-type=%s is deprecated and has no effect; using %s", ciliumLBType, nodeBalancerLBType)
-		options.Options.LoadBalancerType = nodeBalancerLBType
+		opts.LoadBalancerType = nodeBalancerLBType
 	}
 
-	if options.Options.BGPNodeSelector != "" {
+	if opts.BGPNodeSelector != "" {
 		klog.Warning("--bgp-node-selector is deprecated and has no effect; it is retained for backwards compatibility")
 	}
 
-	if options.Options.IpHolderSuffix != "" {
+	if opts.IpHolderSuffix != "" {
 		klog.Warning("--ip-holder-suffix is deprecated and has no effect; it is retained for backwards compatibility")
 	}
 
-	if options.Options.LoadBalancerType != "" && !slices.Contains(supportedLoadBalancerTypes, options.Options.LoadBalancerType) {
+	if opts.LoadBalancerType != "" && !slices.Contains(supportedLoadBalancerTypes, opts.LoadBalancerType) {
 		return nil, fmt.Errorf(
-			"unsupported default load-balancer type %s. options.Options are %v",
-			options.Options.LoadBalancerType,
+			"unsupported default load-balancer type %s. options are %v",
+			opts.LoadBalancerType,
 			supportedLoadBalancerTypes,
 		)
 	}
 
-	if len(options.Options.NodeBalancerPrefix) > NodeBalancerPrefixCharLimit {
-		msg := fmt.Sprintf("nodebalancer-prefix must be %d characters or less: %s is %d characters\n", NodeBalancerPrefixCharLimit, options.Options.NodeBalancerPrefix, len(options.Options.NodeBalancerPrefix))
+	if len(opts.NodeBalancerPrefix) > NodeBalancerPrefixCharLimit {
+		msg := fmt.Sprintf("nodebalancer-prefix must be %d characters or less: %s is %d characters\n", NodeBalancerPrefixCharLimit, opts.NodeBalancerPrefix, len(opts.NodeBalancerPrefix))
 		klog.Error(msg)
 		return nil, fmt.Errorf("%s", msg)
 	}
 
 	validPrefix := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-	if !validPrefix.MatchString(options.Options.NodeBalancerPrefix) {
-		msg := fmt.Sprintf("nodebalancer-prefix must be no empty and use only letters, numbers, underscores, and dashes: %s\n", options.Options.NodeBalancerPrefix)
+	if !validPrefix.MatchString(opts.NodeBalancerPrefix) {
+		msg := fmt.Sprintf("nodebalancer-prefix must be no empty and use only letters, numbers, underscores, and dashes: %s\n", opts.NodeBalancerPrefix)
 		klog.Error(msg)
 		return nil, fmt.Errorf("%s", msg)
 	}
 
+	if opts.NodeBalancerLabelMode != "" && !slices.Contains(supportedNodeBalancerLabelModes, opts.NodeBalancerLabelMode) {
+		return nil, fmt.Errorf("unsupported nodebalancer-label-mode %s, options are %v", opts.NodeBalancerLabelMode, supportedNodeBalancerLabelModes)
+	}
+	if opts.NodeBalancerLabelMode == NodeBalancerLabelModeService {
+		if _, err = parseNodeBalancerLabelTemplate(opts.NodeBalancerLabelTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.EnableNodeBalancerGC && (opts.NodeBalancerGCInterval <= 0 || opts.NodeBalancerGCGracePeriod < 0) {
+		return nil, fmt.Errorf("nodebalancer-gc-interval must be positive and nodebalancer-gc-grace-period must not be negative")
+	}
+	if opts.EnableNodeBalancerHealth && opts.NodeBalancerHealthInterval <= 0 {
+		return nil, fmt.Errorf("nodebalancer-health-interval must be positive")
+	}
+	if opts.EnableNodeBalancerStats && (opts.NodeBalancerStatsInterval <= 0 || opts.NodeBalancerStatsMaxServices < 0) {
+		return nil, fmt.Errorf("nodebalancer-stats-interval must be positive and nodebalancer-stats-max-services must not be negative")
+	}
+
 	// create struct that satisfies cloudprovider.Interface
 	lcloud := &linodeCloud{
-		client:                   linodeClient,
+		clients:                  clients,
+		options:                  opts,
 		instances:                instanceCache,
-		loadbalancers:            newLoadbalancers(linodeClient, region),
+		loadbalancers:            newLoadbalancers(clients.loadBalancers, region, opts, vpcs),
 		routes:                   routes,
+		vpcs:                     vpcs,
+		k8sNodes:                 k8sNodes,
 		linodeTokenHealthChecker: healthChecker,
 	}
+
+	// A plan only reads, it doesn't serve the webhook of a running CCM
+	if opts.EnableServiceWebhook && !opts.Plan {
+		if err = startServiceWebhook(lcloud.loadbalancers.(*loadbalancers)); err != nil {
+			return nil, fmt.Errorf("failed to start service admission webhook: %w", err)
+		}
+	}
 	return lcloud, nil
 }
 
@@ -302,16 +377,43 @@ func (c *linodeCloud) Initialize(clientBuilder cloudprovider.ControllerClientBui
 		go c.linodeTokenHealthChecker.Run(stopCh)
 	}
 
+	if c.configReloader != nil && c.configReloader.path != "" && c.configReloader.interval > 0 {
+		go c.configReloader.Run(stopCh)
+	}
+
 	lb, assertion := c.loadbalancers.(*loadbalancers)
 	if !assertion {
 		klog.Error("type assertion during Initialize() failed")
 		return
 	}
+	endpointSliceInformer := sharedInformer.Discovery().V1().EndpointSlices()
+	lb.endpointSliceInformer = endpointSliceInformer
 	serviceController := newServiceController(lb, serviceInformer)
 	go serviceController.Run(stopCh)
 
-	nodeController := newNodeController(kubeclient, c.client, nodeInformer, instanceCache)
+	lb.loadBalancerConfigInformer = newLoadBalancerConfigInformer(
+		kubeclient.Discovery(), dynamic.NewForConfigOrDie(clientBuilder.ConfigOrDie("linode-shared-informers")))
+	serviceResyncController := newServiceResyncController(
+		lb, serviceInformer, nodeInformer, endpointSliceInformer, kubeclient)
+	go serviceResyncController.Run(stopCh)
+
+	nodeController := newNodeController(kubeclient, c.clients.instances, nodeInformer, c.instances, c.options, c.k8sNodes)
 	go nodeController.Run(stopCh)
+
+	if c.options.EnableNodeBalancerGC {
+		nodeBalancerGCController := newNodeBalancerGCController(c.clients.loadBalancers, c.options, serviceInformer)
+		go nodeBalancerGCController.Run(stopCh)
+	}
+
+	if c.options.EnableNodeBalancerHealth {
+		nodeBalancerHealthController := newNodeBalancerHealthController(lb, serviceInformer)
+		go nodeBalancerHealthController.Run(stopCh)
+	}
+
+	if c.options.EnableNodeBalancerStats {
+		nodeBalancerStatsCollector := newNodeBalancerStatsCollector(lb, serviceInformer)
+		go nodeBalancerStatsCollector.Run(stopCh)
+	}
 }
 
 func (c *linodeCloud) LoadBalancer() (cloudprovider.LoadBalancer, bool) {
@@ -335,7 +437,7 @@ func (c *linodeCloud) Clusters() (cloudprovider.Clusters, bool) {
 }
 
 func (c *linodeCloud) Routes() (cloudprovider.Routes, bool) {
-	if options.Options.EnableRouteController {
+	if c.options.EnableRouteController {
 		return c.routes, true
 	}
 	return nil, false
@@ -41,8 +41,8 @@ func TestTokenFileProviderCache(t *testing.T) {
 
 	now := time.Now()
 	provider := &tokenFileProvider{
-		path:     tokenFilePath,
-		cacheTTL: defaultTokenFileCacheTTL,
+		path:    tokenFilePath,
+		options: &options.Config{},
 		now: func() time.Time {
 			return now
 		},
@@ -64,34 +64,12 @@ func TestTokenFileProviderCache(t *testing.T) {
 	assert.Equal(t, "token-v2", refreshedToken)
 }
 
-func TestTokenFileCacheTTLFromEnv(t *testing.T) {
-	t.Run("uses default ttl", func(t *testing.T) {
-		t.Setenv(tokenCacheTTLEnv, "")
-		assert.Equal(t, defaultTokenFileCacheTTL, tokenFileCacheTTLFromEnv())
-	})
-
-	t.Run("uses configured ttl when valid", func(t *testing.T) {
-		t.Setenv(tokenCacheTTLEnv, "7")
-		assert.Equal(t, 7*time.Second, tokenFileCacheTTLFromEnv())
-	})
-
-	t.Run("falls back to default ttl when invalid", func(t *testing.T) {
-		t.Setenv(tokenCacheTTLEnv, "invalid")
-		assert.Equal(t, defaultTokenFileCacheTTL, tokenFileCacheTTLFromEnv())
-	})
-
-	t.Run("falls back to default ttl when non-positive", func(t *testing.T) {
-		t.Setenv(tokenCacheTTLEnv, "0")
-		assert.Equal(t, defaultTokenFileCacheTTL, tokenFileCacheTTLFromEnv())
-	})
-}
-
 func TestTokenProviderFromFileOrEnv(t *testing.T) {
 	t.Run("uses file token when available", func(t *testing.T) {
 		t.Setenv(accessTokenEnv, "env-token")
 		configureTokenFile(t, "file-token")
 
-		tokenProvider, source, err := tokenProviderFromFileOrEnv()
+		tokenProvider, source, err := tokenProviderFromFileOrEnv(&options.Config{})
 		require.NoError(t, err)
 		assert.Equal(t, "file \""+os.Getenv(tokenFilePathEnv)+"\"", source)
 
@@ -104,7 +82,7 @@ func TestTokenProviderFromFileOrEnv(t *testing.T) {
 		t.Setenv(accessTokenEnv, "env-token")
 		t.Setenv(tokenFilePathEnv, filepath.Join(t.TempDir(), "missing-token-file"))
 
-		tokenProvider, source, err := tokenProviderFromFileOrEnv()
+		tokenProvider, source, err := tokenProviderFromFileOrEnv(&options.Config{})
 		require.NoError(t, err)
 		assert.Equal(t, "environment variable \"LINODE_API_TOKEN\"", source)
 
@@ -117,7 +95,7 @@ func TestTokenProviderFromFileOrEnv(t *testing.T) {
 		t.Setenv(accessTokenEnv, "")
 		t.Setenv(tokenFilePathEnv, filepath.Join(t.TempDir(), "missing-token-file"))
 
-		_, _, err := tokenProviderFromFileOrEnv()
+		_, _, err := tokenProviderFromFileOrEnv(&options.Config{})
 		require.Error(t, err)
 		require.ErrorContains(t, err, "LINODE_API_TOKEN")
 		require.ErrorContains(t, err, "failed to load linode api token")
@@ -131,19 +109,16 @@ func TestNewCloudRouteControllerDisabled(t *testing.T) {
 	t.Setenv("LINODE_REGION", "us-east")
 	t.Setenv("LINODE_REQUEST_TIMEOUT_SECONDS", "10")
 	configureTokenFile(t, "dummyapitoken")
-	options.Options.NodeBalancerPrefix = "ccm"
 
 	t.Run("should not fail if vpc is empty and routecontroller is disabled", func(t *testing.T) {
-		options.Options.VPCNames = []string{}
-		options.Options.EnableRouteController = false
-		_, err := newCloud()
+		opts := &options.Config{NodeBalancerPrefix: "ccm", VPCNames: []string{}}
+		_, err := newCloud(opts)
 		assert.NoError(t, err)
 	})
 
 	t.Run("fail if vpcname is empty and routecontroller is enabled", func(t *testing.T) {
-		options.Options.VPCNames = []string{}
-		options.Options.EnableRouteController = true
-		_, err := newCloud()
+		opts := &options.Config{NodeBalancerPrefix: "ccm", VPCNames: []string{}, EnableRouteController: true}
+		_, err := newCloud(opts)
 		assert.Error(t, err)
 	})
 }
@@ -157,129 +132,91 @@ func TestNewCloud(t *testing.T) {
 	t.Setenv("LINODE_ROUTES_CACHE_TTL_SECONDS", "60")
 	t.Setenv("LINODE_URL", "https://api.linode.com/v4")
 	tokenFilePath := configureTokenFile(t, "dummyapitoken")
-	options.Options.LinodeGoDebug = true
-	options.Options.NodeBalancerPrefix = "ccm"
+	newOptions := func() *options.Config {
+		return &options.Config{LinodeGoDebug: true, NodeBalancerPrefix: "ccm"}
+	}
 
 	t.Run("should fail if api token is empty", func(t *testing.T) {
 		t.Cleanup(func() {
 			updateTokenFile(t, tokenFilePath, "dummyapitoken")
 		})
 		updateTokenFile(t, tokenFilePath, "")
-		_, err := newCloud()
+		_, err := newCloud(newOptions())
 		assert.Error(t, err, "expected error when api token is empty")
 	})
 
 	t.Run("should fail if region is empty", func(t *testing.T) {
 		t.Setenv("LINODE_REGION", "")
-		_, err := newCloud()
+		_, err := newCloud(newOptions())
 		assert.Error(t, err, "expected error when linode region is empty")
 	})
 
 	t.Run("should fail if both nodeBalancerBackendIPv4SubnetID and nodeBalancerBackendIPv4SubnetName are set", func(t *testing.T) {
-		options.Options.VPCNames = []string{"tt"}
-		options.Options.NodeBalancerBackendIPv4SubnetID = 12345
-		options.Options.NodeBalancerBackendIPv4SubnetName = "test-subnet"
-		defer func() {
-			options.Options.VPCNames = []string{}
-			options.Options.NodeBalancerBackendIPv4SubnetID = 0
-			options.Options.NodeBalancerBackendIPv4SubnetName = ""
-		}()
-		_, err := newCloud()
+		opts := newOptions()
+		opts.VPCNames = []string{"tt"}
+		opts.NodeBalancerBackendIPv4SubnetID = 12345
+		opts.NodeBalancerBackendIPv4SubnetName = "test-subnet"
+		_, err := newCloud(opts)
 		assert.Error(t, err, "expected error when both nodeBalancerBackendIPv4SubnetID and nodeBalancerBackendIPv4SubnetName are set")
 	})
 
 	t.Run("should fail if incorrect loadbalancertype is set", func(t *testing.T) {
-		rtEnabled := options.Options.EnableRouteController
-		options.Options.EnableRouteController = false
-		options.Options.LoadBalancerType = "test"
-		options.Options.VPCNames = []string{"vpc-test1", "vpc-test2"}
-		options.Options.NodeBalancerBackendIPv4SubnetName = "t1"
-		services.VpcIDs = map[string]int{"vpc-test1": 1, "vpc-test2": 2, "vpc-test3": 3}
-		services.SubnetIDs = map[string]int{"t1": 1, "t2": 2, "t3": 3}
-		defer func() {
-			options.Options.LoadBalancerType = ""
-			options.Options.EnableRouteController = rtEnabled
-			options.Options.VPCNames = []string{}
-			options.Options.NodeBalancerBackendIPv4SubnetID = 0
-			options.Options.NodeBalancerBackendIPv4SubnetName = ""
-			services.VpcIDs = map[string]int{}
-			services.SubnetIDs = map[string]int{}
-		}()
-		_, err := newCloud()
-		assert.Error(t, err, "expected error if incorrect loadbalancertype is set")
+		opts := newOptions()
+		opts.LoadBalancerType = "test"
+		_, err := newCloud(opts)
+		require.Error(t, err, "expected error if incorrect loadbalancertype is set")
+		require.ErrorContains(t, err, "unsupported default load-balancer type")
 	})
 
 	t.Run("should accept deprecated cilium-bgp loadbalancer type as nodebalancer", func(t *testing.T) {
-		loadBalancerType := options.Options.LoadBalancerType
-		routeControllerEnabled := options.Options.EnableRouteController
-		bgpNodeSelector := options.Options.BGPNodeSelector
-		ipHolderSuffix := options.Options.IpHolderSuffix
-		options.Options.LoadBalancerType = ciliumLBType
-		options.Options.EnableRouteController = false
-		options.Options.BGPNodeSelector = "cilium-bgp-peering=true"
-		options.Options.IpHolderSuffix = "legacy-cluster"
-		defer func() {
-			options.Options.LoadBalancerType = loadBalancerType
-			options.Options.EnableRouteController = routeControllerEnabled
-			options.Options.BGPNodeSelector = bgpNodeSelector
-			options.Options.IpHolderSuffix = ipHolderSuffix
-		}()
-
-		_, err := newCloud()
+		opts := newOptions()
+		opts.LoadBalancerType = ciliumLBType
+		opts.BGPNodeSelector = "cilium-bgp-peering=true"
+		opts.IpHolderSuffix = "legacy-cluster"
+
+		_, err := newCloud(opts)
 		require.NoError(t, err)
-		assert.Equal(t, nodeBalancerLBType, options.Options.LoadBalancerType)
+		assert.Equal(t, nodeBalancerLBType, opts.LoadBalancerType)
 	})
 
 	t.Run("should fail if nodebalancer-prefix is longer than 19 chars", func(t *testing.T) {
-		prefix := options.Options.NodeBalancerPrefix
-		rtEnabled := options.Options.EnableRouteController
-		options.Options.EnableRouteController = false
-		options.Options.LoadBalancerType = "nodebalancer"
-		options.Options.NodeBalancerPrefix = strings.Repeat("a", 21)
-		defer func() {
-			options.Options.NodeBalancerPrefix = prefix
-			options.Options.LoadBalancerType = ""
-			options.Options.EnableRouteController = rtEnabled
-		}()
-		_, err := newCloud()
+		opts := newOptions()
+		opts.LoadBalancerType = "nodebalancer"
+		opts.NodeBalancerPrefix = strings.Repeat("a", 21)
+		_, err := newCloud(opts)
 		t.Log(err)
 		require.Error(t, err, "expected error if nodebalancer-prefix is longer than 19 chars")
 		require.ErrorContains(t, err, "nodebalancer-prefix")
 	})
 
 	t.Run("should fail if nodebalancer-prefix is empty", func(t *testing.T) {
-		prefix := options.Options.NodeBalancerPrefix
-		rtEnabled := options.Options.EnableRouteController
-		options.Options.EnableRouteController = false
-		options.Options.LoadBalancerType = "nodebalancer"
-		options.Options.NodeBalancerPrefix = ""
-		defer func() {
-			options.Options.NodeBalancerPrefix = prefix
-			options.Options.LoadBalancerType = ""
-			options.Options.EnableRouteController = rtEnabled
-		}()
-		_, err := newCloud()
+		opts := newOptions()
+		opts.LoadBalancerType = "nodebalancer"
+		opts.NodeBalancerPrefix = ""
+		_, err := newCloud(opts)
 		t.Log(err)
 		require.Error(t, err, "expected error if nodebalancer-prefix is empty")
 		require.ErrorContains(t, err, "nodebalancer-prefix must be no empty")
 	})
 
 	t.Run("should fail if not validated nodebalancer-prefix", func(t *testing.T) {
-		prefix := options.Options.NodeBalancerPrefix
-		rtEnabled := options.Options.EnableRouteController
-		options.Options.EnableRouteController = false
-		options.Options.LoadBalancerType = "nodebalancer"
-		options.Options.NodeBalancerPrefix = "\\+x"
-		defer func() {
-			options.Options.NodeBalancerPrefix = prefix
-			options.Options.LoadBalancerType = ""
-			options.Options.EnableRouteController = rtEnabled
-		}()
-		_, err := newCloud()
+		opts := newOptions()
+		opts.LoadBalancerType = "nodebalancer"
+		opts.NodeBalancerPrefix = "\\+x"
+		_, err := newCloud(opts)
 		t.Log(err)
 		require.Error(t, err, "expected error if not validated nodebalancer-prefix")
 		require.ErrorContains(t, err, "nodebalancer-prefix must be no empty and use only letters, numbers, underscores, and dashes")
 	})
+
+	t.Run("should fail if nodebalancer-label-template is invalid", func(t *testing.T) {
+		opts := newOptions()
+		opts.LoadBalancerType = "nodebalancer"
+		opts.NodeBalancerLabelMode = NodeBalancerLabelModeService
+		opts.NodeBalancerLabelTemplate = "{{ .Namespac }}"
+		_, err := newCloud(opts)
+		require.ErrorContains(t, err, "invalid nodebalancer-label-template")
+	})
 }
 
 func Test_linodeCloud_LoadBalancer(t *testing.T) {
@@ -288,10 +225,12 @@ func Test_linodeCloud_LoadBalancer(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
+	opts := &options.Config{}
+	vpcs := services.NewVPCCache(opts)
 	tests := []struct {
 		name   string
 		fields fields
@@ -302,18 +241,18 @@ func Test_linodeCloud_LoadBalancer(t *testing.T) {
 			name: "should return loadbalancer interface",
 			fields: fields{
 				client:        client,
-				instances:     services.NewInstances(client),
-				loadbalancers: newLoadbalancers(client, "us-east"),
+				instances:     services.NewInstances(client, opts, vpcs),
+				loadbalancers: newLoadbalancers(client, "us-east", opts, vpcs),
 				routes:        nil,
 			},
-			want:  newLoadbalancers(client, "us-east"),
+			want:  newLoadbalancers(client, "us-east", opts, vpcs),
 			want1: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -335,10 +274,12 @@ func Test_linodeCloud_InstancesV2(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
+	opts := &options.Config{}
+	vpcs := services.NewVPCCache(opts)
 	tests := []struct {
 		name   string
 		fields fields
@@ -349,18 +290,18 @@ func Test_linodeCloud_InstancesV2(t *testing.T) {
 			name: "should return instances interface",
 			fields: fields{
 				client:        client,
-				instances:     services.NewInstances(client),
-				loadbalancers: newLoadbalancers(client, "us-east"),
+				instances:     services.NewInstances(client, opts, vpcs),
+				loadbalancers: newLoadbalancers(client, "us-east", opts, vpcs),
 				routes:        nil,
 			},
-			want:  services.NewInstances(client),
+			want:  services.NewInstances(client, opts, vpcs),
 			want1: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -382,10 +323,12 @@ func Test_linodeCloud_Instances(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
+	opts := &options.Config{}
+	vpcs := services.NewVPCCache(opts)
 	tests := []struct {
 		name   string
 		fields fields
@@ -396,8 +339,8 @@ func Test_linodeCloud_Instances(t *testing.T) {
 			name: "should return nil",
 			fields: fields{
 				client:        client,
-				instances:     services.NewInstances(client),
-				loadbalancers: newLoadbalancers(client, "us-east"),
+				instances:     services.NewInstances(client, opts, vpcs),
+				loadbalancers: newLoadbalancers(client, "us-east", opts, vpcs),
 				routes:        nil,
 			},
 			want:  nil,
@@ -407,7 +350,7 @@ func Test_linodeCloud_Instances(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -429,10 +372,12 @@ func Test_linodeCloud_Zones(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
+	opts := &options.Config{}
+	vpcs := services.NewVPCCache(opts)
 	tests := []struct {
 		name   string
 		fields fields
@@ -443,8 +388,8 @@ func Test_linodeCloud_Zones(t *testing.T) {
 			name: "should return nil",
 			fields: fields{
 				client:        client,
-				instances:     services.NewInstances(client),
-				loadbalancers: newLoadbalancers(client, "us-east"),
+				instances:     services.NewInstances(client, opts, vpcs),
+				loadbalancers: newLoadbalancers(client, "us-east", opts, vpcs),
 				routes:        nil,
 			},
 			want:  nil,
@@ -454,7 +399,7 @@ func Test_linodeCloud_Zones(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -476,10 +421,12 @@ func Test_linodeCloud_Clusters(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
+	opts := &options.Config{}
+	vpcs := services.NewVPCCache(opts)
 	tests := []struct {
 		name   string
 		fields fields
@@ -490,8 +437,8 @@ func Test_linodeCloud_Clusters(t *testing.T) {
 			name: "should return nil",
 			fields: fields{
 				client:        client,
-				instances:     services.NewInstances(client),
-				loadbalancers: newLoadbalancers(client, "us-east"),
+				instances:     services.NewInstances(client, opts, vpcs),
+				loadbalancers: newLoadbalancers(client, "us-east", opts, vpcs),
 				routes:        nil,
 			},
 			want:  nil,
@@ -501,7 +448,7 @@ func Test_linodeCloud_Clusters(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -524,11 +471,13 @@ func Test_linodeCloud_Routes(t *testing.T) {
 	r := &routes{}
 	type fields struct {
 		client                *mocks.MockClient
-		instances             cloudprovider.InstancesV2
+		instances             *services.Instances
 		loadbalancers         cloudprovider.LoadBalancer
 		routes                cloudprovider.Routes
 		EnableRouteController bool
 	}
+	opts := &options.Config{}
+	vpcs := services.NewVPCCache(opts)
 	tests := []struct {
 		name   string
 		fields fields
@@ -539,8 +488,8 @@ func Test_linodeCloud_Routes(t *testing.T) {
 			name: "should return nil",
 			fields: fields{
 				client:                client,
-				instances:             services.NewInstances(client),
-				loadbalancers:         newLoadbalancers(client, "us-east"),
+				instances:             services.NewInstances(client, opts, vpcs),
+				loadbalancers:         newLoadbalancers(client, "us-east", opts, vpcs),
 				routes:                r,
 				EnableRouteController: false,
 			},
@@ -551,8 +500,8 @@ func Test_linodeCloud_Routes(t *testing.T) {
 			name: "should return routes interface",
 			fields: fields{
 				client:                client,
-				instances:             services.NewInstances(client),
-				loadbalancers:         newLoadbalancers(client, "us-east"),
+				instances:             services.NewInstances(client, opts, vpcs),
+				loadbalancers:         newLoadbalancers(client, "us-east", opts, vpcs),
 				routes:                r,
 				EnableRouteController: true,
 			},
@@ -563,14 +512,12 @@ func Test_linodeCloud_Routes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
+				options:       &options.Config{EnableRouteController: tt.fields.EnableRouteController},
 			}
-			rt := options.Options.EnableRouteController
-			defer func() { options.Options.EnableRouteController = rt }()
-			options.Options.EnableRouteController = tt.fields.EnableRouteController
 			got, got1 := c.Routes()
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("linodeCloud.Routes() got = %v, want %v", got, tt.want)
@@ -585,7 +532,7 @@ func Test_linodeCloud_Routes(t *testing.T) {
 func Test_linodeCloud_ProviderName(t *testing.T) {
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
@@ -608,7 +555,7 @@ func Test_linodeCloud_ProviderName(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -623,7 +570,7 @@ func Test_linodeCloud_ProviderName(t *testing.T) {
 func Test_linodeCloud_ScrubDNS(t *testing.T) {
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
@@ -653,7 +600,7 @@ func Test_linodeCloud_ScrubDNS(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -672,7 +619,7 @@ func Test_linodeCloud_ScrubDNS(t *testing.T) {
 func Test_linodeCloud_HasClusterID(t *testing.T) {
 	type fields struct {
 		client        *mocks.MockClient
-		instances     cloudprovider.InstancesV2
+		instances     *services.Instances
 		loadbalancers cloudprovider.LoadBalancer
 		routes        cloudprovider.Routes
 	}
@@ -695,7 +642,7 @@ func Test_linodeCloud_HasClusterID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &linodeCloud{
-				client:        tt.fields.client,
+				clients:       linodeClients{defaultClient: tt.fields.client},
 				instances:     tt.fields.instances,
 				loadbalancers: tt.fields.loadbalancers,
 				routes:        tt.fields.routes,
@@ -706,3 +653,35 @@ func Test_linodeCloud_HasClusterID(t *testing.T) {
 		})
 	}
 }
+
+func Test_linodeAPIRateLimitOptions(t *testing.T) {
+	opts := &options.Config{
+		LinodeAPIRequestsPerSecond:       10,
+		LinodeAPIBurst:                   20,
+		LinodeAPIMaxRetries:              5,
+		LinodeAPIRetryBaseDelay:          time.Second,
+		LinodeAPIRetryMaxDelay:           30 * time.Second,
+		LinodeAPIMethodRequestsPerSecond: map[string]string{"ListInstances": "0.5"},
+	}
+	rateLimitOptions, err := linodeAPIRateLimitOptions(opts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"ListInstances": 0.5}, rateLimitOptions.MethodRequestsPerSecond)
+
+	opts.LinodeAPIMethodRequestsPerSecond = map[string]string{"ListInstance": "0.5"}
+	_, err = linodeAPIRateLimitOptions(opts)
+	require.ErrorContains(t, err, "unknown client method")
+
+	opts.LinodeAPIMethodRequestsPerSecond = map[string]string{"ListInstances": "0"}
+	_, err = linodeAPIRateLimitOptions(opts)
+	require.ErrorContains(t, err, "invalid budget")
+
+	opts.LinodeAPIMethodRequestsPerSecond = nil
+	opts.LinodeAPIRetryMaxDelay = time.Millisecond
+	_, err = linodeAPIRateLimitOptions(opts)
+	require.Error(t, err)
+
+	opts.LinodeAPIRetryMaxDelay = 30 * time.Second
+	opts.LinodeAPICacheTTL = -time.Second
+	_, err = linodeAPIRateLimitOptions(opts)
+	require.ErrorContains(t, err, "linode-api-cache-ttl")
+}
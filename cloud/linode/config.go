@@ -0,0 +1,485 @@
+package linode
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
+)
+
+const (
+	// ConfigAPIVersion and ConfigKind identify the version of the configuration file passed with
+	// --cloud-config.
+	ConfigAPIVersion = "ccm.linode.com/v1alpha1"
+	ConfigKind       = "LinodeCloudConfig"
+
+	requestTimeoutEnv      = "LINODE_REQUEST_TIMEOUT_SECONDS"
+	tokenCacheTTLEnv       = "LINODE_API_TOKEN_CACHE_TTL_SECONDS"
+	routesCacheTTLEnv      = "LINODE_ROUTES_CACHE_TTL_SECONDS"
+	k8sNodeCacheTTLEnv     = "K8S_NODECACHE_TTL"
+	metadataTTLEnv         = "LINODE_METADATA_TTL"
+	instanceCacheTTLEnv    = "LINODE_INSTANCE_CACHE_TTL"
+	hostnameOnlyIngressEnv = "LINODE_HOSTNAME_ONLY_INGRESS"
+	externalSubnetEnv      = "LINODE_EXTERNAL_SUBNET"
+)
+
+// CloudConfig is the configuration file of the Linode CCM. Every setting is optional; settings
+// that are left out keep the value of their environment variable, flag or default.
+type CloudConfig struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	LinodeAPI      LinodeAPIConfig      `json:"linodeAPI"`
+	Routes         RoutesConfig         `json:"routes"`
+	Nodes          NodesConfig          `json:"nodes"`
+	LoadBalancers  LoadBalancersConfig  `json:"loadBalancers"`
+	ServiceWebhook ServiceWebhookConfig `json:"serviceWebhook"`
+}
+
+// LinodeAPIConfig configures the Linode API client shared by all controllers.
+type LinodeAPIConfig struct {
+	Debug                    *bool              `json:"debug,omitempty"`
+	RequestTimeout           *metav1.Duration   `json:"requestTimeout,omitempty"`
+	TokenCacheTTL            *metav1.Duration   `json:"tokenCacheTTL,omitempty"`
+	EnableTokenHealthChecker *bool              `json:"enableTokenHealthChecker,omitempty"`
+	TagFilter                *string            `json:"tagFilter,omitempty"`
+	RequestsPerSecond        *float64           `json:"requestsPerSecond,omitempty"`
+	Burst                    *int               `json:"burst,omitempty"`
+	MethodRequestsPerSecond  map[string]float64 `json:"methodRequestsPerSecond,omitempty"`
+	MaxRetries               *int               `json:"maxRetries,omitempty"`
+	RetryBaseDelay           *metav1.Duration   `json:"retryBaseDelay,omitempty"`
+	RetryMaxDelay            *metav1.Duration   `json:"retryMaxDelay,omitempty"`
+	CacheTTL                 *metav1.Duration   `json:"cacheTTL,omitempty"`
+}
+
+// RoutesConfig configures the route controller.
+type RoutesConfig struct {
+	Enabled     *bool            `json:"enabled,omitempty"`
+	CacheTTL    *metav1.Duration `json:"cacheTTL,omitempty"`
+	VPCNames    []string         `json:"vpcNames,omitempty"`
+	VPCIDs      []int            `json:"vpcIDs,omitempty"`
+	SubnetNames []string         `json:"subnetNames,omitempty"`
+	SubnetIDs   []int            `json:"subnetIDs,omitempty"`
+}
+
+// NodesConfig configures the node controller, the instance cache and node IPAM.
+type NodesConfig struct {
+	CacheTTL                      *metav1.Duration `json:"cacheTTL,omitempty"`
+	MetadataTTL                   *metav1.Duration `json:"metadataTTL,omitempty"`
+	InstanceCacheTTL              *metav1.Duration `json:"instanceCacheTTL,omitempty"`
+	ExternalSubnet                *string          `json:"externalSubnet,omitempty"`
+	NodeCIDRMaskSizeIPv4          *int             `json:"nodeCIDRMaskSizeIPv4,omitempty"`
+	NodeCIDRMaskSizeIPv6          *int             `json:"nodeCIDRMaskSizeIPv6,omitempty"`
+	DisableIPv6NodeCIDRAllocation *bool            `json:"disableIPv6NodeCIDRAllocation,omitempty"`
+}
+
+// LoadBalancersConfig configures the NodeBalancers of LoadBalancer services.
+type LoadBalancersConfig struct {
+	Type                        *string                  `json:"type,omitempty"`
+	DefaultNodeBalancerType     *string                  `json:"defaultNodeBalancerType,omitempty"`
+	NodeBalancerTags            []string                 `json:"nodeBalancerTags,omitempty"`
+	NodeBalancerPrefix          *string                  `json:"nodeBalancerPrefix,omitempty"`
+	NodeBalancerLabelMode       *string                  `json:"nodeBalancerLabelMode,omitempty"`
+	NodeBalancerLabelTemplate   *string                  `json:"nodeBalancerLabelTemplate,omitempty"`
+	BackendIPv4Subnet           *string                  `json:"backendIPv4Subnet,omitempty"`
+	BackendIPv4SubnetID         *int                     `json:"backendIPv4SubnetID,omitempty"`
+	BackendIPv4SubnetName       *string                  `json:"backendIPv4SubnetName,omitempty"`
+	DisableVPCBackends          *bool                    `json:"disableVPCBackends,omitempty"`
+	EnableIPv6                  *bool                    `json:"enableIPv6,omitempty"`
+	EnableIPv6ForBackends       *bool                    `json:"enableIPv6ForBackends,omitempty"`
+	HostnameOnlyIngress         *bool                    `json:"hostnameOnlyIngress,omitempty"`
+	EndpointAwareBackends       *bool                    `json:"endpointAwareBackends,omitempty"`
+	TLSSecretNamespaceAllowlist []string                 `json:"tlsSecretNamespaceAllowlist,omitempty"`
+	GarbageCollection           NodeBalancerGCConfig     `json:"garbageCollection"`
+	Health                      NodeBalancerHealthConfig `json:"health"`
+	Stats                       NodeBalancerStatsConfig  `json:"stats"`
+}
+
+// NodeBalancerGCConfig configures the NodeBalancer garbage collector.
+type NodeBalancerGCConfig struct {
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Interval    *metav1.Duration `json:"interval,omitempty"`
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	ReportOnly  *bool            `json:"reportOnly,omitempty"`
+}
+
+// NodeBalancerHealthConfig configures the reporting of NodeBalancer backend health.
+type NodeBalancerHealthConfig struct {
+	Enabled  *bool            `json:"enabled,omitempty"`
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// NodeBalancerStatsConfig configures the collection of NodeBalancer statistics.
+type NodeBalancerStatsConfig struct {
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Interval    *metav1.Duration `json:"interval,omitempty"`
+	MaxServices *int             `json:"maxServices,omitempty"`
+}
+
+// ServiceWebhookConfig configures the service admission webhook.
+type ServiceWebhookConfig struct {
+	Enabled *bool   `json:"enabled,omitempty"`
+	Port    *int    `json:"port,omitempty"`
+	CertDir *string `json:"certDir,omitempty"`
+}
+
+// loadConfigFile applies the configuration file at path, which may be empty, and the environment
+// to opts like loadConfig.
+func loadConfigFile(opts *options.Config, path string) error {
+	var config io.Reader
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open cloud config: %w", err)
+		}
+		defer file.Close()
+		config = file
+	}
+	return loadConfig(opts, config)
+}
+
+// loadConfig applies the configuration file read from config, which may be nil, and the
+// environment to opts. Flags passed on the command line take precedence over the environment,
+// which takes precedence over the file.
+func loadConfig(opts *options.Config, config io.Reader) error {
+	setConfigDefaults(opts)
+	if config != nil {
+		cfg, err := readConfig(config)
+		if err != nil {
+			return err
+		}
+		if err := applyConfig(opts, cfg); err != nil {
+			return err
+		}
+	}
+	if err := applyEnv(opts); err != nil {
+		return err
+	}
+
+	// Settings with flags are validated by newCloud
+	for name, ttl := range map[string]time.Duration{
+		"linodeAPI.requestTimeout": opts.LinodeAPIRequestTimeout,
+		"linodeAPI.tokenCacheTTL":  opts.LinodeAPITokenCacheTTL,
+		"routes.cacheTTL":          opts.RoutesCacheTTL,
+		"nodes.cacheTTL":           opts.K8sNodeCacheTTL,
+		"nodes.metadataTTL":        opts.MetadataTTL,
+		"nodes.instanceCacheTTL":   opts.InstanceCacheTTL,
+	} {
+		if ttl <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", name, ttl)
+		}
+	}
+	return nil
+}
+
+// readConfig decodes and validates the version of a configuration file. Unknown settings are
+// rejected.
+func readConfig(config io.Reader) (*CloudConfig, error) {
+	data, err := io.ReadAll(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cloud config: %w", err)
+	}
+	cfg := &CloudConfig{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return cfg, nil
+	}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse cloud config: %w", err)
+	}
+	if cfg.APIVersion != ConfigAPIVersion || cfg.Kind != ConfigKind {
+		return nil, fmt.Errorf("unsupported cloud config %s %s, expected apiVersion %s and kind %s", cfg.APIVersion, cfg.Kind, ConfigAPIVersion, ConfigKind)
+	}
+	return cfg, nil
+}
+
+// setConfigDefaults defaults the settings that have no flag.
+func setConfigDefaults(o *options.Config) {
+	defaultTo(&o.LinodeAPIRequestTimeout, client.DefaultClientTimeout)
+	defaultTo(&o.LinodeAPITokenCacheTTL, defaultTokenFileCacheTTL)
+	defaultTo(&o.RoutesCacheTTL, defaultRoutesCacheTTL)
+	defaultTo(&o.K8sNodeCacheTTL, defaultK8sNodeCacheTTL)
+	defaultTo(&o.MetadataTTL, defaultMetadataTTL)
+	defaultTo(&o.InstanceCacheTTL, services.DefaultInstanceCacheTTL)
+}
+
+func defaultTo(dst *time.Duration, value time.Duration) {
+	if *dst == 0 {
+		*dst = value
+	}
+}
+
+// applyConfig copies the settings of cfg to o, except for those whose flag was passed on the
+// command line.
+func applyConfig(o *options.Config, cfg *CloudConfig) error {
+	api := cfg.LinodeAPI
+	setFromFile(o.Flags, &o.LinodeGoDebug, api.Debug, "linodego-debug")
+	setFromFile(o.Flags, &o.LinodeAPIRequestTimeout, duration(api.RequestTimeout), "")
+	setFromFile(o.Flags, &o.LinodeAPITokenCacheTTL, duration(api.TokenCacheTTL), "")
+	setFromFile(o.Flags, &o.EnableTokenHealthChecker, api.EnableTokenHealthChecker, "enable-token-health-checker")
+	setFromFile(o.Flags, &o.LinodeTagFilter, api.TagFilter, "linode-tag-filter")
+	setFromFile(o.Flags, &o.LinodeAPIRequestsPerSecond, api.RequestsPerSecond, "linode-api-requests-per-second")
+	setFromFile(o.Flags, &o.LinodeAPIBurst, api.Burst, "linode-api-burst")
+	if api.MethodRequestsPerSecond != nil {
+		budgets := make(map[string]string, len(api.MethodRequestsPerSecond))
+		for method, rps := range api.MethodRequestsPerSecond {
+			budgets[method] = strconv.FormatFloat(rps, 'f', -1, 64)
+		}
+		setFromFile(o.Flags, &o.LinodeAPIMethodRequestsPerSecond, &budgets, "linode-api-method-requests-per-second")
+	}
+	setFromFile(o.Flags, &o.LinodeAPIMaxRetries, api.MaxRetries, "linode-api-max-retries")
+	setFromFile(o.Flags, &o.LinodeAPIRetryBaseDelay, duration(api.RetryBaseDelay), "linode-api-retry-base-delay")
+	setFromFile(o.Flags, &o.LinodeAPIRetryMaxDelay, duration(api.RetryMaxDelay), "linode-api-retry-max-delay")
+	setFromFile(o.Flags, &o.LinodeAPICacheTTL, duration(api.CacheTTL), "linode-api-cache-ttl")
+
+	routes := cfg.Routes
+	setFromFile(o.Flags, &o.EnableRouteController, routes.Enabled, "enable-route-controller")
+	setFromFile(o.Flags, &o.RoutesCacheTTL, duration(routes.CacheTTL), "")
+	setFromFile(o.Flags, &o.VPCNames, slice(routes.VPCNames), "vpc-names")
+	setFromFile(o.Flags, &o.VPCIDs, slice(routes.VPCIDs), "vpc-ids")
+	setFromFile(o.Flags, &o.SubnetNames, slice(routes.SubnetNames), "subnet-names")
+	setFromFile(o.Flags, &o.SubnetIDs, slice(routes.SubnetIDs), "subnet-ids")
+
+	nodes := cfg.Nodes
+	setFromFile(o.Flags, &o.K8sNodeCacheTTL, duration(nodes.CacheTTL), "")
+	setFromFile(o.Flags, &o.MetadataTTL, duration(nodes.MetadataTTL), "")
+	setFromFile(o.Flags, &o.InstanceCacheTTL, duration(nodes.InstanceCacheTTL), "")
+	if nodes.ExternalSubnet != nil {
+		if err := setExternalSubnet(o, *nodes.ExternalSubnet); err != nil {
+			return err
+		}
+	}
+	setFromFile(o.Flags, &o.NodeCIDRMaskSizeIPv4, nodes.NodeCIDRMaskSizeIPv4, "node-cidr-mask-size-ipv4")
+	setFromFile(o.Flags, &o.NodeCIDRMaskSizeIPv6, nodes.NodeCIDRMaskSizeIPv6, "node-cidr-mask-size-ipv6")
+	setFromFile(o.Flags, &o.DisableIPv6NodeCIDRAllocation, nodes.DisableIPv6NodeCIDRAllocation, "disable-ipv6-node-cidr-allocation")
+
+	lbs := cfg.LoadBalancers
+	setFromFile(o.Flags, &o.LoadBalancerType, lbs.Type, "load-balancer-type")
+	setFromFile(o.Flags, &o.DefaultNBType, lbs.DefaultNodeBalancerType, "default-nodebalancer-type")
+	setFromFile(o.Flags, &o.NodeBalancerTags, slice(lbs.NodeBalancerTags), "nodebalancer-tags")
+	setFromFile(o.Flags, &o.NodeBalancerPrefix, lbs.NodeBalancerPrefix, "nodebalancer-prefix")
+	setFromFile(o.Flags, &o.NodeBalancerLabelMode, lbs.NodeBalancerLabelMode, "nodebalancer-label-mode")
+	setFromFile(o.Flags, &o.NodeBalancerLabelTemplate, lbs.NodeBalancerLabelTemplate, "nodebalancer-label-template")
+	setFromFile(o.Flags, &o.NodeBalancerBackendIPv4Subnet, lbs.BackendIPv4Subnet, "nodebalancer-backend-ipv4-subnet")
+	setFromFile(o.Flags, &o.NodeBalancerBackendIPv4SubnetID, lbs.BackendIPv4SubnetID, "nodebalancer-backend-ipv4-subnet-id")
+	setFromFile(o.Flags, &o.NodeBalancerBackendIPv4SubnetName, lbs.BackendIPv4SubnetName, "nodebalancer-backend-ipv4-subnet-name")
+	setFromFile(o.Flags, &o.DisableNodeBalancerVPCBackends, lbs.DisableVPCBackends, "disable-nodebalancer-vpc-backends")
+	setFromFile(o.Flags, &o.EnableIPv6ForLoadBalancers, lbs.EnableIPv6, "enable-ipv6-for-loadbalancers")
+	setFromFile(o.Flags, &o.EnableIPv6ForNodeBalancerBackends, lbs.EnableIPv6ForBackends, "enable-ipv6-for-nodebalancer-backends")
+	setFromFile(o.Flags, &o.HostnameOnlyIngress, lbs.HostnameOnlyIngress, "")
+	setFromFile(o.Flags, &o.EnableEndpointAwareBackends, lbs.EndpointAwareBackends, "enable-endpoint-aware-backends")
+	setFromFile(o.Flags, &o.TLSSecretNamespaceAllowlist, slice(lbs.TLSSecretNamespaceAllowlist), "tls-secret-namespace-allowlist")
+	setFromFile(o.Flags, &o.EnableNodeBalancerGC, lbs.GarbageCollection.Enabled, "enable-nodebalancer-gc")
+	setFromFile(o.Flags, &o.NodeBalancerGCInterval, duration(lbs.GarbageCollection.Interval), "nodebalancer-gc-interval")
+	setFromFile(o.Flags, &o.NodeBalancerGCGracePeriod, duration(lbs.GarbageCollection.GracePeriod), "nodebalancer-gc-grace-period")
+	setFromFile(o.Flags, &o.NodeBalancerGCReportOnly, lbs.GarbageCollection.ReportOnly, "nodebalancer-gc-report-only")
+	setFromFile(o.Flags, &o.EnableNodeBalancerHealth, lbs.Health.Enabled, "enable-nodebalancer-health")
+	setFromFile(o.Flags, &o.NodeBalancerHealthInterval, duration(lbs.Health.Interval), "nodebalancer-health-interval")
+	setFromFile(o.Flags, &o.EnableNodeBalancerStats, lbs.Stats.Enabled, "enable-nodebalancer-stats")
+	setFromFile(o.Flags, &o.NodeBalancerStatsInterval, duration(lbs.Stats.Interval), "nodebalancer-stats-interval")
+	setFromFile(o.Flags, &o.NodeBalancerStatsMaxServices, lbs.Stats.MaxServices, "nodebalancer-stats-max-services")
+
+	webhook := cfg.ServiceWebhook
+	setFromFile(o.Flags, &o.EnableServiceWebhook, webhook.Enabled, "enable-service-webhook")
+	setFromFile(o.Flags, &o.ServiceWebhookPort, webhook.Port, "service-webhook-port")
+	setFromFile(o.Flags, &o.ServiceWebhookCertDir, webhook.CertDir, "service-webhook-cert-dir")
+	return nil
+}
+
+// setFromFile sets dst to value unless value is not set or flag was passed on the command line.
+// Settings without a flag pass an empty flag.
+func setFromFile[T any](flags *pflag.FlagSet, dst *T, value *T, flag string) {
+	if value == nil || flagChanged(flags, flag) {
+		return
+	}
+	*dst = *value
+}
+
+func flagChanged(flags *pflag.FlagSet, flag string) bool {
+	return flag != "" && flags != nil && flags.Changed(flag)
+}
+
+func duration(d *metav1.Duration) *time.Duration {
+	if d == nil {
+		return nil
+	}
+	return &d.Duration
+}
+
+func slice[T any](s []T) *[]T {
+	if s == nil {
+		return nil
+	}
+	return &s
+}
+
+// applyEnv applies the settings of environment variables, which have no flags. Invalid TTLs and
+// booleans only log a warning and keep their previous value, as they always did, while an invalid
+// external subnet fails like it always did.
+func applyEnv(o *options.Config) error {
+	for env, dst := range map[string]*time.Duration{
+		requestTimeoutEnv:   &o.LinodeAPIRequestTimeout,
+		tokenCacheTTLEnv:    &o.LinodeAPITokenCacheTTL,
+		routesCacheTTLEnv:   &o.RoutesCacheTTL,
+		k8sNodeCacheTTLEnv:  &o.K8sNodeCacheTTL,
+		metadataTTLEnv:      &o.MetadataTTL,
+		instanceCacheTTLEnv: &o.InstanceCacheTTL,
+	} {
+		raw, ok := os.LookupEnv(env)
+		if !ok || raw == "" {
+			continue
+		}
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds <= 0 {
+			klog.Warningf("ignoring invalid %s %q, must be a positive number of seconds, keeping %s", env, raw, *dst)
+			continue
+		}
+		*dst = time.Duration(seconds) * time.Second
+	}
+
+	if raw, ok := os.LookupEnv(hostnameOnlyIngressEnv); ok && raw != "" {
+		hostnameOnly, err := strconv.ParseBool(raw)
+		if err != nil {
+			klog.Warningf("ignoring invalid %s %q, must be true or false, keeping %t", hostnameOnlyIngressEnv, raw, o.HostnameOnlyIngress)
+		} else {
+			o.HostnameOnlyIngress = hostnameOnly
+		}
+	}
+
+	if raw, ok := os.LookupEnv(externalSubnetEnv); ok && raw != "" {
+		if err := setExternalSubnet(o, raw); err != nil {
+			return fmt.Errorf("invalid %s: %w", externalSubnetEnv, err)
+		}
+	}
+	return nil
+}
+
+func setExternalSubnet(o *options.Config, raw string) error {
+	if raw == "" {
+		o.LinodeExternalNetwork = nil
+		return nil
+	}
+	_, network, err := net.ParseCIDR(raw)
+	if err != nil {
+		return fmt.Errorf("unable to parse %s as network subnet: %w", raw, err)
+	}
+	o.LinodeExternalNetwork = network
+	return nil
+}
+
+// effectiveConfig returns the configuration file equivalent to o.
+func effectiveConfig(o *options.Config) (*CloudConfig, error) {
+	rateLimitOptions, err := linodeAPIRateLimitOptions(o)
+	if err != nil {
+		return nil, err
+	}
+	var externalSubnet string
+	if o.LinodeExternalNetwork != nil {
+		externalSubnet = o.LinodeExternalNetwork.String()
+	}
+
+	return &CloudConfig{
+		APIVersion: ConfigAPIVersion,
+		Kind:       ConfigKind,
+		LinodeAPI: LinodeAPIConfig{
+			Debug:                    ptr.To(o.LinodeGoDebug),
+			RequestTimeout:           &metav1.Duration{Duration: o.LinodeAPIRequestTimeout},
+			TokenCacheTTL:            &metav1.Duration{Duration: o.LinodeAPITokenCacheTTL},
+			EnableTokenHealthChecker: ptr.To(o.EnableTokenHealthChecker),
+			TagFilter:                ptr.To(o.LinodeTagFilter),
+			RequestsPerSecond:        ptr.To(o.LinodeAPIRequestsPerSecond),
+			Burst:                    ptr.To(o.LinodeAPIBurst),
+			MethodRequestsPerSecond:  rateLimitOptions.MethodRequestsPerSecond,
+			MaxRetries:               ptr.To(o.LinodeAPIMaxRetries),
+			RetryBaseDelay:           &metav1.Duration{Duration: o.LinodeAPIRetryBaseDelay},
+			RetryMaxDelay:            &metav1.Duration{Duration: o.LinodeAPIRetryMaxDelay},
+			CacheTTL:                 &metav1.Duration{Duration: o.LinodeAPICacheTTL},
+		},
+		Routes: RoutesConfig{
+			Enabled:     ptr.To(o.EnableRouteController),
+			CacheTTL:    &metav1.Duration{Duration: o.RoutesCacheTTL},
+			VPCNames:    o.VPCNames,
+			VPCIDs:      o.VPCIDs,
+			SubnetNames: o.SubnetNames,
+			SubnetIDs:   o.SubnetIDs,
+		},
+		Nodes: NodesConfig{
+			CacheTTL:                      &metav1.Duration{Duration: o.K8sNodeCacheTTL},
+			MetadataTTL:                   &metav1.Duration{Duration: o.MetadataTTL},
+			InstanceCacheTTL:              &metav1.Duration{Duration: o.InstanceCacheTTL},
+			ExternalSubnet:                ptr.To(externalSubnet),
+			NodeCIDRMaskSizeIPv4:          ptr.To(o.NodeCIDRMaskSizeIPv4),
+			NodeCIDRMaskSizeIPv6:          ptr.To(o.NodeCIDRMaskSizeIPv6),
+			DisableIPv6NodeCIDRAllocation: ptr.To(o.DisableIPv6NodeCIDRAllocation),
+		},
+		LoadBalancers: LoadBalancersConfig{
+			Type:                        ptr.To(o.LoadBalancerType),
+			DefaultNodeBalancerType:     ptr.To(o.DefaultNBType),
+			NodeBalancerTags:            o.NodeBalancerTags,
+			NodeBalancerPrefix:          ptr.To(o.NodeBalancerPrefix),
+			NodeBalancerLabelMode:       ptr.To(o.NodeBalancerLabelMode),
+			NodeBalancerLabelTemplate:   ptr.To(o.NodeBalancerLabelTemplate),
+			BackendIPv4Subnet:           ptr.To(o.NodeBalancerBackendIPv4Subnet),
+			BackendIPv4SubnetID:         ptr.To(o.NodeBalancerBackendIPv4SubnetID),
+			BackendIPv4SubnetName:       ptr.To(o.NodeBalancerBackendIPv4SubnetName),
+			DisableVPCBackends:          ptr.To(o.DisableNodeBalancerVPCBackends),
+			EnableIPv6:                  ptr.To(o.EnableIPv6ForLoadBalancers),
+			EnableIPv6ForBackends:       ptr.To(o.EnableIPv6ForNodeBalancerBackends),
+			HostnameOnlyIngress:         ptr.To(o.HostnameOnlyIngress),
+			EndpointAwareBackends:       ptr.To(o.EnableEndpointAwareBackends),
+			TLSSecretNamespaceAllowlist: o.TLSSecretNamespaceAllowlist,
+			GarbageCollection: NodeBalancerGCConfig{
+				Enabled:     ptr.To(o.EnableNodeBalancerGC),
+				Interval:    &metav1.Duration{Duration: o.NodeBalancerGCInterval},
+				GracePeriod: &metav1.Duration{Duration: o.NodeBalancerGCGracePeriod},
+				ReportOnly:  ptr.To(o.NodeBalancerGCReportOnly),
+			},
+			Health: NodeBalancerHealthConfig{
+				Enabled:  ptr.To(o.EnableNodeBalancerHealth),
+				Interval: &metav1.Duration{Duration: o.NodeBalancerHealthInterval},
+			},
+			Stats: NodeBalancerStatsConfig{
+				Enabled:     ptr.To(o.EnableNodeBalancerStats),
+				Interval:    &metav1.Duration{Duration: o.NodeBalancerStatsInterval},
+				MaxServices: ptr.To(o.NodeBalancerStatsMaxServices),
+			},
+		},
+		ServiceWebhook: ServiceWebhookConfig{
+			Enabled: ptr.To(o.EnableServiceWebhook),
+			Port:    ptr.To(o.ServiceWebhookPort),
+			CertDir: ptr.To(o.ServiceWebhookCertDir),
+		},
+	}, nil
+}
+
+// PrintEffectiveConfig loads the configuration file at path, which may be empty, and the
+// environment into opts like the cloud provider does, and writes the resulting configuration to
+// out as YAML.
+func PrintEffectiveConfig(opts *options.Config, path string, out io.Writer) error {
+	if err := loadConfigFile(opts, path); err != nil {
+		return err
+	}
+
+	cfg, err := effectiveConfig(opts)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
@@ -0,0 +1,235 @@
+package linode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+// reloadableSettings are the settings of the configuration file that are put into effect while the
+// CCM is running, those of options.Reloadable. All other settings are only read at startup.
+var reloadableSettings = sets.New(
+	"linodeAPI.tokenCacheTTL",
+	"routes.cacheTTL",
+	"nodes.cacheTTL",
+	"nodes.metadataTTL",
+	"nodes.instanceCacheTTL",
+	"loadBalancers.defaultNodeBalancerType",
+	"loadBalancers.nodeBalancerTags",
+	"loadBalancers.hostnameOnlyIngress",
+)
+
+// configReloader loads the configuration file of the CCM at startup and reloads it whenever it
+// changes. A change is only put into effect if every setting it changes is reloadable, otherwise
+// it is rejected as a whole.
+type configReloader struct {
+	options  *options.Config
+	path     string
+	interval time.Duration
+
+	// flags is the configuration without the file, which every version of the file is applied to
+	flags *CloudConfig
+	// data is the content of the file when it was last read, applied the configuration in effect
+	data       []byte
+	applied    *CloudConfig
+	generation int
+}
+
+// configChange is a setting that differs between two configurations, identified by its path in
+// the configuration file and with its values as JSON.
+type configChange struct {
+	path     string
+	old, new string
+}
+
+func (c configChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.path, c.old, c.new)
+}
+
+// newConfigReloader applies the configuration file at path, which may be empty, and the
+// environment to opts like loadConfigFile and returns a configReloader for later changes of the
+// file.
+func newConfigReloader(opts *options.Config, path string) (*configReloader, error) {
+	flags, err := effectiveConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var config io.Reader
+	if path != "" {
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to open cloud config: %w", err)
+		}
+		config = bytes.NewReader(data)
+	}
+	if err = loadConfig(opts, config); err != nil {
+		return nil, err
+	}
+	applied, err := effectiveConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	configGenerationGauge.Set(1)
+	return &configReloader{
+		options:    opts,
+		path:       path,
+		interval:   opts.ConfigReloadInterval,
+		flags:      flags,
+		data:       data,
+		applied:    applied,
+		generation: 1,
+	}, nil
+}
+
+// Run reloads the configuration file every interval until stopCh is closed.
+func (r *configReloader) Run(stopCh <-chan struct{}) {
+	klog.Infof("Reloading cloud config %s every %s", r.path, r.interval)
+	wait.Until(func() {
+		if err := r.reload(); err != nil {
+			klog.Errorf("Failed to reload cloud config %s: %s", r.path, err)
+		}
+	}, r.interval, stopCh)
+}
+
+// reload puts the changes of the configuration file since it was last read into effect.
+func (r *configReloader) reload() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		configReloadsCounterVec.WithLabelValues(configReloadResultFailed).Inc()
+		return fmt.Errorf("failed to open cloud config: %w", err)
+	}
+	if bytes.Equal(data, r.data) {
+		return nil
+	}
+	// An invalid or rejected file is reported once, not on every reload until it changes again
+	r.data = data
+
+	next := &options.Config{}
+	if err = applyConfig(next, r.flags); err != nil {
+		configReloadsCounterVec.WithLabelValues(configReloadResultFailed).Inc()
+		return err
+	}
+	next.Flags = r.options.Flags
+	if err = loadConfig(next, bytes.NewReader(data)); err != nil {
+		configReloadsCounterVec.WithLabelValues(configReloadResultFailed).Inc()
+		return err
+	}
+	cfg, err := effectiveConfig(next)
+	if err != nil {
+		configReloadsCounterVec.WithLabelValues(configReloadResultFailed).Inc()
+		return err
+	}
+
+	changes, err := diffConfig(r.applied, cfg)
+	if err != nil {
+		configReloadsCounterVec.WithLabelValues(configReloadResultFailed).Inc()
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	var unsafe []string
+	for _, change := range changes {
+		if !reloadableSettings.Has(change.path) {
+			unsafe = append(unsafe, change.path)
+		}
+	}
+	if len(unsafe) > 0 {
+		configReloadsCounterVec.WithLabelValues(configReloadResultRejected).Inc()
+		return fmt.Errorf("rejected changes of %s, which require a restart of the CCM, keeping generation %d:\n%s",
+			strings.Join(unsafe, ", "), r.generation, formatConfigChanges(changes))
+	}
+
+	r.options.Reload(next.Reloadable)
+	r.applied = cfg
+	r.generation++
+	configGenerationGauge.Set(float64(r.generation))
+	configReloadsCounterVec.WithLabelValues(configReloadResultApplied).Inc()
+	klog.Infof("Applied generation %d of cloud config %s:\n%s", r.generation, r.path, formatConfigChanges(changes))
+	return nil
+}
+
+// diffConfig returns the settings that differ between old and updated, sorted by their path.
+func diffConfig(old, updated *CloudConfig) ([]configChange, error) {
+	oldSettings, err := flattenConfig(old)
+	if err != nil {
+		return nil, err
+	}
+	updatedSettings, err := flattenConfig(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := sets.KeySet(oldSettings).Union(sets.KeySet(updatedSettings))
+	changes := []configChange{}
+	for _, path := range sets.List(paths) {
+		oldValue, ok := oldSettings[path]
+		if !ok {
+			oldValue = "null"
+		}
+		updatedValue, ok := updatedSettings[path]
+		if !ok {
+			updatedValue = "null"
+		}
+		if oldValue != updatedValue {
+			changes = append(changes, configChange{path: path, old: oldValue, new: updatedValue})
+		}
+	}
+	return changes, nil
+}
+
+// flattenConfig returns the settings of cfg as JSON by their path in the configuration file, such
+// as loadBalancers.nodeBalancerTags.
+func flattenConfig(cfg *CloudConfig) (map[string]string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err = json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	settings := map[string]string{}
+	var flatten func(prefix string, value any) error
+	flatten = func(prefix string, value any) error {
+		if section, ok := value.(map[string]any); ok {
+			for key, value := range section {
+				if err := flatten(prefix+"."+key, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		settings[strings.TrimPrefix(prefix, ".")] = string(data)
+		return nil
+	}
+	if err = flatten("", tree); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func formatConfigChanges(changes []configChange) string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		lines = append(lines, "  "+change.String())
+	}
+	return strings.Join(lines, "\n")
+}
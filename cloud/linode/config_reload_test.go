@@ -0,0 +1,101 @@
+package linode
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_configReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cloud-config.yaml")
+	write := func(config string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	}
+	write(testConfig)
+
+	opts := &options.Config{NodeBalancerPrefix: "ccm"}
+	opts.Flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.Flags.StringVar(&opts.NodeBalancerPrefix, "nodebalancer-prefix", "ccm", "")
+	require.NoError(t, opts.Flags.Parse([]string{"--nodebalancer-prefix=flag"}))
+	t.Setenv(routesCacheTTLEnv, "90")
+
+	reloader, err := newConfigReloader(opts, path)
+	require.NoError(t, err)
+	assert.Equal(t, "flag", opts.NodeBalancerPrefix)
+	assert.True(t, opts.Current().HostnameOnlyIngress)
+	assert.InDelta(t, 1, testutil.ToFloat64(configGenerationGauge), 0)
+
+	reloadable := strings.Replace(testConfig, "hostnameOnlyIngress: true", "hostnameOnlyIngress: false\n  nodeBalancerTags: [team-a]", 1) +
+		"  defaultNodeBalancerType: premium\n"
+
+	t.Run("applies changes of reloadable settings", func(t *testing.T) {
+		write(reloadable)
+		require.NoError(t, reloader.reload())
+
+		current := opts.Current()
+		assert.False(t, current.HostnameOnlyIngress)
+		assert.Equal(t, []string{"team-a"}, current.NodeBalancerTags)
+		assert.Equal(t, "premium", current.DefaultNBType)
+		assert.Equal(t, 10*time.Minute, current.MetadataTTL)
+		assert.Equal(t, 90*time.Second, current.RoutesCacheTTL)
+		assert.Equal(t, 10*time.Minute, (&nodeController{options: opts}).ttl())
+		assert.Equal(t, 2, reloader.generation)
+		assert.InDelta(t, 2, testutil.ToFloat64(configGenerationGauge), 0)
+	})
+
+	t.Run("rejects changes of other settings", func(t *testing.T) {
+		rejected := testutil.ToFloat64(configReloadsCounterVec.WithLabelValues(configReloadResultRejected))
+		write(strings.NewReplacer("vpcNames: [prod]", "vpcNames: [staging]", "team-a", "team-b").Replace(reloadable))
+		err := reloader.reload()
+		require.ErrorContains(t, err, "rejected changes of routes.vpcNames")
+		require.ErrorContains(t, err, `routes.vpcNames: ["prod"] -> ["staging"]`)
+
+		assert.Equal(t, []string{"team-a"}, opts.Current().NodeBalancerTags)
+		assert.Equal(t, []string{"prod"}, opts.VPCNames)
+		assert.Equal(t, 2, reloader.generation)
+		assert.InDelta(t, rejected+1, testutil.ToFloat64(configReloadsCounterVec.WithLabelValues(configReloadResultRejected)), 0)
+
+		// The rejected file is not reported again until it changes
+		require.NoError(t, reloader.reload())
+	})
+
+	t.Run("ignores settings overridden by flags or the environment", func(t *testing.T) {
+		write(strings.NewReplacer("cacheTTL: 2m", "cacheTTL: 5m", "nodeBalancerPrefix: prod", "nodeBalancerPrefix: staging").Replace(reloadable))
+		require.NoError(t, reloader.reload())
+		assert.Equal(t, 90*time.Second, opts.Current().RoutesCacheTTL)
+		assert.Equal(t, 2, reloader.generation)
+	})
+
+	t.Run("keeps the configuration when the file is invalid", func(t *testing.T) {
+		write(reloadable + "bogus: true\n")
+		require.ErrorContains(t, reloader.reload(), "bogus")
+		assert.False(t, opts.Current().HostnameOnlyIngress)
+		assert.Equal(t, 2, reloader.generation)
+	})
+}
+
+func Test_diffConfig(t *testing.T) {
+	old := &CloudConfig{Routes: RoutesConfig{VPCNames: []string{"prod"}}}
+	updated := &CloudConfig{
+		Routes:        RoutesConfig{VPCNames: []string{"prod", "staging"}},
+		LoadBalancers: LoadBalancersConfig{Stats: NodeBalancerStatsConfig{MaxServices: ptr.To(10)}},
+	}
+
+	changes, err := diffConfig(old, updated)
+	require.NoError(t, err)
+	assert.Equal(t, []configChange{
+		{path: "loadBalancers.stats.maxServices", old: "null", new: "10"},
+		{path: "routes.vpcNames", old: `["prod"]`, new: `["prod","staging"]`},
+	}, changes)
+}
@@ -0,0 +1,147 @@
+package linode
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+const testConfig = `
+apiVersion: ccm.linode.com/v1alpha1
+kind: LinodeCloudConfig
+linodeAPI:
+  requestTimeout: 20s
+  burst: 40
+  methodRequestsPerSecond:
+    ListInstances: 2.5
+routes:
+  cacheTTL: 2m
+  vpcNames: [prod]
+nodes:
+  metadataTTL: 10m
+  externalSubnet: 10.0.0.0/8
+loadBalancers:
+  nodeBalancerPrefix: prod
+  hostnameOnlyIngress: true
+  stats:
+    enabled: true
+    maxServices: 10
+`
+
+func Test_loadConfig(t *testing.T) {
+	var opts *options.Config
+	reset := func() {
+		opts = &options.Config{LinodeAPIBurst: 20, NodeBalancerPrefix: "ccm"}
+		opts.Flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		opts.Flags.StringVar(&opts.NodeBalancerPrefix, "nodebalancer-prefix", "ccm", "")
+		opts.Flags.IntVar(&opts.LinodeAPIBurst, "linode-api-burst", 20, "")
+	}
+
+	t.Run("defaults without a file", func(t *testing.T) {
+		reset()
+		require.NoError(t, loadConfig(opts, nil))
+		assert.Equal(t, defaultRoutesCacheTTL, opts.RoutesCacheTTL)
+		assert.Equal(t, defaultMetadataTTL, opts.MetadataTTL)
+		assert.Equal(t, defaultTokenFileCacheTTL, opts.LinodeAPITokenCacheTTL)
+		assert.Equal(t, "ccm", opts.NodeBalancerPrefix)
+	})
+
+	t.Run("applies the file", func(t *testing.T) {
+		reset()
+		require.NoError(t, loadConfig(opts, strings.NewReader(testConfig)))
+		assert.Equal(t, 20*time.Second, opts.LinodeAPIRequestTimeout)
+		assert.Equal(t, 40, opts.LinodeAPIBurst)
+		assert.Equal(t, map[string]string{"ListInstances": "2.5"}, opts.LinodeAPIMethodRequestsPerSecond)
+		assert.Equal(t, 2*time.Minute, opts.RoutesCacheTTL)
+		assert.Equal(t, []string{"prod"}, opts.VPCNames)
+		assert.Equal(t, 10*time.Minute, opts.MetadataTTL)
+		assert.Equal(t, "10.0.0.0/8", opts.LinodeExternalNetwork.String())
+		assert.Equal(t, "prod", opts.NodeBalancerPrefix)
+		assert.True(t, opts.HostnameOnlyIngress)
+		assert.True(t, opts.EnableNodeBalancerStats)
+		assert.Equal(t, 10, opts.NodeBalancerStatsMaxServices)
+	})
+
+	t.Run("environment overrides the file and flags override both", func(t *testing.T) {
+		reset()
+		t.Setenv(routesCacheTTLEnv, "30")
+		t.Setenv(hostnameOnlyIngressEnv, "false")
+		require.NoError(t, opts.Flags.Parse([]string{"--nodebalancer-prefix=flag"}))
+		require.NoError(t, loadConfig(opts, strings.NewReader(testConfig)))
+		assert.Equal(t, 30*time.Second, opts.RoutesCacheTTL)
+		assert.False(t, opts.HostnameOnlyIngress)
+		assert.Equal(t, "flag", opts.NodeBalancerPrefix)
+		assert.Equal(t, 40, opts.LinodeAPIBurst)
+	})
+
+	t.Run("invalid environment values keep their previous value", func(t *testing.T) {
+		reset()
+		t.Setenv(tokenCacheTTLEnv, "invalid")
+		t.Setenv(k8sNodeCacheTTLEnv, "0")
+		t.Setenv(routesCacheTTLEnv, "-5")
+		t.Setenv(hostnameOnlyIngressEnv, "banana")
+		require.NoError(t, loadConfig(opts, strings.NewReader(testConfig)))
+		assert.Equal(t, defaultTokenFileCacheTTL, opts.LinodeAPITokenCacheTTL)
+		assert.Equal(t, defaultK8sNodeCacheTTL, opts.K8sNodeCacheTTL)
+		assert.Equal(t, 2*time.Minute, opts.RoutesCacheTTL)
+		assert.True(t, opts.HostnameOnlyIngress)
+	})
+
+	testcases := []struct {
+		name   string
+		config string
+		env    map[string]string
+		err    string
+	}{
+		{name: "unknown version", config: "apiVersion: v1\nkind: LinodeCloudConfig", err: "unsupported cloud config"},
+		{name: "unknown setting", config: testConfig + "bogus: true\n", err: "bogus"},
+		{name: "wrong type", config: testConfig + "serviceWebhook:\n  port: http\n", err: "failed to parse"},
+		{name: "non-positive ttl", config: "apiVersion: ccm.linode.com/v1alpha1\nkind: LinodeCloudConfig\nnodes:\n  cacheTTL: 0s", err: "nodes.cacheTTL must be positive"},
+		{name: "invalid subnet", config: "apiVersion: ccm.linode.com/v1alpha1\nkind: LinodeCloudConfig\nnodes:\n  externalSubnet: 10.0.0.0", err: "unable to parse"},
+		{name: "invalid subnet in environment", env: map[string]string{externalSubnetEnv: "banana"}, err: externalSubnetEnv},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			reset()
+			for env, value := range tc.env {
+				t.Setenv(env, value)
+			}
+			require.ErrorContains(t, loadConfig(opts, strings.NewReader(tc.config)), tc.err)
+		})
+	}
+}
+
+func TestPrintEffectiveConfig(t *testing.T) {
+	opts := &options.Config{LinodeAPIRetryMaxDelay: time.Minute}
+
+	path := filepath.Join(t.TempDir(), "cloud-config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+
+	var out bytes.Buffer
+	require.NoError(t, PrintEffectiveConfig(opts, path, &out))
+
+	// The effective configuration is a complete configuration file that loads to the same options
+	cfg := &CloudConfig{}
+	require.NoError(t, yaml.UnmarshalStrict(out.Bytes(), cfg))
+	assert.Equal(t, ConfigKind, cfg.Kind)
+	assert.Equal(t, map[string]float64{"ListInstances": 2.5}, cfg.LinodeAPI.MethodRequestsPerSecond)
+	assert.Equal(t, time.Minute, cfg.LinodeAPI.RetryMaxDelay.Duration)
+	assert.Equal(t, "10.0.0.0/8", *cfg.Nodes.ExternalSubnet)
+	assert.Equal(t, "prod", *cfg.LoadBalancers.NodeBalancerPrefix)
+
+	loaded := &options.Config{}
+	require.NoError(t, loadConfig(loaded, bytes.NewReader(out.Bytes())))
+	assert.Equal(t, opts, loaded)
+
+	require.Error(t, PrintEffectiveConfig(&options.Config{}, filepath.Join(t.TempDir(), "missing.yaml"), &out))
+}
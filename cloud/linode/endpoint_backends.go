@@ -0,0 +1,95 @@
+package linode
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+// resolveEndpointAwareBackends reports whether the NodeBalancer backends of service should be
+// limited to nodes hosting its ready endpoints.
+func resolveEndpointAwareBackends(opts *options.Config, service *v1.Service) bool {
+	if enabled := getServiceBoolAnnotation(service, annotations.AnnLinodeEndpointAwareBackends); enabled != nil {
+		return *enabled
+	}
+	return opts.EnableEndpointAwareBackends
+}
+
+// filterNodesByEndpoints returns the nodes that host ready endpoints of service when endpoint
+// aware backends are enabled for it. All nodes are returned if none of them host a ready
+// endpoint, e.g. while the workload is rolled out, or if the EndpointSlices can't be listed.
+func (l *loadbalancers) filterNodesByEndpoints(ctx context.Context, service *v1.Service, nodes []*v1.Node) []*v1.Node {
+	if !resolveEndpointAwareBackends(l.options, service) {
+		return nodes
+	}
+
+	slices, err := l.listEndpointSlices(ctx, service)
+	if err != nil {
+		klog.Warningf("failed to list EndpointSlices, using all nodes as backends for service (%s): %s", getServiceNn(service), err)
+		return nodes
+	}
+
+	endpointNodes := readyEndpointNodeNames(slices...)
+	filtered := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if endpointNodes.Has(node.Name) {
+			filtered = append(filtered, node)
+		}
+	}
+
+	if len(filtered) == 0 {
+		klog.Infof("No node hosts a ready endpoint of service (%s), using all nodes as backends", getServiceNn(service))
+		return nodes
+	}
+	return filtered
+}
+
+// listEndpointSlices returns the EndpointSlices of service from the informer cache of the
+// ServiceResyncController once it is synced, and from the API before that or without it, as when
+// planning.
+func (l *loadbalancers) listEndpointSlices(ctx context.Context, service *v1.Service) ([]discoveryv1.EndpointSlice, error) {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service.Name})
+	if l.endpointSliceInformer != nil && l.endpointSliceInformer.Informer().HasSynced() {
+		cached, err := l.endpointSliceInformer.Lister().EndpointSlices(service.Namespace).List(selector)
+		if err != nil {
+			return nil, err
+		}
+		slices := make([]discoveryv1.EndpointSlice, 0, len(cached))
+		for _, slice := range cached {
+			slices = append(slices, *slice)
+		}
+		return slices, nil
+	}
+
+	if err := l.retrieveKubeClient(); err != nil {
+		return nil, err
+	}
+	list, err := l.kubeClient.DiscoveryV1().EndpointSlices(service.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// readyEndpointNodeNames returns the names of the nodes hosting the ready endpoints in slices.
+func readyEndpointNodeNames(slices ...discoveryv1.EndpointSlice) sets.Set[string] {
+	nodeNames := sets.New[string]()
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			// A nil ready condition is to be interpreted as ready
+			if endpoint.NodeName == nil || (endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready) {
+				continue
+			}
+			nodeNames.Insert(*endpoint.NodeName)
+		}
+	}
+	return nodeNames
+}
@@ -0,0 +1,143 @@
+package linode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_filterNodesByEndpoints(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-3"}},
+	}
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc-abc",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "svc"},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{NodeName: ptr.To("node-1")},
+			{NodeName: ptr.To("node-2"), Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)}},
+			{NodeName: ptr.To("node-3"), Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)}},
+		},
+	}
+	otherSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-abc",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "other"},
+		},
+		Endpoints: []discoveryv1.Endpoint{{NodeName: ptr.To("node-2")}},
+	}
+
+	testcases := []struct {
+		name        string
+		serviceName string
+		annotations map[string]string
+		expected    []string
+	}{
+		{
+			name:        "disabled",
+			serviceName: "svc",
+			expected:    []string{"node-1", "node-2", "node-3"},
+		},
+		{
+			name:        "enabled",
+			serviceName: "svc",
+			annotations: map[string]string{annotations.AnnLinodeEndpointAwareBackends: "true"},
+			expected:    []string{"node-1", "node-3"},
+		},
+		{
+			name:        "enabled without ready endpoints",
+			serviceName: "no-endpoints",
+			annotations: map[string]string{annotations.AnnLinodeEndpointAwareBackends: "true"},
+			expected:    []string{"node-1", "node-2", "node-3"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeClient := fake.NewClientset(slice, otherSlice)
+			lb := &loadbalancers{kubeClient: kubeClient, options: &options.Config{}}
+			service := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.serviceName, Namespace: "default", Annotations: tc.annotations},
+			}
+
+			names := []string{}
+			for _, node := range lb.filterNodesByEndpoints(t.Context(), service, nodes) {
+				names = append(names, node.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+
+	t.Run("reads the informer cache", func(t *testing.T) {
+		factory := informers.NewSharedInformerFactory(fake.NewClientset(slice, otherSlice), 0)
+		endpointSliceInformer := factory.Discovery().V1().EndpointSlices()
+		endpointSliceInformer.Informer()
+		factory.Start(t.Context().Done())
+		factory.WaitForCacheSync(t.Context().Done())
+
+		// The API has no EndpointSlices, so every node would be a backend without the cache
+		lb := &loadbalancers{kubeClient: fake.NewClientset(), options: &options.Config{}, endpointSliceInformer: endpointSliceInformer}
+		service := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:        "svc",
+			Namespace:   "default",
+			Annotations: map[string]string{annotations.AnnLinodeEndpointAwareBackends: "true"},
+		}}
+
+		names := []string{}
+		for _, node := range lb.filterNodesByEndpoints(t.Context(), service, nodes) {
+			names = append(names, node.Name)
+		}
+		assert.Equal(t, []string{"node-1", "node-3"}, names)
+	})
+}
+
+func Test_serviceResyncController_enqueueEndpointSliceService(t *testing.T) {
+	kubeClient := fake.NewClientset()
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+
+	newService := func(name string, ann map[string]string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: ann},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			}},
+		}
+	}
+	require.NoError(t, serviceInformer.Informer().GetStore().Add(newService("aware", map[string]string{annotations.AnnLinodeEndpointAwareBackends: "true"})))
+	require.NoError(t, serviceInformer.Informer().GetStore().Add(newService("unaware", nil)))
+
+	controller := newServiceResyncController(&loadbalancers{options: &options.Config{}}, serviceInformer, factory.Core().V1().Nodes(), factory.Discovery().V1().EndpointSlices(), kubeClient)
+	defer controller.queue.ShutDown()
+
+	for _, name := range []string{"aware", "unaware"} {
+		controller.enqueueEndpointSliceService(&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name + "-abc",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: name},
+			},
+		})
+	}
+
+	require.Equal(t, 1, controller.queue.Len())
+	key, _ := controller.queue.Get()
+	assert.Equal(t, "default/aware", key)
+}
@@ -9,11 +9,18 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
 )
 
 const apiVersion = "v4"
@@ -23,6 +30,7 @@ type fakeAPI struct {
 	nb     map[string]*linodego.NodeBalancer
 	nbc    map[string]*linodego.NodeBalancerConfig
 	nbn    map[string]*linodego.NodeBalancerNode
+	nbs    map[string]*linodego.NodeBalancerStats
 	fw     map[int]*linodego.Firewall               // map of firewallID -> firewall
 	fwd    map[int]map[int]*linodego.FirewallDevice // map of firewallID -> firewallDeviceID:FirewallDevice
 	nbvpcc map[string]*linodego.NodeBalancerVPCConfig
@@ -49,6 +57,7 @@ func newFake(t *testing.T) *fakeAPI {
 		nb:       make(map[string]*linodego.NodeBalancer),
 		nbc:      make(map[string]*linodego.NodeBalancerConfig),
 		nbn:      make(map[string]*linodego.NodeBalancerNode),
+		nbs:      make(map[string]*linodego.NodeBalancerStats),
 		fw:       make(map[int]*linodego.Firewall),
 		fwd:      make(map[int]map[int]*linodego.FirewallDevice),
 		nbvpcc:   make(map[string]*linodego.NodeBalancerVPCConfig),
@@ -61,6 +70,27 @@ func newFake(t *testing.T) *fakeAPI {
 	return fake
 }
 
+// newFakeLinodeClient returns a Linode client for fakeLinode, which is served until the test ends.
+func newFakeLinodeClient(t *testing.T, fakeLinode *fakeAPI) *linodego.Client {
+	t.Helper()
+
+	ts := httptest.NewServer(fakeLinode)
+	t.Cleanup(ts.Close)
+	linodeClient, err := linodego.NewClient(http.DefaultClient)
+	require.NoError(t, err)
+	linodeClient.SetBaseURL(ts.URL)
+	return &linodeClient
+}
+
+// newFakeLoadBalancers returns the load balancers of a CCM in us-west backed by fakeLinode and kubeClient.
+func newFakeLoadBalancers(t *testing.T, fakeLinode *fakeAPI, kubeClient kubernetes.Interface, opts *options.Config) *loadbalancers {
+	t.Helper()
+
+	lb := newLoadbalancers(newFakeLinodeClient(t, fakeLinode), "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
+	lb.kubeClient = kubeClient
+	return lb
+}
+
 func (f *fakeAPI) ResetRequests() {
 	f.requests = make(map[fakeRequest]struct{})
 }
@@ -111,7 +141,8 @@ func (f *fakeAPI) setupRoutes() {
 			}
 			for _, n := range f.nb {
 				if (n.Label != nil && fs["label"] != "" && *n.Label == fs["label"]) ||
-					(fs["ipv4"] != "" && n.IPv4 != nil && *n.IPv4 == fs["ipv4"]) {
+					(fs["ipv4"] != "" && n.IPv4 != nil && *n.IPv4 == fs["ipv4"]) ||
+					(fs["tags"] != "" && slices.Contains(n.Tags, fs["tags"])) {
 					data = append(data, *n)
 				}
 			}
@@ -193,6 +224,16 @@ func (f *fakeAPI) setupRoutes() {
 		_, _ = w.Write(rr)
 	})
 
+	f.mux.HandleFunc("GET /v4/nodebalancers/{nodeBalancerId}/stats", func(w http.ResponseWriter, r *http.Request) {
+		stats, found := f.nbs[r.PathValue("nodeBalancerId")]
+		if !found {
+			stats = &linodego.NodeBalancerStats{}
+		}
+
+		rr, _ := json.Marshal(stats)
+		_, _ = w.Write(rr)
+	})
+
 	f.mux.HandleFunc("GET /v4/nodebalancers/{nodeBalancerId}/firewalls", func(w http.ResponseWriter, r *http.Request) {
 		nodebalancerID, err := strconv.Atoi(r.PathValue("nodeBalancerId"))
 		if err != nil {
@@ -221,14 +262,15 @@ func (f *fakeAPI) setupRoutes() {
 		_, _ = w.Write(resp)
 	})
 
-	// TODO: note that we discard `nodeBalancerId`
 	f.mux.HandleFunc("GET /v4/nodebalancers/{nodeBalancerId}/configs", func(w http.ResponseWriter, r *http.Request) {
 		res := 0
 		data := []linodego.NodeBalancerConfig{}
 		filter := r.Header.Get("X-Filter")
 		if filter == "" {
 			for _, n := range f.nbc {
-				data = append(data, *n)
+				if strconv.Itoa(n.NodeBalancerID) == r.PathValue("nodeBalancerId") {
+					data = append(data, *n)
+				}
 			}
 		} else {
 			var fs map[string]string
@@ -352,6 +394,9 @@ func (f *fakeAPI) setupRoutes() {
 		}
 
 		ip := net.IPv4(byte(rand.Intn(100)), byte(rand.Intn(100)), byte(rand.Intn(100)), byte(rand.Intn(100))).String()
+		if nbco.IPv4 != nil {
+			ip = *nbco.IPv4
+		}
 		hostname := fmt.Sprintf("nb-%s.%s.linode.com", strings.Replace(ip, ".", "-", 4), strings.ToLower(nbco.Region))
 		nb := linodego.NodeBalancer{
 			ID:       rand.Intn(9999),
@@ -360,6 +405,7 @@ func (f *fakeAPI) setupRoutes() {
 			IPv4:     &ip,
 			Hostname: &hostname,
 			Tags:     nbco.Tags,
+			Type:     nbco.Type,
 		}
 
 		if nbco.ClientConnThrottle != nil {
@@ -11,16 +11,16 @@ import (
 )
 
 type healthChecker struct {
-	period       time.Duration
-	linodeClient client.Client
-	stopCh       chan<- struct{}
+	period      time.Duration
+	credentials []apiCredential
+	stopCh      chan<- struct{}
 }
 
-func newHealthChecker(client client.Client, period time.Duration, stopCh chan<- struct{}) *healthChecker {
+func newHealthChecker(credentials []apiCredential, period time.Duration, stopCh chan<- struct{}) *healthChecker {
 	return &healthChecker{
-		period:       period,
-		linodeClient: client,
-		stopCh:       stopCh,
+		period:      period,
+		credentials: credentials,
+		stopCh:      stopCh,
 	}
 }
 
@@ -41,19 +41,21 @@ func (r *healthChecker) do(ctx context.Context) {
 		return
 	}
 
-	authenticated, err := client.CheckClientAuthenticated(ctx, r.linodeClient)
-	if err != nil {
-		klog.Warningf("unable to determine linode client authentication status: %s", err.Error())
-		return
-	}
+	for _, credential := range r.credentials {
+		authenticated, err := client.CheckClientAuthenticated(ctx, credential.client)
+		if err != nil {
+			klog.Warningf("unable to determine linode client authentication status of %s: %s", credential, err.Error())
+			continue
+		}
 
-	if !authenticated {
-		klog.Error("detected invalid linode api token: stopping controllers")
+		if !authenticated {
+			klog.Errorf("detected invalid linode api %s: stopping controllers", credential)
 
-		close(r.stopCh)
-		r.stopCh = nil
-		return
-	}
+			close(r.stopCh)
+			r.stopCh = nil
+			return
+		}
 
-	klog.Info("linode api token is healthy")
+		klog.Infof("linode api %s is healthy", credential)
+	}
 }
@@ -48,7 +48,7 @@ func testSucceedingCallsToLinodeAPIHappenStopSignalNotFired(t *testing.T, client
 
 	client.EXPECT().GetProfile(gomock.Any()).Times(2).Return(&linodego.Profile{}, nil)
 
-	hc := newHealthChecker(client, 1*time.Second, writableStopCh)
+	hc := newHealthChecker([]apiCredential{{name: defaultCredential, client: client}}, 1*time.Second, writableStopCh)
 
 	defer close(readableStopCh)
 	go hc.Run(readableStopCh)
@@ -71,7 +71,7 @@ func testFailingCallsToLinodeAPIHappenStopSignalFired(t *testing.T, client *mock
 
 	client.EXPECT().GetProfile(gomock.Any()).Times(1).Return(&linodego.Profile{}, nil)
 
-	hc := newHealthChecker(client, 1*time.Second, writableStopCh)
+	hc := newHealthChecker([]apiCredential{{name: defaultCredential, client: client}}, 1*time.Second, writableStopCh)
 
 	defer close(readableStopCh)
 	go hc.Run(readableStopCh)
@@ -106,7 +106,7 @@ func testErrorCallsToLinodeAPIHappenStopSignalNotFired(t *testing.T, client *moc
 
 	client.EXPECT().GetProfile(gomock.Any()).Times(1).Return(&linodego.Profile{}, nil)
 
-	hc := newHealthChecker(client, 1*time.Second, writableStopCh)
+	hc := newHealthChecker([]apiCredential{{name: defaultCredential, client: client}}, 1*time.Second, writableStopCh)
 
 	defer close(readableStopCh)
 	go hc.Run(readableStopCh)
@@ -143,3 +143,29 @@ func testErrorCallsToLinodeAPIHappenStopSignalNotFired(t *testing.T, client *moc
 	default:
 	}
 }
+
+func TestHealthCheckCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defaultClient := mocks.NewMockClient(ctrl)
+	routesClient := mocks.NewMockClient(ctrl)
+
+	writableStopCh := make(chan struct{})
+	hc := newHealthChecker([]apiCredential{
+		{name: defaultCredential, source: "test", client: defaultClient},
+		{name: routesCredential, source: "test", client: routesClient},
+	}, time.Second, writableStopCh)
+
+	// Every token is checked, and an invalid one stops the controllers even if the others are valid
+	defaultClient.EXPECT().GetProfile(gomock.Any()).Times(2).Return(&linodego.Profile{}, nil)
+	routesClient.EXPECT().GetProfile(gomock.Any()).Return(&linodego.Profile{}, nil)
+	hc.do(t.Context())
+
+	routesClient.EXPECT().GetProfile(gomock.Any()).Return(nil, &linodego.Error{Code: 401, Message: "Invalid Token"})
+	hc.do(t.Context())
+
+	select {
+	case <-writableStopCh:
+	default:
+		t.Error("healthChecker did not send stop signal")
+	}
+}
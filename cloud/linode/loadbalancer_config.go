@@ -0,0 +1,315 @@
+package linode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/apis/v1alpha1"
+)
+
+// resolveLoadBalancerConfig returns the service with the settings of its referenced
+// LinodeLoadBalancerConfig merged into its annotations. Annotations already present on the
+// service win over the config. The service is returned unchanged if it references no config.
+func (l *loadbalancers) resolveLoadBalancerConfig(ctx context.Context, service *v1.Service) (*v1.Service, error) {
+	resolved, _, err := l.resolveLoadBalancerConfigGeneration(ctx, service)
+	return resolved, err
+}
+
+// resolveLoadBalancerConfigGeneration is resolveLoadBalancerConfig, but also returns the
+// generation of the merged config, or zero if the service references none.
+func (l *loadbalancers) resolveLoadBalancerConfigGeneration(ctx context.Context, service *v1.Service) (*v1.Service, int64, error) {
+	name, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerConfig]
+	if !ok || name == "" {
+		return service, 0, nil
+	}
+
+	lbConfig, err := l.getLoadBalancerConfig(ctx, service.Namespace, name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get LinodeLoadBalancerConfig %s/%s for service (%s): %w", service.Namespace, name, getServiceNn(service), err)
+	}
+
+	configAnnotations, err := loadBalancerConfigAnnotations(&lbConfig.Spec)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid LinodeLoadBalancerConfig %s/%s: %w", service.Namespace, name, err)
+	}
+
+	resolved := service.DeepCopy()
+	if resolved.Annotations == nil {
+		resolved.Annotations = make(map[string]string, len(configAnnotations))
+	}
+	for key, value := range configAnnotations {
+		if _, exists := resolved.Annotations[key]; !exists {
+			resolved.Annotations[key] = value
+		}
+	}
+	return resolved, lbConfig.Generation, nil
+}
+
+// getLoadBalancerConfig returns a LinodeLoadBalancerConfig from the informer cache of the
+// ServiceResyncController once it is synced, and from the API before that or without it.
+func (l *loadbalancers) getLoadBalancerConfig(ctx context.Context, namespace, name string) (*v1alpha1.LinodeLoadBalancerConfig, error) {
+	var obj runtime.Object
+	if l.loadBalancerConfigInformer != nil && l.loadBalancerConfigInformer.Informer().HasSynced() {
+		cached, err := l.loadBalancerConfigInformer.Lister().ByNamespace(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		obj = cached
+	} else {
+		if err := l.retrieveDynamicClient(); err != nil {
+			return nil, err
+		}
+		fetched, err := l.dynamicClient.Resource(v1alpha1.LinodeLoadBalancerConfigGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		obj = fetched
+	}
+	return toLoadBalancerConfig(obj)
+}
+
+// toLoadBalancerConfig converts a LinodeLoadBalancerConfig read through a dynamic client.
+func toLoadBalancerConfig(obj runtime.Object) (*v1alpha1.LinodeLoadBalancerConfig, error) {
+	content, ok := obj.(runtime.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected LinodeLoadBalancerConfig type %T", obj)
+	}
+	lbConfig := &v1alpha1.LinodeLoadBalancerConfig{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content.UnstructuredContent(), lbConfig); err != nil {
+		return nil, err
+	}
+	return lbConfig, nil
+}
+
+// recordLoadBalancerConfigStatus stores the outcome of reconciling service in the status of
+// its referenced LinodeLoadBalancerConfig. generation is the generation of the config that was
+// applied, it is only recorded if the reconcile succeeded. Failures are logged and otherwise
+// ignored so that status reporting never blocks NodeBalancer reconciliation. The status is left
+// untouched when the outcome is the same as last time, so that resyncs don't write to the API server.
+func (l *loadbalancers) recordLoadBalancerConfigStatus(ctx context.Context, service *v1.Service, generation int64, nodeBalancerID int, reconcileErr error) {
+	l.updateLoadBalancerConfigStatus(ctx, service, func(lbConfig *v1alpha1.LinodeLoadBalancerConfig) {
+		entry := v1alpha1.ServiceStatus{
+			Name:           service.Name,
+			NodeBalancerID: nodeBalancerID,
+			LastSyncTime:   metav1.Now(),
+		}
+		if reconcileErr != nil {
+			entry.Error = reconcileErr.Error()
+		} else {
+			entry.ObservedGeneration = generation
+		}
+		statuses := lbConfig.Status.Services
+		for i := range statuses {
+			if statuses[i].Name != service.Name {
+				continue
+			}
+			if entry.NodeBalancerID == 0 {
+				entry.NodeBalancerID = statuses[i].NodeBalancerID
+			}
+			if entry.ObservedGeneration == 0 {
+				entry.ObservedGeneration = statuses[i].ObservedGeneration
+			}
+			if entry.NodeBalancerID == statuses[i].NodeBalancerID && entry.Error == statuses[i].Error &&
+				entry.ObservedGeneration == statuses[i].ObservedGeneration {
+				return
+			}
+			statuses[i] = entry
+			return
+		}
+		lbConfig.Status.Services = append(statuses, entry)
+	})
+}
+
+// removeLoadBalancerConfigStatus drops service from the status of its referenced LinodeLoadBalancerConfig.
+func (l *loadbalancers) removeLoadBalancerConfigStatus(ctx context.Context, service *v1.Service) {
+	l.updateLoadBalancerConfigStatus(ctx, service, func(lbConfig *v1alpha1.LinodeLoadBalancerConfig) {
+		lbConfig.Status.Services = slices.DeleteFunc(lbConfig.Status.Services, func(status v1alpha1.ServiceStatus) bool {
+			return status.Name == service.Name
+		})
+	})
+}
+
+// updateLoadBalancerConfigStatus applies mutate to the LinodeLoadBalancerConfig referenced by
+// service and writes its status back, unless mutate left the status as it was.
+func (l *loadbalancers) updateLoadBalancerConfigStatus(ctx context.Context, service *v1.Service, mutate func(*v1alpha1.LinodeLoadBalancerConfig)) {
+	name, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerConfig]
+	if !ok || name == "" {
+		return
+	}
+	if err := l.retrieveDynamicClient(); err != nil {
+		klog.Errorf("failed to update status of LinodeLoadBalancerConfig %s/%s: %s", service.Namespace, name, err)
+		return
+	}
+
+	resource := l.dynamicClient.Resource(v1alpha1.LinodeLoadBalancerConfigGVR).Namespace(service.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		lbConfig := &v1alpha1.LinodeLoadBalancerConfig{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), lbConfig); err != nil {
+			return err
+		}
+		previous := slices.Clone(lbConfig.Status.Services)
+		mutate(lbConfig)
+		if reflect.DeepEqual(previous, lbConfig.Status.Services) {
+			return nil
+		}
+
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(lbConfig)
+		if err != nil {
+			return err
+		}
+		_, err = resource.UpdateStatus(ctx, &unstructured.Unstructured{Object: content}, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		klog.Errorf("failed to update status of LinodeLoadBalancerConfig %s/%s: %s", service.Namespace, name, err)
+	}
+}
+
+// newLoadBalancerConfigInformer returns an informer for LinodeLoadBalancerConfigs, or nil if
+// the CRD isn't installed in the cluster.
+func newLoadBalancerConfigInformer(discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface) informers.GenericInformer {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(v1alpha1.SchemeGroupVersion.String())
+	if err != nil {
+		klog.Infof("not watching LinodeLoadBalancerConfigs: %s", err)
+		return nil
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == v1alpha1.LinodeLoadBalancerConfigResource {
+			return dynamicinformer.NewFilteredDynamicInformer(
+				dynamicClient, v1alpha1.LinodeLoadBalancerConfigGVR, metav1.NamespaceAll, 0, cache.Indexers{}, nil)
+		}
+	}
+	klog.Infof("not watching LinodeLoadBalancerConfigs: resource %s is not served", v1alpha1.LinodeLoadBalancerConfigGVR)
+	return nil
+}
+
+func (l *loadbalancers) retrieveDynamicClient() error {
+	if l.dynamicClient != nil {
+		return nil
+	}
+
+	kubeConfig, err := buildKubeConfig(l.options)
+	if err != nil {
+		return err
+	}
+
+	l.dynamicClient, err = dynamic.NewForConfig(kubeConfig)
+	return err
+}
+
+// loadBalancerConfigAnnotations translates a LinodeLoadBalancerConfigSpec into the service
+// annotations it stands for, so that the config is parsed and validated by the same code paths.
+func loadBalancerConfigAnnotations(spec *v1alpha1.LinodeLoadBalancerConfigSpec) (map[string]string, error) {
+	result := map[string]string{}
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			result[key] = value
+		}
+	}
+	setIfNotNil := func(key string, value *int) {
+		if value != nil {
+			result[key] = strconv.Itoa(*value)
+		}
+	}
+
+	setIfNotEmpty(annotations.AnnLinodeDefaultProtocol, spec.DefaultProtocol)
+	setIfNotEmpty(annotations.AnnLinodeDefaultProxyProtocol, spec.DefaultProxyProtocol)
+	setIfNotEmpty(annotations.AnnLinodeDefaultAlgorithm, spec.DefaultAlgorithm)
+	setIfNotEmpty(annotations.AnnLinodeDefaultStickiness, spec.DefaultStickiness)
+
+	for _, port := range spec.Ports {
+		if port.Port < 1 || port.Port > 65535 {
+			return nil, fmt.Errorf("invalid port %d in ports", port.Port)
+		}
+		portAnnotation := portConfigAnnotation{
+			TLSSecretName: port.TLSSecretName,
+			TLSCertField:  port.TLSCertField,
+			TLSKeyField:   port.TLSKeyField,
+			Protocol:      port.Protocol,
+			ProxyProtocol: port.ProxyProtocol,
+			Algorithm:     port.Algorithm,
+			Stickiness:    port.Stickiness,
+		}
+		if port.UDPCheckPort != nil {
+			portAnnotation.UDPCheckPort = strconv.Itoa(*port.UDPCheckPort)
+		}
+		portJSON, err := json.Marshal(portAnnotation)
+		if err != nil {
+			return nil, err
+		}
+		result[annotations.AnnLinodePortConfigPrefix+strconv.Itoa(port.Port)] = string(portJSON)
+	}
+
+	if hc := spec.HealthCheck; hc != nil {
+		setIfNotEmpty(annotations.AnnLinodeHealthCheckType, hc.Type)
+		setIfNotEmpty(annotations.AnnLinodeCheckPath, hc.Path)
+		setIfNotEmpty(annotations.AnnLinodeCheckBody, hc.Body)
+		setIfNotNil(annotations.AnnLinodeHealthCheckInterval, hc.Interval)
+		setIfNotNil(annotations.AnnLinodeHealthCheckTimeout, hc.Timeout)
+		setIfNotNil(annotations.AnnLinodeHealthCheckAttempts, hc.Attempts)
+		setIfNotNil(annotations.AnnLinodeUDPCheckPort, hc.UDPCheckPort)
+		if hc.Passive != nil {
+			result[annotations.AnnLinodeHealthCheckPassive] = strconv.FormatBool(*hc.Passive)
+		}
+	}
+
+	setIfNotNil(annotations.AnnLinodeThrottle, spec.Throttle)
+	if len(spec.Tags) > 0 {
+		result[annotations.AnnLinodeLoadBalancerTags] = strings.Join(spec.Tags, ",")
+	}
+	setIfNotEmpty(annotations.AnnLinodeNodeBalancerType, spec.NodeBalancerType)
+
+	if fw := spec.Firewall; fw != nil {
+		if fw.ID != nil && fw.ACL != nil {
+			return nil, fmt.Errorf("firewall id and acl are mutually exclusive")
+		}
+		setIfNotNil(annotations.AnnLinodeCloudFirewallID, fw.ID)
+		if fw.ACL != nil {
+			aclJSON, err := json.Marshal(fw.ACL)
+			if err != nil {
+				return nil, err
+			}
+			result[annotations.AnnLinodeCloudFirewallACL] = string(aclJSON)
+		}
+	}
+
+	if vpc := spec.BackendVPC; vpc != nil {
+		setIfNotEmpty(annotations.NodeBalancerBackendVPCName, vpc.VPCName)
+		setIfNotEmpty(annotations.NodeBalancerBackendSubnetName, vpc.SubnetName)
+		setIfNotNil(annotations.NodeBalancerBackendSubnetID, vpc.SubnetID)
+		setIfNotEmpty(annotations.NodeBalancerBackendIPv4Range, vpc.IPv4Range)
+	}
+
+	if vpc := spec.FrontendVPC; vpc != nil {
+		setIfNotEmpty(annotations.NodeBalancerFrontendVPCName, vpc.VPCName)
+		setIfNotEmpty(annotations.NodeBalancerFrontendSubnetName, vpc.SubnetName)
+		setIfNotNil(annotations.NodeBalancerFrontendSubnetID, vpc.SubnetID)
+		setIfNotEmpty(annotations.NodeBalancerFrontendIPv4Range, vpc.IPv4Range)
+		setIfNotEmpty(annotations.NodeBalancerFrontendIPv6Range, vpc.IPv6Range)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,249 @@
+package linode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/apis/v1alpha1"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func newFakeLoadBalancerConfigClient(t *testing.T, configs ...*v1alpha1.LinodeLoadBalancerConfig) *dynamicfake.FakeDynamicClient {
+	t.Helper()
+
+	objs := make([]runtime.Object, 0, len(configs))
+	for _, cfg := range configs {
+		cfg.APIVersion = v1alpha1.SchemeGroupVersion.String()
+		cfg.Kind = v1alpha1.LinodeLoadBalancerConfigKind
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cfg)
+		require.NoError(t, err)
+		objs = append(objs, &unstructured.Unstructured{Object: content})
+	}
+
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			v1alpha1.LinodeLoadBalancerConfigGVR: v1alpha1.LinodeLoadBalancerConfigListKind,
+		},
+		objs...,
+	)
+}
+
+func Test_loadBalancerConfigAnnotations(t *testing.T) {
+	testcases := []struct {
+		name     string
+		spec     v1alpha1.LinodeLoadBalancerConfigSpec
+		expected map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "empty spec",
+			spec:     v1alpha1.LinodeLoadBalancerConfigSpec{},
+			expected: map[string]string{},
+		},
+		{
+			name: "full spec",
+			spec: v1alpha1.LinodeLoadBalancerConfigSpec{
+				DefaultProtocol:  "http",
+				DefaultAlgorithm: "leastconn",
+				Ports: []v1alpha1.PortConfig{
+					{Port: 443, Protocol: "https", TLSSecretName: "tls"},
+				},
+				HealthCheck: &v1alpha1.HealthCheck{
+					Type:     "http",
+					Path:     "/healthz",
+					Interval: ptr.To(10),
+					Passive:  ptr.To(false),
+				},
+				Throttle:         ptr.To(5),
+				Tags:             []string{"a", "b"},
+				NodeBalancerType: "premium",
+				Firewall: &v1alpha1.Firewall{
+					ACL: &v1alpha1.FirewallACL{
+						AllowList: &v1alpha1.NetworkAddresses{IPv4: []string{"1.2.3.4/32"}},
+					},
+				},
+				BackendVPC: &v1alpha1.BackendVPC{VPCName: "vpc", SubnetName: "subnet"},
+			},
+			expected: map[string]string{
+				annotations.AnnLinodeDefaultProtocol:          "http",
+				annotations.AnnLinodeDefaultAlgorithm:         "leastconn",
+				annotations.AnnLinodePortConfigPrefix + "443": `{"tls-secret-name":"tls","protocol":"https","proxy-protocol":"","algorithm":"","stickiness":"","udp-check-port":""}`,
+				annotations.AnnLinodeHealthCheckType:          "http",
+				annotations.AnnLinodeCheckPath:                "/healthz",
+				annotations.AnnLinodeHealthCheckInterval:      "10",
+				annotations.AnnLinodeHealthCheckPassive:       "false",
+				annotations.AnnLinodeThrottle:                 "5",
+				annotations.AnnLinodeLoadBalancerTags:         "a,b",
+				annotations.AnnLinodeNodeBalancerType:         "premium",
+				annotations.AnnLinodeCloudFirewallACL:         `{"allowList":{"ipv4":["1.2.3.4/32"]}}`,
+				annotations.NodeBalancerBackendVPCName:        "vpc",
+				annotations.NodeBalancerBackendSubnetName:     "subnet",
+			},
+		},
+		{
+			name: "firewall id and acl",
+			spec: v1alpha1.LinodeLoadBalancerConfigSpec{
+				Firewall: &v1alpha1.Firewall{
+					ID:  ptr.To(1),
+					ACL: &v1alpha1.FirewallACL{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid port",
+			spec: v1alpha1.LinodeLoadBalancerConfigSpec{
+				Ports: []v1alpha1.PortConfig{{Port: 0}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := loadBalancerConfigAnnotations(&tc.spec)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_resolveLoadBalancerConfig(t *testing.T) {
+	newLBConfig := func() *v1alpha1.LinodeLoadBalancerConfig {
+		return &v1alpha1.LinodeLoadBalancerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+			Spec: v1alpha1.LinodeLoadBalancerConfigSpec{
+				DefaultProtocol: "http",
+				Throttle:        ptr.To(5),
+			},
+		}
+	}
+
+	testcases := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "no config referenced",
+			annotations: map[string]string{annotations.AnnLinodeThrottle: "10"},
+			expected:    map[string]string{annotations.AnnLinodeThrottle: "10"},
+		},
+		{
+			name:        "config fills missing annotations",
+			annotations: map[string]string{annotations.AnnLinodeLoadBalancerConfig: "shared"},
+			expected: map[string]string{
+				annotations.AnnLinodeLoadBalancerConfig: "shared",
+				annotations.AnnLinodeDefaultProtocol:    "http",
+				annotations.AnnLinodeThrottle:           "5",
+			},
+		},
+		{
+			name: "service annotations override config",
+			annotations: map[string]string{
+				annotations.AnnLinodeLoadBalancerConfig: "shared",
+				annotations.AnnLinodeThrottle:           "10",
+			},
+			expected: map[string]string{
+				annotations.AnnLinodeLoadBalancerConfig: "shared",
+				annotations.AnnLinodeDefaultProtocol:    "http",
+				annotations.AnnLinodeThrottle:           "10",
+			},
+		},
+		{
+			name:        "missing config",
+			annotations: map[string]string{annotations.AnnLinodeLoadBalancerConfig: "missing"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			lb := &loadbalancers{dynamicClient: newFakeLoadBalancerConfigClient(t, newLBConfig()), options: &options.Config{}}
+			svc := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: tc.annotations},
+			}
+			original := svc.DeepCopy()
+
+			resolved, err := lb.resolveLoadBalancerConfig(t.Context(), svc)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, resolved.Annotations)
+			assert.Equal(t, original, svc, "service should not be modified")
+		})
+	}
+}
+
+func Test_recordLoadBalancerConfigStatus(t *testing.T) {
+	lbConfig := &v1alpha1.LinodeLoadBalancerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default", Generation: 3},
+	}
+	dynamicClient := newFakeLoadBalancerConfigClient(t, lbConfig)
+	lb := &loadbalancers{dynamicClient: dynamicClient, options: &options.Config{}}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "svc",
+			Namespace:   "default",
+			Annotations: map[string]string{annotations.AnnLinodeLoadBalancerConfig: "shared"},
+		},
+	}
+
+	statusUpdates := func() int {
+		count := 0
+		for _, action := range dynamicClient.Actions() {
+			if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The generation of the config that was applied is recorded, not the current one
+	lb.recordLoadBalancerConfigStatus(t.Context(), svc, 2, 123, nil)
+	got, err := lb.getLoadBalancerConfig(t.Context(), "default", "shared")
+	require.NoError(t, err)
+	require.Len(t, got.Status.Services, 1)
+	assert.Equal(t, "svc", got.Status.Services[0].Name)
+	assert.Equal(t, 123, got.Status.Services[0].NodeBalancerID)
+	assert.Equal(t, int64(2), got.Status.Services[0].ObservedGeneration)
+	assert.Empty(t, got.Status.Services[0].Error)
+	assert.Equal(t, 1, statusUpdates())
+
+	// A resync with the same outcome doesn't write the status again
+	lb.recordLoadBalancerConfigStatus(t.Context(), svc, 2, 123, nil)
+	assert.Equal(t, 1, statusUpdates())
+
+	lb.recordLoadBalancerConfigStatus(t.Context(), svc, 3, 0, errors.New("boom"))
+	got, err = lb.getLoadBalancerConfig(t.Context(), "default", "shared")
+	require.NoError(t, err)
+	require.Len(t, got.Status.Services, 1)
+	assert.Equal(t, 123, got.Status.Services[0].NodeBalancerID, "last known NodeBalancer ID should be kept on error")
+	assert.Equal(t, int64(2), got.Status.Services[0].ObservedGeneration, "a failed reconcile should not be recorded as observed")
+	assert.Equal(t, "boom", got.Status.Services[0].Error)
+	assert.Equal(t, 2, statusUpdates())
+
+	lb.removeLoadBalancerConfigStatus(t.Context(), svc)
+	got, err = lb.getLoadBalancerConfig(t.Context(), "default", "shared")
+	require.NoError(t, err)
+	assert.Empty(t, got.Status.Services)
+}
@@ -1,6 +1,7 @@
 package linode
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,18 +9,26 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
-	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
@@ -101,13 +110,73 @@ func (e lbNotFoundError) Error() string {
 }
 
 type loadbalancers struct {
-	client     client.Client
-	zone       string
-	kubeClient kubernetes.Interface
+	client        client.Client
+	zone          string
+	options       *options.Config
+	vpcs          *services.VPCCache
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	// endpointSliceInformer is the EndpointSlice informer of the ServiceResyncController, which
+	// endpoint aware backends are read from once it is synced
+	endpointSliceInformer discoveryinformers.EndpointSliceInformer
+	// loadBalancerConfigInformer is the LinodeLoadBalancerConfig informer of the
+	// ServiceResyncController, which configs are read from once it is synced. It is nil when the
+	// CRD isn't installed.
+	loadBalancerConfigInformer informers.GenericInformer
+
+	// replacements tracks the background deletion of NodeBalancers replaced by new ones
+	replacements sync.WaitGroup
+	// replacementCleanups holds the IDs of the replaced NodeBalancers being waited for
+	replacementCleanups sync.Map
+	// serviceLocks serializes the reconciliation of each Service, which both the cloud-provider
+	// service controller and the ServiceResyncController reconcile
+	serviceLocks serviceLocks
+}
+
+// serviceLocks hands out a lock per Service, so that work on the same Service is serialized
+// while different Services are reconciled concurrently. The zero value is ready to use.
+type serviceLocks struct {
+	mu    sync.Mutex
+	locks map[string]*serviceLock
+}
+
+type serviceLock struct {
+	sync.Mutex
+	// holders counts the callers holding or waiting for the lock, which is dropped at zero
+	holders int
+}
+
+// lock blocks until the lock of service is held and returns the function releasing it.
+func (s *serviceLocks) lock(service *v1.Service) (unlock func()) {
+	key := getServiceNn(service)
+	s.mu.Lock()
+	if s.locks == nil {
+		s.locks = make(map[string]*serviceLock)
+	}
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = &serviceLock{}
+		s.locks[key] = lock
+	}
+	lock.holders++
+	s.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		s.mu.Lock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(s.locks, key)
+		}
+		s.mu.Unlock()
+	}
 }
 
 type portConfigAnnotation struct {
 	TLSSecretName string `json:"tls-secret-name"`
+	TLSCertField  string `json:"tls-cert-field,omitempty"`
+	TLSKeyField   string `json:"tls-key-field,omitempty"`
 	Protocol      string `json:"protocol"`
 	ProxyProtocol string `json:"proxy-protocol"`
 	Algorithm     string `json:"algorithm"`
@@ -117,6 +186,10 @@ type portConfigAnnotation struct {
 
 type portConfig struct {
 	TLSSecretName string
+	// TLSCertField and TLSKeyField are the keys of the certificate chain and private key in
+	// the TLS secret, tls.crt and tls.key unless set otherwise
+	TLSCertField  string
+	TLSKeyField   string
 	Protocol      linodego.ConfigProtocol
 	ProxyProtocol linodego.ConfigProxyProtocol
 	Port          int
@@ -126,8 +199,8 @@ type portConfig struct {
 }
 
 // newLoadbalancers returns a cloudprovider.LoadBalancer whose concrete type is a *loadbalancer.
-func newLoadbalancers(client client.Client, zone string) cloudprovider.LoadBalancer {
-	return &loadbalancers{client: client, zone: zone}
+func newLoadbalancers(client client.Client, zone string, opts *options.Config, vpcs *services.VPCCache) cloudprovider.LoadBalancer {
+	return &loadbalancers{client: client, zone: zone, options: opts, vpcs: vpcs}
 }
 
 func (l *loadbalancers) getNodeBalancerForService(ctx context.Context, service *v1.Service) (*linodego.NodeBalancer, error) {
@@ -139,7 +212,22 @@ func (l *loadbalancers) getNodeBalancerForService(ctx context.Context, service *
 		sentry.SetTag(ctx, "load_balancer_id", rawID)
 		return l.getNodeBalancerByID(ctx, service, id)
 	}
-	return l.getNodeBalancerByStatus(ctx, service)
+
+	nb, err := l.getNodeBalancerByStatus(ctx, service)
+	var targetError lbNotFoundError
+	if errors.As(err, &targetError) {
+		return l.getNodeBalancerByOwnershipTags(ctx, service)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the status still references a NodeBalancer that was replaced by a new one
+	if id, ok := replacementNodeBalancerID(nb); ok {
+		klog.V(2).Infof("NodeBalancer (%d) for service (%s) was replaced by NodeBalancer (%d)", nb.ID, getServiceNn(service), id)
+		return l.getNodeBalancerByID(ctx, service, id)
+	}
+	return nb, nil
 }
 
 func (l *loadbalancers) getLatestServiceLoadBalancerStatus(ctx context.Context, service *v1.Service) (v1.LoadBalancerStatus, error) {
@@ -223,9 +311,8 @@ func (l *loadbalancers) cleanupOldNodeBalancer(ctx context.Context, service *v1.
 // GetLoadBalancerName returns the name of the load balancer.
 //
 // GetLoadBalancer will not modify service.
-func (l *loadbalancers) GetLoadBalancerName(_ context.Context, _ string, _ *v1.Service) string {
-	unixNano := strconv.FormatInt(time.Now().UnixNano(), 16)
-	return fmt.Sprintf("%s-%s", options.Options.NodeBalancerPrefix, unixNano[len(unixNano)-12:])
+func (l *loadbalancers) GetLoadBalancerName(_ context.Context, clusterName string, service *v1.Service) string {
+	return nodeBalancerLabel(l.options, clusterName, service)
 }
 
 // GetLoadBalancer returns the *v1.LoadBalancerStatus of service.
@@ -247,7 +334,7 @@ func (l *loadbalancers) GetLoadBalancer(ctx context.Context, clusterName string,
 		}
 	}
 
-	return makeLoadBalancerStatus(service, nb), true, nil
+	return makeLoadBalancerStatus(l.options, service, nb), true, nil
 }
 
 // EnsureLoadBalancer ensures that the cluster is running a load balancer for
@@ -255,16 +342,62 @@ func (l *loadbalancers) GetLoadBalancer(ctx context.Context, clusterName string,
 //
 // EnsureLoadBalancer will not modify service or nodes.
 func (l *loadbalancers) EnsureLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (lbStatus *v1.LoadBalancerStatus, err error) {
+	defer l.serviceLocks.lock(service)()
+
 	ctx = sentry.SetHubOnContext(ctx)
 	sentry.SetTag(ctx, "cluster_name", clusterName)
 	sentry.SetTag(ctx, "service", service.Name)
 	serviceNn := getServiceNn(service)
 
 	var nb *linodego.NodeBalancer
+	var configGeneration int64
+	defer func() {
+		nbID := 0
+		if nb != nil {
+			nbID = nb.ID
+		}
+		l.recordLoadBalancerConfigStatus(ctx, service, configGeneration, nbID, err)
+	}()
+
+	resolved, configGeneration, err := l.resolveLoadBalancerConfigGeneration(ctx, service)
+	if err != nil {
+		sentry.CaptureError(ctx, err)
+		return nil, err
+	}
+	service = resolved
+
+	if err = ensureValidService(l.options, service); err != nil {
+		sentry.CaptureError(ctx, err)
+		return nil, err
+	}
+
+	nodes, err = l.selectBackendNodes(ctx, service, nodes)
+	if err != nil {
+		sentry.CaptureError(ctx, err)
+		return nil, err
+	}
 
 	nb, err = l.getNodeBalancerForService(ctx, service)
 	if err == nil {
-		if err = l.updateNodeBalancer(ctx, clusterName, service, nodes, nb); err != nil {
+		_, typeMismatch := nodeBalancerTypeMismatch(l.options, service, nb)
+		_, ipv4Changed := reservedIPv4HandoverTarget(service, nb)
+		migrate := typeMismatch && nodeBalancerTypeMigrationBlocker(service, nb) == ""
+		handOver := !migrate && ipv4Changed && reservedIPv4HandoverBlocker(service) == ""
+		if !handOver {
+			if err = l.deleteAbandonedReplacements(ctx, clusterName, service, nb); err != nil {
+				sentry.CaptureError(ctx, err)
+				return nil, err
+			}
+		}
+		switch {
+		case migrate:
+			nb, err = l.migrateNodeBalancerType(ctx, clusterName, service, nodes, nb)
+		case handOver:
+			nb, err = l.handOverReservedIPv4(ctx, clusterName, service, nodes, nb)
+		default:
+			err = l.updateNodeBalancer(ctx, clusterName, service, nodes, nb)
+		}
+		if err != nil {
 			sentry.CaptureError(ctx, err)
 			return nil, err
 		}
@@ -278,6 +411,11 @@ func (l *loadbalancers) EnsureLoadBalancer(ctx context.Context, clusterName stri
 				return nil, err
 			}
 
+			if service, err = l.reserveIPv4(ctx, service); err != nil {
+				sentry.CaptureError(ctx, err)
+				return nil, err
+			}
+
 			if nb, err = l.buildLoadBalancerRequest(ctx, clusterName, service, nodes); err != nil {
 				sentry.CaptureError(ctx, err)
 				return nil, err
@@ -290,7 +428,8 @@ func (l *loadbalancers) EnsureLoadBalancer(ctx context.Context, clusterName stri
 	}
 
 	klog.Infof("NodeBalancer (%d) has been ensured for service (%s)", nb.ID, serviceNn)
-	lbStatus = makeLoadBalancerStatus(service, nb)
+	lbStatus = makeLoadBalancerStatus(l.options, service, nb)
+	l.resumeNodeBalancerReplacements(ctx, service, nb)
 
 	if !l.shouldPreserveNodeBalancer(service) {
 		if err := l.cleanupOldNodeBalancer(ctx, service); err != nil {
@@ -303,9 +442,21 @@ func (l *loadbalancers) EnsureLoadBalancer(ctx context.Context, clusterName stri
 }
 
 func (l *loadbalancers) createIPChangeWarningEvent(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer, newIP string) {
+	l.createServiceEvent(ctx, service, eventIPChangeIgnoredWarning, "Warning", "NodeBalancerIPChangeIgnored",
+		fmt.Sprintf("IPv4 annotation changed to %s, but NodeBalancer (%d) IP cannot be updated after creation. It will remain %s", newIP, nb.ID, *nb.IPv4))
+}
+
+// createServiceEvent records an event about service named after namePrefix. Failing to create
+// it is only logged, so that events never fail a reconcile.
+func (l *loadbalancers) createServiceEvent(ctx context.Context, service *v1.Service, namePrefix, eventType, reason, message string) {
+	if err := l.retrieveKubeClient(); err != nil {
+		klog.Errorf("failed to create %s event for service %s: %s", reason, getServiceNn(service), err)
+		return
+	}
+
 	_, err := l.kubeClient.CoreV1().Events(service.Namespace).Create(ctx, &v1.Event{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%d", eventIPChangeIgnoredWarning, time.Now().Unix()),
+			Name:      fmt.Sprintf("%s-%d", namePrefix, time.Now().UnixNano()),
 			Namespace: service.Namespace,
 		},
 		InvolvedObject: v1.ObjectReference{
@@ -314,15 +465,15 @@ func (l *loadbalancers) createIPChangeWarningEvent(ctx context.Context, service
 			Name:      service.Name,
 			UID:       service.UID,
 		},
-		Type:    "Warning",
-		Reason:  "NodeBalancerIPChangeIgnored",
-		Message: fmt.Sprintf("IPv4 annotation changed to %s, but NodeBalancer (%d) IP cannot be updated after creation. It will remain %s", newIP, nb.ID, *nb.IPv4),
+		Type:    eventType,
+		Reason:  reason,
+		Message: message,
 		Source: v1.EventSource{
 			Component: "linode-cloud-controller-manager",
 		},
 	}, metav1.CreateOptions{})
 	if err != nil {
-		klog.Errorf("failed to create NodeBalancerIPChangeIgnored event for service %s: %s", getServiceNn(service), err)
+		klog.Errorf("failed to create %s event for service %s: %s", reason, getServiceNn(service), err)
 	}
 }
 
@@ -337,8 +488,12 @@ func (l *loadbalancers) updateNodeBalancer(
 		return fmt.Errorf("%w: service %s", errNoNodesAvailable, getServiceNn(service))
 	}
 
-	// Check for IPv4 annotation change
-	if ipv4, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerReservedIPv4]; ok && ipv4 != *nb.IPv4 {
+	if err = checkNodeBalancerOwnership(clusterName, service, nb); err != nil {
+		return err
+	}
+
+	// Check for IPv4 annotation change, which is only handed over by EnsureLoadBalancer
+	if ipv4, changed := reservedIPv4HandoverTarget(service, nb); changed && reservedIPv4HandoverBlocker(service) != "" {
 		// Log the error in the CCM's logfile
 		klog.Warningf("IPv4 annotation has changed for service (%s) from %s to %s, but NodeBalancer (%d) IP cannot be updated after creation",
 			getServiceNn(service), *nb.IPv4, ipv4, nb.ID)
@@ -347,6 +502,15 @@ func (l *loadbalancers) updateNodeBalancer(
 		l.createIPChangeWarningEvent(ctx, service, nb, ipv4)
 	}
 
+	// A mismatch is only migrated by EnsureLoadBalancer, which can switch the service status
+	if nbType, mismatch := nodeBalancerTypeMismatch(l.options, service, nb); mismatch {
+		if blocker := nodeBalancerTypeMigrationBlocker(service, nb); blocker != "" {
+			klog.Warningf("NodeBalancer (%d) for service (%s) is of type %s instead of %s: %s", nb.ID, getServiceNn(service), nb.Type, nbType, blocker)
+			l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Warning", "NodeBalancerTypeMismatch",
+				fmt.Sprintf("NodeBalancer (%d) is of type %s instead of %s, %s", nb.ID, nb.Type, nbType, blocker))
+		}
+	}
+
 	connThrottle := getConnectionThrottle(service)
 	if connThrottle != nb.ClientConnThrottle {
 		update := nb.GetUpdateOptions()
@@ -358,6 +522,20 @@ func (l *loadbalancers) updateNodeBalancer(
 		}
 	}
 
+	if l.options.NodeBalancerLabelMode == NodeBalancerLabelModeService {
+		label := l.GetLoadBalancerName(ctx, clusterName, service)
+		if nb.Label == nil || *nb.Label != label {
+			klog.Infof("renaming NodeBalancer (%d) for service (%s) to %s", nb.ID, getServiceNn(service), label)
+			update := nb.GetUpdateOptions()
+			update.Label = &label
+			nb, err = l.client.UpdateNodeBalancer(ctx, nb.ID, update)
+			if err != nil {
+				sentry.CaptureError(ctx, err)
+				return err
+			}
+		}
+	}
+
 	tags := l.GetLoadBalancerTags(ctx, clusterName, service)
 	if !reflect.DeepEqual(nb.Tags, tags) {
 		update := nb.GetUpdateOptions()
@@ -369,8 +547,26 @@ func (l *loadbalancers) updateNodeBalancer(
 		}
 	}
 
+	return l.updateNodeBalancerConfigs(ctx, clusterName, service, nodes, nb)
+}
+
+// updateNodeBalancerConfigs updates the firewall, configs and backends of nb to match service,
+// leaving its label and tags alone.
+func (l *loadbalancers) updateNodeBalancerConfigs(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nodes []*v1.Node,
+	nb *linodego.NodeBalancer,
+) (err error) {
 	fwClient := services.LinodeClient{Client: l.client}
-	err = fwClient.UpdateNodeBalancerFirewall(ctx, l.GetLoadBalancerName(ctx, clusterName, service), tags, service, nb)
+	// A firewall created for the NodeBalancer shares its label
+	firewallLabel := l.GetLoadBalancerName(ctx, clusterName, service)
+	if nb.Label != nil {
+		firewallLabel = *nb.Label
+	}
+	tags := l.GetLoadBalancerTags(ctx, clusterName, service)
+	err = fwClient.UpdateNodeBalancerFirewall(ctx, firewallLabel, tags, service, nb)
 	if err != nil {
 		return err
 	}
@@ -387,6 +583,15 @@ func (l *loadbalancers) updateNodeBalancer(
 		sentry.CaptureError(ctx, err)
 		return err
 	}
+	servicePorts := sets.New[int]()
+	for _, port := range service.Spec.Ports {
+		servicePorts.Insert(int(port.Port))
+	}
+	for _, nbc := range nbCfgs {
+		if !servicePorts.Has(nbc.Port) {
+			forgetCertificateExpiry(service, nbc.Port)
+		}
+	}
 
 	// Add or overwrite configs for each of the Service's ports
 	for _, port := range service.Spec.Ports {
@@ -408,6 +613,7 @@ func (l *loadbalancers) updateNodeBalancer(
 		}
 		oldNBNodeIDs := make(map[string]int)
 		var currentNBNodes []linodego.NodeBalancerNode
+		currentNBNodesKnown := false
 		if currentNBCfg != nil {
 			// Obtain list of current NB nodes and convert it to map of node IDs
 			currentNBNodes, err = l.client.ListNodeBalancerNodes(ctx, nb.ID, currentNBCfg.ID, nil)
@@ -415,6 +621,8 @@ func (l *loadbalancers) updateNodeBalancer(
 				// This error can be ignored, because if we fail to get nodes we can anyway rebuild the config from scratch,
 				// it would just cause the NB to reload config even if the node list did not change, so we prefer to send IDs when it is possible.
 				klog.Warningf("Unable to list existing nodebalancer nodes for NB %d config %d, error: %s", nb.ID, newNBCfg.ID, err)
+			} else {
+				currentNBNodesKnown = true
 			}
 			for _, node := range currentNBNodes {
 				oldNBNodeIDs[node.Address] = node.ID
@@ -424,7 +632,7 @@ func (l *loadbalancers) updateNodeBalancer(
 			klog.Infof("No preexisting nodebalancer for port %v found.", port.Port)
 		}
 
-		useIPv6Backends := resolveIPv6NodeBalancerBackendState(service)
+		useIPv6Backends := resolveIPv6NodeBalancerBackendState(l.options, service)
 		// Add all of the Nodes to the config
 		subnetID, err := l.getBackendSubnetID(ctx, service, useIPv6Backends)
 		if err != nil {
@@ -437,6 +645,21 @@ func (l *loadbalancers) updateNodeBalancer(
 			return fmt.Errorf("[port %d] error building NodeBalancer backend node configs: %w", int(port.Port), err)
 		}
 
+		// Leave the config alone if it already matches, rebuilding makes the NodeBalancer reload it
+		if currentNBCfg != nil && currentNBNodesKnown &&
+			!nodeBalancerConfigNeedsUpdate(currentNBCfg, &newNBCfg) &&
+			!nodeBalancerNodesNeedUpdate(currentNBNodes, newNBNodes) {
+			klog.V(3).Infof("NodeBalancer %d config %d for port %d is up to date, skipping rebuild", nb.ID, currentNBCfg.ID, port.Port)
+			nodeBalancerConfigUpdatesCounterVec.WithLabelValues(configUpdateResultSkipped).Inc()
+			if newNBCfg.Protocol == linodego.ProtocolHTTPS {
+				recordCertificateExpiry(service, &newNBCfg)
+			}
+			continue
+		}
+
+		rotated := currentNBCfg != nil && newNBCfg.Protocol == linodego.ProtocolHTTPS &&
+			certificateRotated(newNBCfg.SSLCert, currentNBCfg.SSLFingerprint)
+
 		// If there's no existing config, create it
 		var rebuildOpts linodego.NodeBalancerConfigRebuildOptions
 		if currentNBCfg == nil {
@@ -463,6 +686,15 @@ func (l *loadbalancers) updateNodeBalancer(
 			sentry.CaptureError(ctx, err)
 			return fmt.Errorf("[port %d] error rebuilding NodeBalancer config: %w", int(port.Port), err)
 		}
+		nodeBalancerConfigUpdatesCounterVec.WithLabelValues(configUpdateResultApplied).Inc()
+		if newNBCfg.Protocol == linodego.ProtocolHTTPS {
+			recordCertificateExpiry(service, &newNBCfg)
+			if rotated {
+				l.createCertificateRotatedEvent(ctx, service, nb, &newNBCfg)
+			}
+		} else {
+			forgetCertificateExpiry(service, newNBCfg.Port)
+		}
 	}
 
 	return nil
@@ -470,10 +702,40 @@ func (l *loadbalancers) updateNodeBalancer(
 
 // UpdateLoadBalancer updates the NodeBalancer to have configs that match the Service's ports
 func (l *loadbalancers) UpdateLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (err error) {
+	defer l.serviceLocks.lock(service)()
+
 	ctx = sentry.SetHubOnContext(ctx)
 	sentry.SetTag(ctx, "cluster_name", clusterName)
 	sentry.SetTag(ctx, "service", service.Name)
 
+	var nb *linodego.NodeBalancer
+	var configGeneration int64
+	defer func() {
+		nbID := 0
+		if nb != nil {
+			nbID = nb.ID
+		}
+		l.recordLoadBalancerConfigStatus(ctx, service, configGeneration, nbID, err)
+	}()
+
+	resolved, configGeneration, err := l.resolveLoadBalancerConfigGeneration(ctx, service)
+	if err != nil {
+		sentry.CaptureError(ctx, err)
+		return err
+	}
+	service = resolved
+
+	if err = ensureValidService(l.options, service); err != nil {
+		sentry.CaptureError(ctx, err)
+		return err
+	}
+
+	nodes, err = l.selectBackendNodes(ctx, service, nodes)
+	if err != nil {
+		sentry.CaptureError(ctx, err)
+		return err
+	}
+
 	// UpdateLoadBalancer is invoked with a nil LoadBalancerStatus; we must fetch the latest
 	// status for NodeBalancer discovery.
 	serviceWithStatus := service.DeepCopy()
@@ -483,7 +745,7 @@ func (l *loadbalancers) UpdateLoadBalancer(ctx context.Context, clusterName stri
 		serviceWithStatus.Status.LoadBalancer = service.Status.LoadBalancer
 	}
 
-	nb, err := l.getNodeBalancerForService(ctx, serviceWithStatus)
+	nb, err = l.getNodeBalancerForService(ctx, serviceWithStatus)
 	if err != nil {
 		sentry.CaptureError(ctx, err)
 		return err
@@ -496,7 +758,11 @@ func (l *loadbalancers) UpdateLoadBalancer(ctx context.Context, clusterName stri
 		}
 	}
 
-	return l.updateNodeBalancer(ctx, clusterName, serviceWithStatus, nodes, nb)
+	if err = l.updateNodeBalancer(ctx, clusterName, serviceWithStatus, nodes, nb); err != nil {
+		return err
+	}
+	l.resumeNodeBalancerReplacements(ctx, service, nb)
+	return nil
 }
 
 // Delete any NodeBalancer configs for ports that no longer exist on the Service
@@ -525,6 +791,28 @@ func (l *loadbalancers) shouldPreserveNodeBalancer(service *v1.Service) bool {
 	return shouldPreserve != nil && *shouldPreserve
 }
 
+// releaseNodeBalancer removes the ownership tags of a NodeBalancer that is kept after its service
+// was deleted, so that another service can adopt it, and tags it so that the orphaned NodeBalancer
+// garbage collector leaves it alone.
+func (l *loadbalancers) releaseNodeBalancer(ctx context.Context, nb *linodego.NodeBalancer) error {
+	tags := slices.DeleteFunc(slices.Clone(nb.Tags), isOwnershipTag)
+	if !slices.Contains(tags, preserveNodeBalancerTag) {
+		tags = append(tags, preserveNodeBalancerTag)
+	}
+	if slices.Equal(tags, nb.Tags) {
+		return nil
+	}
+
+	update := nb.GetUpdateOptions()
+	update.Tags = tags
+	if _, err := l.client.UpdateNodeBalancer(ctx, nb.ID, update); err != nil {
+		klog.Errorf("failed to release preserved NodeBalancer (%d): %s", nb.ID, err)
+		sentry.CaptureError(ctx, err)
+		return err
+	}
+	return nil
+}
+
 // shouldRetainReservedIP determines whether a reserved IP should be retained based on the
 // service's retain reserved IP annotation. If the annotation is not present, the default behavior is to retain the reserved IP.
 func (l *loadbalancers) shouldRetainReservedIP(service *v1.Service) bool {
@@ -532,18 +820,89 @@ func (l *loadbalancers) shouldRetainReservedIP(service *v1.Service) bool {
 	return shouldRetain == nil || *shouldRetain
 }
 
+// shouldReserveIPv4 determines whether an IPv4 address should be reserved for the NodeBalancer of
+// service based on the service's reserve IPv4 annotation.
+func (l *loadbalancers) shouldReserveIPv4(service *v1.Service) bool {
+	if _, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerReservedIPv4]; ok {
+		return false
+	}
+	shouldReserve := getServiceBoolAnnotation(service, annotations.AnnLinodeLoadBalancerReserveIPv4)
+	return shouldReserve != nil && *shouldReserve
+}
+
+// reserveIPv4 reserves an IPv4 address in the region of the NodeBalancers when service asks for it,
+// and records it in the reserved IPv4 annotation of service so that the NodeBalancer is created
+// with it. A copy of service with the annotation is returned.
+func (l *loadbalancers) reserveIPv4(ctx context.Context, service *v1.Service) (*v1.Service, error) {
+	if !l.shouldReserveIPv4(service) {
+		return service, nil
+	}
+
+	serviceNn := getServiceNn(service)
+	ip, err := l.client.ReserveIPAddress(ctx, linodego.ReserveIPOptions{Region: l.zone})
+	if err != nil {
+		klog.Errorf("failed to reserve IP for service (%s): %s", serviceNn, err)
+		return nil, err
+	}
+	// the service is not changed while planning
+	if l.options.Plan || ip.Address == "" {
+		return service, nil
+	}
+	klog.Infof("reserved IP (%s) for service (%s)", ip.Address, serviceNn)
+
+	if err = l.retrieveKubeClient(); err == nil {
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			// Get a fresh copy of the service so the resource version is up-to-date
+			current, err := l.kubeClient.CoreV1().Services(service.Namespace).Get(ctx, service.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if current.Annotations == nil {
+				current.Annotations = map[string]string{}
+			}
+			current.Annotations[annotations.AnnLinodeLoadBalancerReservedIPv4] = ip.Address
+			_, err = l.kubeClient.CoreV1().Services(service.Namespace).Update(ctx, current, metav1.UpdateOptions{})
+			return err
+		})
+	}
+	if err != nil {
+		// an address that is not recorded on the service would be leaked
+		klog.Errorf("failed to record reserved IP (%s) on service (%s): %s", ip.Address, serviceNn, err)
+		if deleteErr := l.client.DeleteReservedIPAddress(ctx, ip.Address); deleteErr != nil {
+			klog.Errorf("failed to delete reserved IP (%s) for service (%s): %s", ip.Address, serviceNn, deleteErr)
+		}
+		return nil, err
+	}
+
+	service = service.DeepCopy()
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[annotations.AnnLinodeLoadBalancerReservedIPv4] = ip.Address
+	return service, nil
+}
+
 // EnsureLoadBalancerDeleted deletes the specified loadbalancer if it exists.
 // nil is returned if the load balancer for service does not exist or is
 // successfully deleted.
 //
 // EnsureLoadBalancerDeleted will not modify service.
 func (l *loadbalancers) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string, service *v1.Service) error {
+	defer l.serviceLocks.lock(service)()
+
 	ctx = sentry.SetHubOnContext(ctx)
 	sentry.SetTag(ctx, "cluster_name", clusterName)
 	sentry.SetTag(ctx, "service", service.Name)
 
 	serviceNn := getServiceNn(service)
 
+	resolved, err := l.resolveLoadBalancerConfig(ctx, service)
+	if err != nil {
+		klog.Warningf("deleting NodeBalancer for service (%s) without its LinodeLoadBalancerConfig: %s", serviceNn, err)
+	} else {
+		service = resolved
+	}
+
 	if len(service.Status.LoadBalancer.Ingress) == 0 {
 		klog.Infof("short-circuiting deletion of NodeBalancer for service(%s) as LoadBalancer ingress is not present", serviceNn)
 		return nil
@@ -562,6 +921,11 @@ func (l *loadbalancers) EnsureLoadBalancerDeleted(ctx context.Context, clusterNa
 		}
 	}
 
+	if err = checkNodeBalancerOwnership(clusterName, service, nb); err != nil {
+		klog.Infof("short-circuiting deletion of NodeBalancer for service (%s): %s", serviceNn, err)
+		return nil
+	}
+
 	if l.shouldPreserveNodeBalancer(service) {
 		klog.Infof(
 			"short-circuiting deletion of NodeBalancer (%d) for service (%s) as annotated with %s",
@@ -569,7 +933,7 @@ func (l *loadbalancers) EnsureLoadBalancerDeleted(ctx context.Context, clusterNa
 			serviceNn,
 			annotations.AnnLinodeLoadBalancerPreserve,
 		)
-		return nil
+		return l.releaseNodeBalancer(ctx, nb)
 	}
 
 	fwClient := services.LinodeClient{Client: l.client}
@@ -593,6 +957,21 @@ func (l *loadbalancers) EnsureLoadBalancerDeleted(ctx context.Context, clusterNa
 	}
 
 	klog.Infof("successfully deleted NodeBalancer (%d) for service (%s)", nb.ID, serviceNn)
+
+	if err = l.deleteReplacedNodeBalancers(ctx, service, nb); err != nil {
+		klog.Errorf("failed to delete NodeBalancers replaced by NodeBalancer (%d) for service (%s): %s", nb.ID, serviceNn, err)
+		sentry.CaptureError(ctx, err)
+		return err
+	}
+	if err = l.deleteAbandonedReplacements(ctx, clusterName, service, nb); err != nil {
+		klog.Errorf("failed to delete NodeBalancers prepared to replace NodeBalancer (%d) for service (%s): %s", nb.ID, serviceNn, err)
+		sentry.CaptureError(ctx, err)
+		return err
+	}
+	l.removeLoadBalancerConfigStatus(ctx, service)
+	nodeBalancerCertificateExpiryGaugeVec.DeletePartialMatch(prometheus.Labels{"namespace": service.Namespace, "service": service.Name})
+	forgetNodeBalancerBackends(service)
+	forgetNodeBalancerStats(service)
 	return nil
 }
 
@@ -659,11 +1038,18 @@ func (l *loadbalancers) GetLoadBalancerTags(_ context.Context, clusterName strin
 		tags = append(tags, clusterName)
 	}
 
-	tags = append(tags, options.Options.NodeBalancerTags...)
+	tags = append(tags, nodeBalancerOwnershipTags(clusterName, service)...)
+	tags = append(tags, l.options.Current().NodeBalancerTags...)
 
 	tagStr, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerTags]
 	if ok {
-		return append(tags, strings.Split(tagStr, ",")...)
+		for _, tag := range strings.Split(tagStr, ",") {
+			if isReservedTag(tag) {
+				klog.Warningf("ignoring tag %q reserved for the CCM in annotation %q of service (%s)", tag, annotations.AnnLinodeLoadBalancerTags, getServiceNn(service))
+				continue
+			}
+			tags = append(tags, tag)
+		}
 	}
 
 	return tags
@@ -671,24 +1057,36 @@ func (l *loadbalancers) GetLoadBalancerTags(_ context.Context, clusterName strin
 
 // GetLinodeNBType returns the NodeBalancer type for the service.
 func (l *loadbalancers) GetLinodeNBType(service *v1.Service) linodego.NodeBalancerPlanType {
+	nbType, warning := parseNodeBalancerType(l.options, service)
+	if warning != "" {
+		klog.Warningf("%s for service %s/%s", warning, service.Namespace, service.Name)
+	}
+	return nbType
+}
+
+// parseNodeBalancerType returns the NodeBalancer type requested by service, along with a
+// warning when the annotation holds an unknown type and the default is used instead.
+func parseNodeBalancerType(opts *options.Config, service *v1.Service) (linodego.NodeBalancerPlanType, string) {
+	defaultNBType := opts.Current().DefaultNBType
 	typeStr, ok := service.GetAnnotations()[annotations.AnnLinodeNodeBalancerType]
 	if ok {
 		// For Safety - avoid typos and inconsistent casing
 		typeStr = strings.ToLower(typeStr)
 		switch linodego.NodeBalancerPlanType(typeStr) {
 		case linodego.NBTypeCommon: // need to add this because of the golint check
-			return linodego.NBTypeCommon
+			return linodego.NBTypeCommon, ""
 		case linodego.NBTypePremium:
-			return linodego.NBTypePremium
+			return linodego.NBTypePremium, ""
 		case linodego.NBTypePremium40GB:
-			return linodego.NBTypePremium40GB
+			return linodego.NBTypePremium40GB, ""
 		default:
-			klog.Warningf("Invalid NodeBalancer type '%s' specified in annotation for service %s/%s. Valid types are: %s, %s, %s. Defaulting to %s.",
-				typeStr, service.Namespace, service.Name, linodego.NBTypeCommon, linodego.NBTypePremium, linodego.NBTypePremium40GB, options.Options.DefaultNBType)
+			return linodego.NodeBalancerPlanType(defaultNBType), fmt.Sprintf(
+				"Invalid NodeBalancer type '%s' specified in annotation. Valid types are: %s, %s, %s. Defaulting to %s",
+				typeStr, linodego.NBTypeCommon, linodego.NBTypePremium, linodego.NBTypePremium40GB, defaultNBType)
 		}
 	}
 
-	return linodego.NodeBalancerPlanType(options.Options.DefaultNBType)
+	return linodego.NodeBalancerPlanType(defaultNBType), ""
 }
 
 // getVPCCreateOptions returns the VPC options for the NodeBalancer creation.
@@ -708,7 +1106,7 @@ func (l *loadbalancers) getVPCCreateOptions(ctx context.Context, service *v1.Ser
 	// Precedence 1: If the user has specified a NodeBalancerBackendIPv4Range, use that
 	backendIPv4Range, ok := service.GetAnnotations()[annotations.NodeBalancerBackendIPv4Range]
 	if ok {
-		if err := validateNodeBalancerBackendIPv4Range(backendIPv4Range); err != nil {
+		if err := validateNodeBalancerBackendIPv4Range(l.options, backendIPv4Range); err != nil {
 			return nil, err
 		}
 		// If the user has specified a NodeBalancerBackendIPv4Range, use that
@@ -740,10 +1138,10 @@ func (l *loadbalancers) getVPCCreateOptions(ctx context.Context, service *v1.Ser
 
 	// Precedence 3: If the user has specified a NodeBalancerBackendIPv4SubnetID, use that
 	// and auto-allocate subnets from it for the NodeBalancer
-	if options.Options.NodeBalancerBackendIPv4SubnetID != 0 {
+	if l.options.NodeBalancerBackendIPv4SubnetID != 0 {
 		vpcCreateOpts := []linodego.NodeBalancerBackendVPCOptions{
 			{
-				SubnetID: options.Options.NodeBalancerBackendIPv4SubnetID,
+				SubnetID: l.options.NodeBalancerBackendIPv4SubnetID,
 			},
 		}
 		return vpcCreateOpts, nil
@@ -751,11 +1149,11 @@ func (l *loadbalancers) getVPCCreateOptions(ctx context.Context, service *v1.Ser
 
 	// Precedence 4: If the user has specified a NodeBalancerBackendIPv4Subnet, use that
 	// and auto-allocate subnets from it for the NodeBalancer
-	if options.Options.NodeBalancerBackendIPv4Subnet != "" {
+	if l.options.NodeBalancerBackendIPv4Subnet != "" {
 		vpcCreateOpts := []linodego.NodeBalancerBackendVPCOptions{
 			{
 				SubnetID:            subnetID,
-				IPv4Range:           options.Options.NodeBalancerBackendIPv4Subnet,
+				IPv4Range:           l.options.NodeBalancerBackendIPv4Subnet,
 				IPv4RangeAutoAssign: true,
 			},
 		}
@@ -830,21 +1228,33 @@ func (l *loadbalancers) getSubnetIDByVPCAndSubnetNames(ctx context.Context, vpcN
 		return 0, fmt.Errorf("frontend VPC configuration requires either subnet-id annotation or both vpc-name and subnet-name annotations. No vpc-name or subnet-name annotation found")
 	}
 
-	vpcID, err := services.GetVPCID(ctx, l.client, vpcName)
+	vpcID, err := l.vpcs.GetVPCID(ctx, l.client, vpcName)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get VPC ID for frontend VPC '%s': %w", vpcName, err)
 	}
 
 	// Use the VPC ID and Subnet Name to get the subnet ID
-	return services.GetSubnetID(ctx, l.client, vpcID, subnetName)
+	return l.vpcs.GetSubnetID(ctx, l.client, vpcID, subnetName)
 }
 
-func (l *loadbalancers) createNodeBalancer(ctx context.Context, clusterName string, service *v1.Service, configs []linodego.NodeBalancerConfigCreateOptions) (lb *linodego.NodeBalancer, err error) {
-	connThrottle := getConnectionThrottle(service)
-	useIPv6Backends := resolveIPv6NodeBalancerBackendState(service)
-
+func (l *loadbalancers) createNodeBalancer(ctx context.Context, clusterName string, service *v1.Service, configs []linodego.NodeBalancerConfigCreateOptions) (*linodego.NodeBalancer, error) {
 	label := l.GetLoadBalancerName(ctx, clusterName, service)
 	tags := l.GetLoadBalancerTags(ctx, clusterName, service)
+	return l.createLabeledNodeBalancer(ctx, service, label, tags, configs)
+}
+
+// createLabeledNodeBalancer creates a NodeBalancer with configs for service, labeled and tagged
+// with label and tags instead of those of service.
+func (l *loadbalancers) createLabeledNodeBalancer(
+	ctx context.Context,
+	service *v1.Service,
+	label string,
+	tags []string,
+	configs []linodego.NodeBalancerConfigCreateOptions,
+) (lb *linodego.NodeBalancer, err error) {
+	connThrottle := getConnectionThrottle(service)
+	useIPv6Backends := resolveIPv6NodeBalancerBackendState(l.options, service)
+
 	nbType := l.GetLinodeNBType(service)
 	createOpts := linodego.NodeBalancerCreateOptions{
 		Label:              &label,
@@ -855,7 +1265,7 @@ func (l *loadbalancers) createNodeBalancer(ctx context.Context, clusterName stri
 		Type:               nbType,
 	}
 
-	if !useIPv6Backends && len(options.Options.VPCNames) > 0 && !options.Options.DisableNodeBalancerVPCBackends {
+	if !useIPv6Backends && len(l.options.VPCNames) > 0 && !l.options.DisableNodeBalancerVPCBackends {
 		createOpts.BackendVPCs, err = l.getVPCCreateOptions(ctx, service)
 		if err != nil {
 			return nil, err
@@ -903,14 +1313,31 @@ func (l *loadbalancers) createNodeBalancer(ctx context.Context, clusterName stri
 }
 
 func (l *loadbalancers) buildNodeBalancerConfig(ctx context.Context, service *v1.Service, port v1.ServicePort) (linodego.NodeBalancerConfig, error) {
+	config, portConfigResult, err := newNodeBalancerConfig(service, port)
+	if err != nil {
+		return config, err
+	}
+
+	if portConfigResult.Protocol == linodego.ProtocolHTTPS {
+		if err = l.addTLSCert(ctx, service, &config, portConfigResult); err != nil {
+			return config, err
+		}
+	}
+
+	return config, nil
+}
+
+// newNodeBalancerConfig builds the NodeBalancer config for port from the service's annotations.
+// It does not talk to any API, so TLS material is left for the caller to add.
+func newNodeBalancerConfig(service *v1.Service, port v1.ServicePort) (linodego.NodeBalancerConfig, portConfig, error) {
 	portConfigResult, err := getPortConfig(service, port)
 	if err != nil {
-		return linodego.NodeBalancerConfig{}, err
+		return linodego.NodeBalancerConfig{}, portConfigResult, err
 	}
 
-	health, err := getHealthCheckType(service, port)
+	health, err := getHealthCheckType(service, port, portConfigResult.UDPCheckPort)
 	if err != nil {
-		return linodego.NodeBalancerConfig{}, err
+		return linodego.NodeBalancerConfig{}, portConfigResult, err
 	}
 
 	config := linodego.NodeBalancerConfig{
@@ -933,6 +1360,9 @@ func (l *loadbalancers) buildNodeBalancerConfig(ctx context.Context, service *v1
 		path := service.GetAnnotations()[annotations.AnnLinodeCheckPath]
 		if path == "" {
 			path = "/"
+			if config.Protocol == linodego.ProtocolUDP && config.UDPCheckPort == getLocalHealthCheckNodePort(service) {
+				path = localHealthCheckPath
+			}
 		}
 		config.CheckPath = path
 	}
@@ -940,14 +1370,14 @@ func (l *loadbalancers) buildNodeBalancerConfig(ctx context.Context, service *v1
 	if health == linodego.CheckHTTPBody {
 		body := service.GetAnnotations()[annotations.AnnLinodeCheckBody]
 		if body == "" {
-			return config, fmt.Errorf("for health check type http_body need body regex annotation %v", annotations.AnnLinodeCheckBody)
+			return config, portConfigResult, fmt.Errorf("for health check type http_body need body regex annotation %v", annotations.AnnLinodeCheckBody)
 		}
 		config.CheckBody = body
 	}
 	checkInterval := 5
 	if ci, ok := service.GetAnnotations()[annotations.AnnLinodeHealthCheckInterval]; ok {
 		if checkInterval, err = strconv.Atoi(ci); err != nil {
-			return config, err
+			return config, portConfigResult, err
 		}
 	}
 	config.CheckInterval = checkInterval
@@ -955,7 +1385,7 @@ func (l *loadbalancers) buildNodeBalancerConfig(ctx context.Context, service *v1
 	checkTimeout := 3
 	if ct, ok := service.GetAnnotations()[annotations.AnnLinodeHealthCheckTimeout]; ok {
 		if checkTimeout, err = strconv.Atoi(ct); err != nil {
-			return config, err
+			return config, portConfigResult, err
 		}
 	}
 	config.CheckTimeout = checkTimeout
@@ -963,7 +1393,7 @@ func (l *loadbalancers) buildNodeBalancerConfig(ctx context.Context, service *v1
 	checkAttempts := 2
 	if ca, ok := service.GetAnnotations()[annotations.AnnLinodeHealthCheckAttempts]; ok {
 		if checkAttempts, err = strconv.Atoi(ca); err != nil {
-			return config, err
+			return config, portConfigResult, err
 		}
 	}
 	config.CheckAttempts = checkAttempts
@@ -973,18 +1403,12 @@ func (l *loadbalancers) buildNodeBalancerConfig(ctx context.Context, service *v1
 		checkPassive = false
 	} else if cp, ok := service.GetAnnotations()[annotations.AnnLinodeHealthCheckPassive]; ok {
 		if checkPassive, err = strconv.ParseBool(cp); err != nil {
-			return config, err
+			return config, portConfigResult, err
 		}
 	}
 	config.CheckPassive = checkPassive
 
-	if portConfigResult.Protocol == linodego.ProtocolHTTPS {
-		if err = l.addTLSCert(ctx, service, &config, portConfigResult); err != nil {
-			return config, err
-		}
-	}
-
-	return config, nil
+	return config, portConfigResult, nil
 }
 
 func (l *loadbalancers) addTLSCert(ctx context.Context, service *v1.Service, nbConfig *linodego.NodeBalancerConfig, config portConfig) error {
@@ -993,6 +1417,11 @@ func (l *loadbalancers) addTLSCert(ctx context.Context, service *v1.Service, nbC
 		return err
 	}
 
+	secretNamespace, secretName := parseTLSSecretReference(service.Namespace, config.TLSSecretName)
+	if err = l.checkTLSSecretReference(ctx, service, secretNamespace, secretName); err != nil {
+		return err
+	}
+
 	nbConfig.SSLCert, nbConfig.SSLKey, err = getTLSCertInfo(ctx, l.kubeClient, service.Namespace, config)
 	if err != nil {
 		return err
@@ -1007,7 +1436,7 @@ func (l *loadbalancers) addTLSCert(ctx context.Context, service *v1.Service, nbC
 // 3. If CCM is configured with --nodebalancer-backend-ipv4-subnet-id, it will be used as the subnet ID.
 // 4. Else, use first VPCName and SubnetName to calculate subnet id for the service.
 func (l *loadbalancers) getSubnetIDForSVC(ctx context.Context, service *v1.Service) (int, error) {
-	if len(options.Options.VPCNames) == 0 {
+	if len(l.options.VPCNames) == 0 {
 		return 0, fmt.Errorf("CCM not configured with VPC, cannot create NodeBalancer with specified annotation")
 	}
 	// Check if the service has an annotation for NodeBalancerBackendSubnetID
@@ -1024,37 +1453,52 @@ func (l *loadbalancers) getSubnetIDForSVC(ctx context.Context, service *v1.Servi
 
 	// If no VPCName or SubnetName is specified in annotations, but NodeBalancerBackendIPv4SubnetID is set,
 	// use the NodeBalancerBackendIPv4SubnetID as the subnet ID.
-	if !vpcOk && !subnetOk && options.Options.NodeBalancerBackendIPv4SubnetID != 0 {
-		return options.Options.NodeBalancerBackendIPv4SubnetID, nil
+	if !vpcOk && !subnetOk && l.options.NodeBalancerBackendIPv4SubnetID != 0 {
+		return l.options.NodeBalancerBackendIPv4SubnetID, nil
 	}
 
-	vpcName := options.Options.VPCNames[0]
+	vpcName := l.options.VPCNames[0]
 	if vpcOk {
 		vpcName = specifiedVPCName
 	}
-	vpcID, err := services.GetVPCID(ctx, l.client, vpcName)
+	vpcID, err := l.vpcs.GetVPCID(ctx, l.client, vpcName)
 	if err != nil {
 		return 0, err
 	}
 
-	subnetName := options.Options.SubnetNames[0]
+	subnetName := l.options.SubnetNames[0]
 	if subnetOk {
 		subnetName = specifiedSubnetName
 	}
 
 	// Use the VPC ID and Subnet Name to get the subnet ID
-	return services.GetSubnetID(ctx, l.client, vpcID, subnetName)
+	return l.vpcs.GetSubnetID(ctx, l.client, vpcID, subnetName)
 }
 
 // buildLoadBalancerRequest returns a linodego.NodeBalancer
 // requests for service across nodes.
 func (l *loadbalancers) buildLoadBalancerRequest(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (*linodego.NodeBalancer, error) {
+	configs, err := l.buildNodeBalancerConfigCreateOptions(ctx, clusterName, service, nodes)
+	if err != nil {
+		return nil, err
+	}
+	return l.createNodeBalancer(ctx, clusterName, service, configs)
+}
+
+// buildNodeBalancerConfigCreateOptions returns the options creating the configs of a NodeBalancer
+// for service across nodes.
+func (l *loadbalancers) buildNodeBalancerConfigCreateOptions(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nodes []*v1.Node,
+) ([]linodego.NodeBalancerConfigCreateOptions, error) {
 	if len(nodes) == 0 {
 		return nil, fmt.Errorf("%w: cluster %s, service %s", errNoNodesAvailable, clusterName, getServiceNn(service))
 	}
 	ports := service.Spec.Ports
 	configs := make([]linodego.NodeBalancerConfigCreateOptions, 0, len(ports))
-	useIPv6Backends := resolveIPv6NodeBalancerBackendState(service)
+	useIPv6Backends := resolveIPv6NodeBalancerBackendState(l.options, service)
 
 	subnetID, err := l.getBackendSubnetID(ctx, service, useIPv6Backends)
 	if err != nil {
@@ -1079,7 +1523,7 @@ func (l *loadbalancers) buildLoadBalancerRequest(ctx context.Context, clusterNam
 
 		configs = append(configs, createOpt)
 	}
-	return l.createNodeBalancer(ctx, clusterName, service, configs)
+	return configs, nil
 }
 
 func coerceString(str string, minLen, maxLen int, padding string) string {
@@ -1105,12 +1549,12 @@ func (l *loadbalancers) buildNodeBalancerNodeConfigRebuildOptions(service *v1.Se
 			// NodeBalancer backends must be 3-32 chars in length
 			// If < 3 chars, pad node name with "node-" prefix
 			Label:  coerceString(node.Name, 3, 32, "node-"),
-			Weight: 100,
+			Weight: getNodeBalancerBackendWeight(node),
 		},
 	}
 	// Mode is not set for UDP protocol
 	if protocol != linodego.ProtocolUDP {
-		nodeOptions.Mode = "accept"
+		nodeOptions.Mode = getNodeBalancerBackendMode(node)
 	}
 	if !useIPv6Backends && subnetID != 0 {
 		nodeOptions.SubnetID = subnetID
@@ -1118,24 +1562,68 @@ func (l *loadbalancers) buildNodeBalancerNodeConfigRebuildOptions(service *v1.Se
 	return nodeOptions, nil
 }
 
+// getNodeAnnotationOrLabel returns the value of key from the node's annotations,
+// or from its labels if there is no such annotation.
+func getNodeAnnotationOrLabel(node *v1.Node, key string) (string, bool) {
+	if value, ok := node.Annotations[key]; ok {
+		return value, true
+	}
+	value, ok := node.Labels[key]
+	return value, ok
+}
+
+// getNodeBalancerBackendWeight returns the NodeBalancer backend weight requested for the node,
+// or the default weight if none or an invalid one is set.
+func getNodeBalancerBackendWeight(node *v1.Node) int {
+	raw, ok := getNodeAnnotationOrLabel(node, annotations.AnnLinodeNodeBalancerWeight)
+	if !ok {
+		return nodeBalancerBackendWeightDefault
+	}
+	weight, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || weight < nodeBalancerBackendWeightMin || weight > nodeBalancerBackendWeightMax {
+		klog.Warningf("Invalid NodeBalancer backend weight %q on node %s, it must be an integer between %d and %d; using %d",
+			raw, node.Name, nodeBalancerBackendWeightMin, nodeBalancerBackendWeightMax, nodeBalancerBackendWeightDefault)
+		return nodeBalancerBackendWeightDefault
+	}
+	return weight
+}
+
+// getNodeBalancerBackendMode returns drain for cordoned nodes and nodes marked for draining,
+// so that they stop receiving new connections while existing ones finish. Otherwise accept.
+func getNodeBalancerBackendMode(node *v1.Node) linodego.NodeMode {
+	if node.Spec.Unschedulable {
+		return linodego.ModeDrain
+	}
+	if raw, ok := getNodeAnnotationOrLabel(node, annotations.AnnLinodeNodeBalancerDrain); ok {
+		drain, err := strconv.ParseBool(raw)
+		if err != nil {
+			klog.Warningf("Invalid value %q for %s on node %s, expected true or false", raw, annotations.AnnLinodeNodeBalancerDrain, node.Name)
+		}
+		if drain {
+			return linodego.ModeDrain
+		}
+	}
+	return linodego.ModeAccept
+}
+
 func (l *loadbalancers) getBackendSubnetID(ctx context.Context, service *v1.Service, useIPv6Backends bool) (int, error) {
 	if useIPv6Backends {
 		return 0, nil
 	}
 
 	subnetID := 0
-	if options.Options.NodeBalancerBackendIPv4SubnetID != 0 {
-		subnetID = options.Options.NodeBalancerBackendIPv4SubnetID
+	if l.options.NodeBalancerBackendIPv4SubnetID != 0 {
+		subnetID = l.options.NodeBalancerBackendIPv4SubnetID
 	}
 
 	backendIPv4Range, ok := service.GetAnnotations()[annotations.NodeBalancerBackendIPv4Range]
 	if ok {
-		if err := validateNodeBalancerBackendIPv4Range(backendIPv4Range); err != nil {
+		if err := validateNodeBalancerBackendIPv4Range(l.options, backendIPv4Range); err != nil {
 			return 0, err
 		}
 	}
 
-	if len(options.Options.VPCNames) > 0 && !options.Options.DisableNodeBalancerVPCBackends {
+	if len(l.options.VPCNames) > 0 && !l.options.DisableNodeBalancerVPCBackends {
 		id, err := l.getSubnetIDForSVC(ctx, service)
 		if err != nil {
 			return 0, err
@@ -1146,13 +1634,13 @@ func (l *loadbalancers) getBackendSubnetID(ctx context.Context, service *v1.Serv
 	return subnetID, nil
 }
 
-func resolveIPv6NodeBalancerBackendState(service *v1.Service) bool {
+func resolveIPv6NodeBalancerBackendState(opts *options.Config, service *v1.Service) bool {
 	useIPv6 := getServiceBoolAnnotation(service, annotations.AnnLinodeEnableIPv6Backends)
 	if useIPv6 != nil {
 		return *useIPv6
 	}
 
-	return options.Options.EnableIPv6ForNodeBalancerBackends
+	return opts.EnableIPv6ForNodeBalancerBackends
 }
 
 func formatNodeBalancerBackendAddress(ip string, nodePort int32) string {
@@ -1191,20 +1679,7 @@ func (l *loadbalancers) retrieveKubeClient() error {
 		return nil
 	}
 
-	var (
-		kubeConfig *rest.Config
-		err        error
-	)
-
-	// Check to see if --kubeconfig was set. If it was, build a kubeconfig from the given file.
-	// Otherwise, use the in-cluster config.
-	kubeconfigFlag := options.Options.KubeconfigFlag
-	if kubeconfigFlag == nil || kubeconfigFlag.Value.String() == "" {
-		kubeConfig, err = rest.InClusterConfig()
-	} else {
-		kubeConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfigFlag.Value.String())
-	}
-
+	kubeConfig, err := buildKubeConfig(l.options)
 	if err != nil {
 		return err
 	}
@@ -1217,6 +1692,16 @@ func (l *loadbalancers) retrieveKubeClient() error {
 	return nil
 }
 
+// buildKubeConfig builds a kubeconfig from the file given by --kubeconfig if it was set.
+// Otherwise, it uses the in-cluster config.
+func buildKubeConfig(opts *options.Config) (*rest.Config, error) {
+	kubeconfigFlag := opts.KubeconfigFlag
+	if kubeconfigFlag == nil || kubeconfigFlag.Value.String() == "" {
+		return rest.InClusterConfig()
+	}
+	return clientcmd.BuildConfigFromFlags("", kubeconfigFlag.Value.String())
+}
+
 func getPortConfig(service *v1.Service, port v1.ServicePort) (portConfig, error) {
 	portConfigResult := portConfig{}
 	portConfigResult.Port = int(port.Port)
@@ -1252,6 +1737,10 @@ func getPortConfig(service *v1.Service, port v1.ServicePort) (portConfig, error)
 		return portConfigResult, fmt.Errorf("specifying TLS secret name is not supported for UDP")
 	}
 	portConfigResult.TLSSecretName = portConfigAnnotationResult.TLSSecretName
+	portConfigResult.TLSCertField, portConfigResult.TLSKeyField, err = getPortTLSFields(portConfigAnnotationResult)
+	if err != nil {
+		return portConfigResult, err
+	}
 
 	// validate and set udp check port
 	udpCheckPort, err := getPortUDPCheckPort(portConfigAnnotationResult, service, portConfigResult.Protocol)
@@ -1275,10 +1764,17 @@ func getPortConfig(service *v1.Service, port v1.ServicePort) (portConfig, error)
 	return portConfigResult, nil
 }
 
-func getHealthCheckType(service *v1.Service, port v1.ServicePort) (linodego.ConfigCheck, error) {
+// getHealthCheckType returns the health check of port, which checks udpCheckPort if it is a UDP port.
+func getHealthCheckType(service *v1.Service, port v1.ServicePort, udpCheckPort int) (linodego.ConfigCheck, error) {
 	hType, ok := service.GetAnnotations()[annotations.AnnLinodeHealthCheckType]
 	if !ok {
 		if port.Protocol == v1.ProtocolUDP {
+			// UDP configs can check a different port, so check kube-proxy's health check
+			// node port for Local services to only send traffic to nodes with ready endpoints.
+			// A UDP check port set by the user is left alone, it may not speak HTTP.
+			if healthCheckNodePort := getLocalHealthCheckNodePort(service); healthCheckNodePort != 0 && udpCheckPort == healthCheckNodePort {
+				return linodego.CheckHTTP, nil
+			}
 			return linodego.CheckNone, nil
 		}
 		return linodego.CheckConnection, nil
@@ -1348,57 +1844,102 @@ func getNodeBackendIP(service *v1.Service, node *v1.Node, subnetID int, useIPv6B
 	return "", fmt.Errorf("service %s requested IPv6 backends but node %s does not have a public IPv6 address", getServiceNn(service), node.Name)
 }
 
+// getPortTLSFields validates the keys of the certificate chain and private key in the TLS secret
+// of a port. They are empty if the port uses the keys of kubernetes.io/tls secrets.
+func getPortTLSFields(portConfigAnnotation portConfigAnnotation) (string, string, error) {
+	certField, keyField := portConfigAnnotation.TLSCertField, portConfigAnnotation.TLSKeyField
+	if certField == "" && keyField == "" {
+		return "", "", nil
+	}
+	if portConfigAnnotation.TLSSecretName == "" {
+		return "", "", errors.New("tls-cert-field and tls-key-field require tls-secret-name")
+	}
+	for _, field := range []string{certField, keyField} {
+		if field == "" {
+			continue
+		}
+		if errs := validation.IsConfigMapKey(field); len(errs) > 0 {
+			return "", "", fmt.Errorf("invalid TLS secret key %q: %s", field, strings.Join(errs, ", "))
+		}
+	}
+	if cmp.Or(certField, v1.TLSCertKey) == cmp.Or(keyField, v1.TLSPrivateKeyKey) {
+		return "", "", errors.New("tls-cert-field and tls-key-field must not be the same key")
+	}
+	return certField, keyField, nil
+}
+
+// getTLSCertInfo returns the certificate and key of the TLS secret of config. The secret is looked
+// up in namespace unless its name is prefixed with another namespace.
 func getTLSCertInfo(ctx context.Context, kubeClient kubernetes.Interface, namespace string, config portConfig) (string, string, error) {
 	if config.TLSSecretName == "" {
 		return "", "", fmt.Errorf("TLS secret name for port %v is not specified", config.Port)
 	}
 
-	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, config.TLSSecretName, metav1.GetOptions{})
+	secretNamespace, secretName := parseTLSSecretReference(namespace, config.TLSSecretName)
+	secret, err := kubeClient.CoreV1().Secrets(secretNamespace).Get(ctx, secretName, metav1.GetOptions{})
 	if err != nil {
 		return "", "", err
 	}
 
-	cert := string(secret.Data[v1.TLSCertKey])
+	certField := cmp.Or(config.TLSCertField, v1.TLSCertKey)
+	cert := string(secret.Data[certField])
 	cert = strings.TrimSpace(cert)
 
-	key := string(secret.Data[v1.TLSPrivateKeyKey])
+	keyField := cmp.Or(config.TLSKeyField, v1.TLSPrivateKeyKey)
+	key := string(secret.Data[keyField])
 
 	key = strings.TrimSpace(key)
 
+	if cert, err = validateTLSCertificate(cert, key, certField, keyField); err != nil {
+		return "", "", fmt.Errorf("invalid TLS secret %s/%s for port %d: %w", secretNamespace, secretName, config.Port, err)
+	}
+
 	return cert, key, nil
 }
 
 func getConnectionThrottle(service *v1.Service) int {
+	connThrottle, _ := parseConnectionThrottle(service)
+	return connThrottle
+}
+
+// parseConnectionThrottle returns the connection throttle for service, along with a warning
+// when the annotation value could not be used as given.
+func parseConnectionThrottle(service *v1.Service) (int, string) {
 	connThrottle := 0 // disable throttle if nothing is specified
 
-	if connThrottleString := service.GetAnnotations()[annotations.AnnLinodeThrottle]; connThrottleString != "" {
-		parsed, err := strconv.Atoi(connThrottleString)
-		if err == nil {
-			if parsed < 0 {
-				parsed = 0
-			}
+	connThrottleString := service.GetAnnotations()[annotations.AnnLinodeThrottle]
+	if connThrottleString == "" {
+		return connThrottle, ""
+	}
 
-			if parsed > maxConnThrottleStringLen {
-				parsed = maxConnThrottleStringLen
-			}
-			connThrottle = parsed
-		}
+	parsed, err := strconv.Atoi(connThrottleString)
+	if err != nil {
+		return connThrottle, fmt.Sprintf("invalid throttle %q specified in annotation %q, throttle is disabled", connThrottleString, annotations.AnnLinodeThrottle)
 	}
 
-	return connThrottle
+	var warning string
+	if parsed < 0 {
+		parsed = 0
+		warning = fmt.Sprintf("throttle %q specified in annotation %q is below 0, throttle is disabled", connThrottleString, annotations.AnnLinodeThrottle)
+	}
+
+	if parsed > maxConnThrottleStringLen {
+		parsed = maxConnThrottleStringLen
+		warning = fmt.Sprintf("throttle %q specified in annotation %q is above %d, using %d", connThrottleString, annotations.AnnLinodeThrottle, maxConnThrottleStringLen, maxConnThrottleStringLen)
+	}
+
+	return parsed, warning
 }
 
-func makeLoadBalancerStatus(service *v1.Service, nb *linodego.NodeBalancer) *v1.LoadBalancerStatus {
+func makeLoadBalancerStatus(opts *options.Config, service *v1.Service, nb *linodego.NodeBalancer) *v1.LoadBalancerStatus {
 	ingress := v1.LoadBalancerIngress{
 		Hostname: *nb.Hostname,
 	}
 
-	// Return hostname-only if annotation is set or environment variable is set
+	// Return hostname-only if annotation is set or it is configured for all services
 	useHostnameOnly := getServiceBoolAnnotation(service, annotations.AnnLinodeHostnameOnlyIngress)
 	if useHostnameOnly == nil {
-		val := envBoolOptions("LINODE_HOSTNAME_ONLY_INGRESS")
-		klog.Infof("LINODE_HOSTNAME_ONLY_INGRESS:  (%v)", val)
-		useHostnameOnly = ptr.To(envBoolOptions("LINODE_HOSTNAME_ONLY_INGRESS"))
+		useHostnameOnly = ptr.To(opts.Current().HostnameOnlyIngress)
 	}
 	if *useHostnameOnly {
 		return &v1.LoadBalancerStatus{
@@ -1413,7 +1954,7 @@ func makeLoadBalancerStatus(service *v1.Service, nb *linodego.NodeBalancer) *v1.
 	// Check for per-service IPv6 annotation first, then fall back to global setting if not set
 	useIPv6 := getServiceBoolAnnotation(service, annotations.AnnLinodeEnableIPv6Ingress)
 	if useIPv6 == nil {
-		useIPv6 = ptr.To(options.Options.EnableIPv6ForLoadBalancers)
+		useIPv6 = ptr.To(opts.EnableIPv6ForLoadBalancers)
 	}
 
 	// When IPv6 is enabled (either per-service or globally), include both IPv4 and IPv6
@@ -1441,15 +1982,6 @@ func makeLoadBalancerStatus(service *v1.Service, nb *linodego.NodeBalancer) *v1.
 	}
 }
 
-// Checks for a truth value in an environment variable
-func envBoolOptions(o string) bool {
-	boolValue, err := strconv.ParseBool(os.Getenv(o))
-	if err != nil {
-		return false
-	}
-	return boolValue
-}
-
 // getServiceNn returns the services namespaced name.
 func getServiceNn(service *v1.Service) string {
 	return fmt.Sprintf("%s/%s", service.Namespace, service.Name)
@@ -1466,16 +1998,16 @@ func getServiceBoolAnnotation(service *v1.Service, name string) *bool {
 
 // validateNodeBalancerBackendIPv4Range validates the NodeBalancerBackendIPv4Range
 // annotation to be within the NodeBalancerBackendIPv4Subnet if it is set.
-func validateNodeBalancerBackendIPv4Range(backendIPv4Range string) error {
-	if options.Options.NodeBalancerBackendIPv4Subnet == "" {
+func validateNodeBalancerBackendIPv4Range(opts *options.Config, backendIPv4Range string) error {
+	if opts.NodeBalancerBackendIPv4Subnet == "" {
 		return nil
 	}
-	withinCIDR, err := isCIDRWithinCIDR(options.Options.NodeBalancerBackendIPv4Subnet, backendIPv4Range)
+	withinCIDR, err := isCIDRWithinCIDR(opts.NodeBalancerBackendIPv4Subnet, backendIPv4Range)
 	if err != nil {
 		return fmt.Errorf("invalid IPv4 range: %w", err)
 	}
 	if !withinCIDR {
-		return fmt.Errorf("IPv4 range %s is not within the subnet %s", backendIPv4Range, options.Options.NodeBalancerBackendIPv4Subnet)
+		return fmt.Errorf("IPv4 range %s is not within the subnet %s", backendIPv4Range, opts.NodeBalancerBackendIPv4Subnet)
 	}
 	return nil
 }
@@ -0,0 +1,73 @@
+package linode
+
+import (
+	"github.com/linode/linodego/v2"
+)
+
+// nodeBalancerConfigNeedsUpdate reports whether the current NodeBalancer config differs from
+// the desired one built by buildNodeBalancerConfig. Fields the desired config leaves unset are
+// defaulted by the API and are therefore not compared.
+//
+// The API redacts the certificate and key of HTTPS configs, so the desired certificate is
+// compared against the fingerprint of the deployed one instead.
+func nodeBalancerConfigNeedsUpdate(current, desired *linodego.NodeBalancerConfig) bool {
+	if desired.Protocol == linodego.ProtocolHTTPS && !certificateMatchesFingerprint(desired.SSLCert, current.SSLFingerprint) {
+		return true
+	}
+
+	if current.Port != desired.Port ||
+		current.Protocol != desired.Protocol ||
+		current.ProxyProtocol != desired.ProxyProtocol ||
+		current.Algorithm != desired.Algorithm ||
+		current.Check != desired.Check ||
+		current.CheckInterval != desired.CheckInterval ||
+		current.CheckTimeout != desired.CheckTimeout ||
+		current.CheckAttempts != desired.CheckAttempts ||
+		current.CheckPassive != desired.CheckPassive {
+		return true
+	}
+
+	if desired.Stickiness != "" && current.Stickiness != desired.Stickiness {
+		return true
+	}
+	if desired.CheckPath != "" && current.CheckPath != desired.CheckPath {
+		return true
+	}
+	if desired.CheckBody != "" && current.CheckBody != desired.CheckBody {
+		return true
+	}
+	if desired.UDPCheckPort != 0 && current.UDPCheckPort != desired.UDPCheckPort {
+		return true
+	}
+
+	return false
+}
+
+// nodeBalancerNodesNeedUpdate reports whether the backend nodes of a NodeBalancer config differ
+// from the desired nodes built by buildNodeBalancerConfigNodes. Nodes are matched by address.
+func nodeBalancerNodesNeedUpdate(current []linodego.NodeBalancerNode, desired []linodego.NodeBalancerConfigRebuildNodeOptions) bool {
+	if len(current) != len(desired) {
+		return true
+	}
+
+	currentByAddress := make(map[string]linodego.NodeBalancerNode, len(current))
+	for _, node := range current {
+		currentByAddress[node.Address] = node
+	}
+
+	for _, node := range desired {
+		currentNode, ok := currentByAddress[node.Address]
+		if !ok {
+			return true
+		}
+		if currentNode.Label != node.Label || currentNode.Weight != node.Weight {
+			return true
+		}
+		// Mode is not set for UDP backends, the API picks it
+		if node.Mode != "" && currentNode.Mode != node.Mode {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,167 @@
+package linode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nodeBalancerConfigNeedsUpdate(t *testing.T) {
+	current := linodego.NodeBalancerConfig{
+		ID:            1,
+		Port:          80,
+		Protocol:      linodego.ProtocolHTTP,
+		ProxyProtocol: linodego.ProxyProtocolNone,
+		Algorithm:     linodego.AlgorithmRoundRobin,
+		Stickiness:    linodego.StickinessTable,
+		Check:         linodego.CheckHTTP,
+		CheckPath:     "/",
+		CheckInterval: 5,
+		CheckTimeout:  3,
+		CheckAttempts: 2,
+		CheckPassive:  true,
+	}
+
+	testcases := []struct {
+		name     string
+		mutate   func(desired *linodego.NodeBalancerConfig)
+		expected bool
+	}{
+		{
+			name:     "unchanged",
+			mutate:   func(desired *linodego.NodeBalancerConfig) {},
+			expected: false,
+		},
+		{
+			name:     "unset stickiness is defaulted by the API",
+			mutate:   func(desired *linodego.NodeBalancerConfig) { desired.Stickiness = "" },
+			expected: false,
+		},
+		{
+			name:     "algorithm changed",
+			mutate:   func(desired *linodego.NodeBalancerConfig) { desired.Algorithm = linodego.AlgorithmLeastConn },
+			expected: true,
+		},
+		{
+			name:     "check path changed",
+			mutate:   func(desired *linodego.NodeBalancerConfig) { desired.CheckPath = "/healthz" },
+			expected: true,
+		},
+		{
+			name:     "check passive changed",
+			mutate:   func(desired *linodego.NodeBalancerConfig) { desired.CheckPassive = false },
+			expected: true,
+		},
+		{
+			name:     "https without a known certificate fingerprint",
+			mutate:   func(desired *linodego.NodeBalancerConfig) { desired.Protocol = linodego.ProtocolHTTPS },
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			desired := current
+			desired.ID = 0
+			tc.mutate(&desired)
+			assert.Equal(t, tc.expected, nodeBalancerConfigNeedsUpdate(&current, &desired))
+		})
+	}
+}
+
+func Test_nodeBalancerConfigNeedsUpdateHTTPS(t *testing.T) {
+	cert, err := parseLeafCertificate(testCert)
+	require.NoError(t, err)
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	current := linodego.NodeBalancerConfig{
+		ID:             1,
+		Port:           443,
+		Protocol:       linodego.ProtocolHTTPS,
+		ProxyProtocol:  linodego.ProxyProtocolNone,
+		Algorithm:      linodego.AlgorithmRoundRobin,
+		Check:          linodego.CheckConnection,
+		CheckInterval:  5,
+		CheckTimeout:   3,
+		CheckAttempts:  2,
+		SSLFingerprint: hex.EncodeToString(fingerprint[:]),
+	}
+
+	desired := current
+	desired.ID = 0
+	desired.SSLCert = testCert
+	desired.SSLKey = testKey
+	assert.False(t, nodeBalancerConfigNeedsUpdate(&current, &desired), "same certificate")
+
+	current.SSLFingerprint = strings.Repeat("00", sha256.Size)
+	assert.True(t, nodeBalancerConfigNeedsUpdate(&current, &desired), "rotated certificate")
+}
+
+func Test_nodeBalancerNodesNeedUpdate(t *testing.T) {
+	current := []linodego.NodeBalancerNode{
+		{ID: 1, Address: "10.0.0.1:30000", Label: "node-1", Weight: 100, Mode: linodego.ModeAccept},
+		{ID: 2, Address: "10.0.0.2:30000", Label: "node-2", Weight: 100, Mode: linodego.ModeAccept},
+	}
+	newDesired := func() []linodego.NodeBalancerConfigRebuildNodeOptions {
+		return []linodego.NodeBalancerConfigRebuildNodeOptions{
+			{NodeBalancerNodeCreateOptions: linodego.NodeBalancerNodeCreateOptions{Address: "10.0.0.2:30000", Label: "node-2", Weight: 100, Mode: linodego.ModeAccept}},
+			{NodeBalancerNodeCreateOptions: linodego.NodeBalancerNodeCreateOptions{Address: "10.0.0.1:30000", Label: "node-1", Weight: 100, Mode: linodego.ModeAccept}},
+		}
+	}
+
+	testcases := []struct {
+		name     string
+		mutate   func([]linodego.NodeBalancerConfigRebuildNodeOptions) []linodego.NodeBalancerConfigRebuildNodeOptions
+		expected bool
+	}{
+		{
+			name: "same nodes in different order",
+			mutate: func(desired []linodego.NodeBalancerConfigRebuildNodeOptions) []linodego.NodeBalancerConfigRebuildNodeOptions {
+				return desired
+			},
+			expected: false,
+		},
+		{
+			name: "mode left to the API",
+			mutate: func(desired []linodego.NodeBalancerConfigRebuildNodeOptions) []linodego.NodeBalancerConfigRebuildNodeOptions {
+				desired[0].Mode = ""
+				return desired
+			},
+			expected: false,
+		},
+		{
+			name: "node removed",
+			mutate: func(desired []linodego.NodeBalancerConfigRebuildNodeOptions) []linodego.NodeBalancerConfigRebuildNodeOptions {
+				return desired[:1]
+			},
+			expected: true,
+		},
+		{
+			name: "node address changed",
+			mutate: func(desired []linodego.NodeBalancerConfigRebuildNodeOptions) []linodego.NodeBalancerConfigRebuildNodeOptions {
+				desired[0].Address = "10.0.0.3:30000"
+				return desired
+			},
+			expected: true,
+		},
+		{
+			name: "node weight changed",
+			mutate: func(desired []linodego.NodeBalancerConfigRebuildNodeOptions) []linodego.NodeBalancerConfigRebuildNodeOptions {
+				desired[1].Weight = 50
+				return desired
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nodeBalancerNodesNeedUpdate(current, tc.mutate(newDesired())))
+		})
+	}
+}
@@ -15,6 +15,13 @@ const (
 	udpCheckPortDefault = 80
 	udpCheckPortMin     = 1
 	udpCheckPortMax     = 65535
+
+	nodeBalancerBackendWeightDefault = 100
+	nodeBalancerBackendWeightMin     = 1
+	nodeBalancerBackendWeightMax     = 255
+
+	// localHealthCheckPath is checked on the health check node port of Local services
+	localHealthCheckPath = "/healthz"
 )
 
 // getPortProtocol returns the protocol for a given service port.
@@ -104,6 +111,9 @@ func getPortUDPCheckPort(portConfigAnnotationResult portConfigAnnotation, servic
 	if protocol != linodego.ProtocolUDP {
 		return udpCheckPort, nil
 	}
+	if healthCheckNodePort := getLocalHealthCheckNodePort(service); healthCheckNodePort != 0 {
+		udpCheckPort = healthCheckNodePort
+	}
 
 	if portConfigAnnotationResult.UDPCheckPort != "" {
 		cp, err := strconv.Atoi(portConfigAnnotationResult.UDPCheckPort)
@@ -126,6 +136,16 @@ func getPortUDPCheckPort(portConfigAnnotationResult portConfigAnnotation, servic
 	return udpCheckPort, nil
 }
 
+// getLocalHealthCheckNodePort returns the health check node port that kube-proxy serves for a
+// service with externalTrafficPolicy Local. It reports whether the node has ready endpoints for
+// the service. Zero is returned for other services.
+func getLocalHealthCheckNodePort(service *v1.Service) int {
+	if service.Spec.ExternalTrafficPolicy != v1.ServiceExternalTrafficPolicyLocal {
+		return 0
+	}
+	return int(service.Spec.HealthCheckNodePort)
+}
+
 // getDefaultStickiness returns the default stickiness for a given protocol.
 // For UDP, it returns StickinessSession, and for other protocols, it returns StickinessTable.
 func getDefaultStickiness(protocol string) linodego.ConfigStickiness {
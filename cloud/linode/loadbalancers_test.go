@@ -11,12 +11,13 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/linode/linodego/v2"
@@ -200,6 +201,10 @@ func TestCCMLoadBalancers(t *testing.T) {
 			name: "Create Load Balancer With Reserved IP",
 			f:    testCreateNodeBalancerWithReservedIP,
 		},
+		{
+			name: "Create Load Balancer With Automatically Reserved IP",
+			f:    testCreateNodeBalancerWithReserveIPv4,
+		},
 		{
 			name: "Update Load Balancer - Add Node",
 			f:    testUpdateLoadBalancerAddNode,
@@ -347,7 +352,7 @@ func stubServiceUpdate(fake *fake.Clientset, service *v1.Service) {
 	_, _ = fake.CoreV1().Services("").Update(context.TODO(), service, metav1.UpdateOptions{})
 }
 
-func testCreateNodeBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI, annMap map[string]string, expectedTags []string) error {
+func testCreateNodeBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI, opts *options.Config, annMap map[string]string, expectedTags []string) error {
 	t.Helper()
 
 	svc := &v1.Service{
@@ -379,7 +384,7 @@ func testCreateNodeBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI, a
 	for key, value := range annMap {
 		svc.Annotations[key] = value
 	}
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -432,6 +437,8 @@ func testCreateNodeBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI, a
 	if len(expectedTags) == 0 {
 		expectedTags = []string{"linodelb", "fake", "test", "yolo"}
 	}
+	// the ownership tags follow the cluster name
+	expectedTags = slices.Insert(slices.Clone(expectedTags), 1, nodeBalancerOwnershipTags("linodelb", svc)...)
 	if !reflect.DeepEqual(nb.Tags, expectedTags) {
 		t.Error("unexpected Tags")
 		t.Logf("expected: %v", expectedTags)
@@ -461,16 +468,98 @@ func testCreateNodeBalancerWithReservedIP(t *testing.T, client *linodego.Client,
 	annMap := map[string]string{
 		annotations.AnnLinodeLoadBalancerReservedIPv4: "156.1.1.101",
 	}
-	err := testCreateNodeBalancer(t, client, f, annMap, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, annMap, nil)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
 }
 
+func testCreateNodeBalancerWithReserveIPv4(t *testing.T, client *linodego.Client, f *fakeAPI) {
+	t.Helper()
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: randString(),
+			UID:  "foobar123",
+			Annotations: map[string]string{
+				annotations.AnnLinodeLoadBalancerReserveIPv4:        "true",
+				annotations.AnnLinodeLoadBalancerRetainReservedIPv4: "false",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{
+					Name:     randString(),
+					Protocol: "TCP",
+					Port:     int32(80),
+					NodePort: int32(30000),
+				},
+			},
+		},
+	}
+	nodes := []*v1.Node{
+		{
+			Status: v1.NodeStatus{
+				Addresses: []v1.NodeAddress{
+					{
+						Type:    v1.NodeInternalIP,
+						Address: "127.0.0.1",
+					},
+				},
+			},
+		},
+	}
+
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
+	if !assertion {
+		t.Error("type assertion failed")
+	}
+	fakeClientset := fake.NewClientset()
+	lb.kubeClient = fakeClientset
+	stubService(fakeClientset, svc)
+	f.ResetRequests()
+
+	status, err := lb.EnsureLoadBalancer(t.Context(), "linodelb", svc, nodes)
+	if err != nil {
+		t.Fatalf("EnsureLoadBalancer returned an error: %s", err)
+	}
+	if !f.didRequestOccur(http.MethodPost, "/networking/reserved/ips", `{"region":"us-west"}`) {
+		t.Fatal("expected an IP address to be reserved")
+	}
+
+	updated, err := fakeClientset.CoreV1().Services("").Get(t.Context(), svc.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get service: %s", err)
+	}
+	reservedIP := updated.Annotations[annotations.AnnLinodeLoadBalancerReservedIPv4]
+	if reservedIP == "" || status.Ingress[0].IP != reservedIP {
+		t.Fatalf("expected the reserved IP to be recorded on the service and used, got %q and status %v", reservedIP, status.Ingress)
+	}
+
+	// The recorded address is used from now on
+	f.ResetRequests()
+	updated.Status.LoadBalancer = *status
+	stubServiceUpdate(fakeClientset, updated)
+	if _, err = lb.EnsureLoadBalancer(t.Context(), "linodelb", updated, nodes); err != nil {
+		t.Fatalf("EnsureLoadBalancer returned an error: %s", err)
+	}
+	if f.didRequestOccur(http.MethodPost, "/networking/reserved/ips", `{"region":"us-west"}`) {
+		t.Fatal("expected no other IP address to be reserved")
+	}
+
+	if err = lb.EnsureLoadBalancerDeleted(t.Context(), "linodelb", updated); err != nil {
+		t.Fatalf("EnsureLoadBalancerDeleted returned an error: %s", err)
+	}
+	if !f.didRequestOccur(http.MethodDelete, "/networking/reserved/ips/"+reservedIP, "") {
+		t.Fatal("expected the reserved IP to be released")
+	}
+}
+
 func testCreateNodeBalancerWithOutFirewall(t *testing.T, client *linodego.Client, f *fakeAPI) {
 	t.Helper()
 
-	err := testCreateNodeBalancer(t, client, f, nil, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, nil, nil)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
@@ -505,7 +594,8 @@ func testCreateNodeBalancerWithNodeNoAddresses(t *testing.T, client *linodego.Cl
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -530,7 +620,7 @@ func testCreateNodeBalanceWithNoAllowOrDenyList(t *testing.T, client *linodego.C
 		annotations.AnnLinodeCloudFirewallACL: `{}`,
 	}
 
-	err := testCreateNodeBalancer(t, client, f, annotations, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, annotations, nil)
 	if err == nil || !stderrors.Is(err, services.ErrInvalidFWConfig) {
 		t.Fatalf("expected a %v error, got %v", services.ErrInvalidFWConfig, err)
 	}
@@ -552,7 +642,7 @@ func testCreateNodeBalanceWithBothAllowOrDenyList(t *testing.T, client *linodego
 		}`,
 	}
 
-	err := testCreateNodeBalancer(t, client, f, annotations, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, annotations, nil)
 	if err == nil || !stderrors.Is(err, services.ErrInvalidFWConfig) {
 		t.Fatalf("expected a %v error, got %v", services.ErrInvalidFWConfig, err)
 	}
@@ -570,7 +660,7 @@ func testCreateNodeBalancerWithAllowList(t *testing.T, client *linodego.Client,
 		}`,
 	}
 
-	err := testCreateNodeBalancer(t, client, f, annotations, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, annotations, nil)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
@@ -588,7 +678,7 @@ func testCreateNodeBalancerWithDenyList(t *testing.T, client *linodego.Client, f
 		}`,
 	}
 
-	err := testCreateNodeBalancer(t, client, f, annotations, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, annotations, nil)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
@@ -600,7 +690,7 @@ func testCreateNodeBalancerWithFirewall(t *testing.T, client *linodego.Client, f
 	annotations := map[string]string{
 		annotations.AnnLinodeCloudFirewallID: "123",
 	}
-	err := testCreateNodeBalancer(t, client, f, annotations, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, annotations, nil)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
@@ -613,7 +703,7 @@ func testCreateNodeBalancerWithInvalidFirewall(t *testing.T, client *linodego.Cl
 		annotations.AnnLinodeCloudFirewallID: "qwerty",
 	}
 	expectedError := "strconv.Atoi: parsing \"qwerty\": invalid syntax"
-	err := testCreateNodeBalancer(t, client, f, annotations, nil)
+	err := testCreateNodeBalancer(t, client, f, &options.Config{}, annotations, nil)
 	if err != nil && err.Error() != expectedError {
 		t.Fatalf("expected a %s error, got %v", expectedError, err)
 	}
@@ -622,13 +712,11 @@ func testCreateNodeBalancerWithInvalidFirewall(t *testing.T, client *linodego.Cl
 func testCreateNodeBalancerWithGlobalTags(t *testing.T, client *linodego.Client, f *fakeAPI) {
 	t.Helper()
 
-	original := options.Options.NodeBalancerTags
-	defer func() {
-		options.Options.NodeBalancerTags = original
-	}()
-	options.Options.NodeBalancerTags = []string{"foobar"}
+	opts := &options.Config{
+		Reloadable: options.Reloadable{NodeBalancerTags: []string{"foobar"}},
+	}
 	expectedTags := []string{"linodelb", "foobar", "fake", "test", "yolo"}
-	err := testCreateNodeBalancer(t, client, f, nil, expectedTags)
+	err := testCreateNodeBalancer(t, client, f, opts, nil, expectedTags)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
@@ -642,14 +730,10 @@ func testCreateNodeBalancerWithVPCBackend(t *testing.T, client *linodego.Client,
 		annotations.NodeBalancerBackendIPv4Range: "10.100.0.0/30",
 	}
 
-	vpcNames := options.Options.VPCNames
-	subnetNames := options.Options.SubnetNames
-	defer func() {
-		options.Options.VPCNames = vpcNames
-		options.Options.SubnetNames = subnetNames
-	}()
-	options.Options.VPCNames = []string{"test1"}
-	options.Options.SubnetNames = []string{defaultSubnet}
+	opts := &options.Config{
+		VPCNames:    []string{"test1"},
+		SubnetNames: []string{defaultSubnet},
+	}
 	_, _ = client.CreateVPC(t.Context(), linodego.VPCCreateOptions{
 		Label:       "test1",
 		Description: "",
@@ -662,7 +746,7 @@ func testCreateNodeBalancerWithVPCBackend(t *testing.T, client *linodego.Client,
 		},
 	})
 
-	err := testCreateNodeBalancer(t, client, f, ann, nil)
+	err := testCreateNodeBalancer(t, client, f, opts, ann, nil)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
@@ -670,12 +754,8 @@ func testCreateNodeBalancerWithVPCBackend(t *testing.T, client *linodego.Client,
 	f.ResetRequests()
 
 	// test with IPv4Range outside of defined NodeBalancer subnet
-	nodebalancerBackendIPv4Subnet := options.Options.NodeBalancerBackendIPv4Subnet
-	defer func() {
-		options.Options.NodeBalancerBackendIPv4Subnet = nodebalancerBackendIPv4Subnet
-	}()
-	options.Options.NodeBalancerBackendIPv4Subnet = "10.99.0.0/24"
-	if err := testCreateNodeBalancer(t, client, f, ann, nil); err == nil {
+	opts.NodeBalancerBackendIPv4Subnet = "10.99.0.0/24"
+	if err := testCreateNodeBalancer(t, client, f, opts, ann, nil); err == nil {
 		t.Fatalf("expected nodebalancer creation to fail")
 	}
 }
@@ -684,14 +764,10 @@ func testUpdateNodeBalancerWithVPCBackend(t *testing.T, client *linodego.Client,
 	t.Helper()
 
 	// provision vpc and test
-	vpcNames := options.Options.VPCNames
-	subnetNames := options.Options.SubnetNames
-	defer func() {
-		options.Options.VPCNames = vpcNames
-		options.Options.SubnetNames = subnetNames
-	}()
-	options.Options.VPCNames = []string{"test1"}
-	options.Options.SubnetNames = []string{defaultSubnet}
+	opts := &options.Config{
+		VPCNames:    []string{"test1"},
+		SubnetNames: []string{defaultSubnet},
+	}
 	_, _ = client.CreateVPC(t.Context(), linodego.VPCCreateOptions{
 		Label:       "test1",
 		Description: "",
@@ -737,7 +813,7 @@ func testUpdateNodeBalancerWithVPCBackend(t *testing.T, client *linodego.Client,
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -772,17 +848,11 @@ func testCreateNodeBalancerWithVPCOnlySubnetFlag(t *testing.T, client *linodego.
 	t.Helper()
 
 	// provision vpc and test
-	vpcNames := options.Options.VPCNames
-	subnetNames := options.Options.SubnetNames
-	nbBackendSubnet := options.Options.NodeBalancerBackendIPv4Subnet
-	defer func() {
-		options.Options.VPCNames = vpcNames
-		options.Options.SubnetNames = subnetNames
-		options.Options.NodeBalancerBackendIPv4Subnet = nbBackendSubnet
-	}()
-	options.Options.VPCNames = []string{"test-subflag"}
-	options.Options.SubnetNames = []string{defaultSubnet}
-	options.Options.NodeBalancerBackendIPv4Subnet = "10.254.0.0/24"
+	opts := &options.Config{
+		VPCNames:                      []string{"test-subflag"},
+		SubnetNames:                   []string{defaultSubnet},
+		NodeBalancerBackendIPv4Subnet: "10.254.0.0/24",
+	}
 	_, _ = client.CreateVPC(t.Context(), linodego.VPCCreateOptions{
 		Label:       "test-subflag",
 		Description: "",
@@ -826,7 +896,7 @@ func testCreateNodeBalancerWithVPCOnlySubnetFlag(t *testing.T, client *linodego.
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -870,14 +940,10 @@ func testCreateNodeBalancerWithVPCNoFlagOrAnnotation(t *testing.T, client *linod
 	t.Helper()
 
 	// provision vpc and test
-	vpcNames := options.Options.VPCNames
-	subnetNames := options.Options.SubnetNames
-	defer func() {
-		options.Options.VPCNames = vpcNames
-		options.Options.SubnetNames = subnetNames
-	}()
-	options.Options.VPCNames = []string{"test-noflags"}
-	options.Options.SubnetNames = []string{defaultSubnet}
+	opts := &options.Config{
+		VPCNames:    []string{"test-noflags"},
+		SubnetNames: []string{defaultSubnet},
+	}
 	_, _ = client.CreateVPC(t.Context(), linodego.VPCCreateOptions{
 		Label:       "test-noflags",
 		Description: "",
@@ -921,7 +987,7 @@ func testCreateNodeBalancerWithVPCNoFlagOrAnnotation(t *testing.T, client *linod
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -955,14 +1021,10 @@ func testCreateNodeBalancerWithVPCAnnotationOnly(t *testing.T, client *linodego.
 	t.Helper()
 
 	// provision vpc and test
-	vpcNames := options.Options.VPCNames
-	subnetNames := options.Options.SubnetNames
-	defer func() {
-		options.Options.VPCNames = vpcNames
-		options.Options.SubnetNames = subnetNames
-	}()
-	options.Options.VPCNames = []string{"test-onlyannotation"}
-	options.Options.SubnetNames = []string{defaultSubnet}
+	opts := &options.Config{
+		VPCNames:    []string{"test-onlyannotation"},
+		SubnetNames: []string{defaultSubnet},
+	}
 	_, _ = client.CreateVPC(t.Context(), linodego.VPCCreateOptions{
 		Label:       "test-onlyannotation",
 		Description: "",
@@ -1012,7 +1074,7 @@ func testCreateNodeBalancerWithVPCAnnotationOnly(t *testing.T, client *linodego.
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1045,17 +1107,11 @@ func testCreateNodeBalancerWithVPCOnlySubnetIDFlag(t *testing.T, client *linodeg
 	t.Helper()
 
 	// provision vpc and test
-	vpcNames := options.Options.VPCNames
-	subnetNames := options.Options.SubnetNames
-	nbBackendSubnetID := options.Options.NodeBalancerBackendIPv4SubnetID
-	defer func() {
-		options.Options.VPCNames = vpcNames
-		options.Options.SubnetNames = subnetNames
-		options.Options.NodeBalancerBackendIPv4SubnetID = nbBackendSubnetID
-	}()
-	options.Options.VPCNames = []string{"test1"}
-	options.Options.SubnetNames = []string{defaultSubnet}
-	options.Options.NodeBalancerBackendIPv4SubnetID = 1111
+	opts := &options.Config{
+		VPCNames:                        []string{"test1"},
+		SubnetNames:                     []string{defaultSubnet},
+		NodeBalancerBackendIPv4SubnetID: 1111,
+	}
 	_, _ = client.CreateVPC(t.Context(), linodego.VPCCreateOptions{
 		Label:       "test-subid-flag",
 		Description: "",
@@ -1099,7 +1155,7 @@ func testCreateNodeBalancerWithVPCOnlySubnetIDFlag(t *testing.T, client *linodeg
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1135,17 +1191,11 @@ func testCreateNodeBalancerWithVPCAnnotationOverwrite(t *testing.T, client *lino
 	t.Helper()
 
 	// provision multiple vpcs
-	vpcNames := options.Options.VPCNames
-	subnetNames := options.Options.SubnetNames
-	nodebalancerBackendIPv4Subnet := options.Options.NodeBalancerBackendIPv4Subnet
-	defer func() {
-		options.Options.VPCNames = vpcNames
-		options.Options.SubnetNames = subnetNames
-		options.Options.NodeBalancerBackendIPv4Subnet = nodebalancerBackendIPv4Subnet
-	}()
-	options.Options.VPCNames = []string{"test1"}
-	options.Options.SubnetNames = []string{defaultSubnet}
-	options.Options.NodeBalancerBackendIPv4Subnet = "10.100.0.0/24"
+	opts := &options.Config{
+		VPCNames:                      []string{"test1"},
+		SubnetNames:                   []string{defaultSubnet},
+		NodeBalancerBackendIPv4Subnet: "10.100.0.0/24",
+	}
 
 	_, _ = client.CreateVPC(t.Context(), linodego.VPCCreateOptions{
 		Label:       "test1",
@@ -1175,7 +1225,7 @@ func testCreateNodeBalancerWithVPCAnnotationOverwrite(t *testing.T, client *lino
 		annotations.NodeBalancerBackendVPCName:    "test2",
 		annotations.NodeBalancerBackendSubnetName: "subnet1",
 	}
-	err := testCreateNodeBalancer(t, client, f, ann, nil)
+	err := testCreateNodeBalancer(t, client, f, opts, ann, nil)
 	if err != nil {
 		t.Fatalf("expected a nil error, got %v", err)
 	}
@@ -1247,7 +1297,8 @@ func testUpdateLoadBalancerAddNode(t *testing.T, client *linodego.Client, f *fak
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1268,23 +1319,17 @@ func testUpdateLoadBalancerAddNode(t *testing.T, client *linodego.Client, f *fak
 
 	stubService(fakeClientset, svc)
 
-	f.ResetRequests()
-
-	err = lb.UpdateLoadBalancer(t.Context(), "linodelb", svc, nodes1)
-	if err != nil {
-		t.Errorf("UpdateLoadBalancer returned an error while updated LB to have one node: %s", err)
-	}
-
 	rx := regexp.MustCompile("/nodebalancers/[0-9]+/configs/[0-9]+/rebuild")
-	checkIDs := func() (int, int) {
-		var req *fakeRequest
+	findRebuild := func() *fakeRequest {
 		for request := range f.requests {
 			if rx.MatchString(request.Path) {
-				req = &request
-				break
+				return &request
 			}
 		}
-
+		return nil
+	}
+	checkIDs := func() (int, int) {
+		req := findRebuild()
 		if req == nil {
 			t.Fatalf("Nodebalancer config rebuild request was not called.")
 			return 0, 0 // explicitly return to satisfy staticcheck
@@ -1306,12 +1351,13 @@ func testUpdateLoadBalancerAddNode(t *testing.T, client *linodego.Client, f *fak
 		return len(nbcro.Nodes), withIds
 	}
 
-	nodecount, nodeswithIdcount := checkIDs()
-	if nodecount != 1 {
-		t.Fatalf("Unexpected node count (%d) in request on updating the nodebalancer with one node.", nodecount)
+	f.ResetRequests()
+	err = lb.UpdateLoadBalancer(t.Context(), "linodelb", svc, nodes1)
+	if err != nil {
+		t.Errorf("UpdateLoadBalancer returned an error while updated LB to have one node: %s", err)
 	}
-	if nodeswithIdcount != 1 {
-		t.Fatalf("Expected Node ID to be set when updating the nodebalancer with the same node it had previously.")
+	if findRebuild() != nil {
+		t.Fatalf("Nodebalancer config rebuild request was called although the config did not change.")
 	}
 
 	f.ResetRequests()
@@ -1319,7 +1365,7 @@ func testUpdateLoadBalancerAddNode(t *testing.T, client *linodego.Client, f *fak
 	if err != nil {
 		t.Errorf("UpdateLoadBalancer returned an error while updated LB to have three nodes: %s", err)
 	}
-	nodecount, nodeswithIdcount = checkIDs()
+	nodecount, nodeswithIdcount := checkIDs()
 	if nodecount != 3 {
 		t.Fatalf("Unexpected node count (%d) in request on updating the nodebalancer with three nodes.", nodecount)
 	}
@@ -1332,6 +1378,16 @@ func testUpdateLoadBalancerAddNode(t *testing.T, client *linodego.Client, f *fak
 	if err != nil {
 		t.Errorf("UpdateLoadBalancer returned an error while updated LB to have three nodes second time: %s", err)
 	}
+	if findRebuild() != nil {
+		t.Fatalf("Nodebalancer config rebuild request was called although the config did not change.")
+	}
+
+	f.ResetRequests()
+	svc.Annotations = map[string]string{annotations.AnnLinodeHealthCheckInterval: "10"}
+	err = lb.UpdateLoadBalancer(t.Context(), "linodelb", svc, nodes2)
+	if err != nil {
+		t.Errorf("UpdateLoadBalancer returned an error while updating the health check interval: %s", err)
+	}
 	nodecount, nodeswithIdcount = checkIDs()
 	if nodecount != 3 {
 		t.Fatalf("Unexpected node count (%d) in request on updating the nodebalancer with three nodes second time.", nodecount)
@@ -1414,7 +1470,8 @@ func testUpdateLoadBalancerAddAnnotation(t *testing.T, client *linodego.Client,
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1491,7 +1548,8 @@ func testUpdateLoadBalancerAddPortAnnotation(t *testing.T, client *linodego.Clie
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1606,7 +1664,8 @@ func testVeryLongServiceName(t *testing.T, client *linodego.Client, _ *fakeAPI)
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1676,7 +1735,8 @@ func testUpdateLoadBalancerAddTags(t *testing.T, client *linodego.Client, _ *fak
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1712,7 +1772,8 @@ func testUpdateLoadBalancerAddTags(t *testing.T, client *linodego.Client, _ *fak
 		t.Fatalf("failed to get NodeBalancer by status: %v", err)
 	}
 
-	expectedTags := append([]string{clusterName}, strings.Split(testTags, ",")...)
+	expectedTags := append([]string{clusterName}, nodeBalancerOwnershipTags(clusterName, svc)...)
+	expectedTags = append(expectedTags, strings.Split(testTags, ",")...)
 	observedTags := nb.Tags
 
 	if !reflect.DeepEqual(expectedTags, observedTags) {
@@ -1763,7 +1824,8 @@ func testUpdateLoadBalancerAddTLSPort(t *testing.T, client *linodego.Client, _ *
 		NodePort: int32(30001),
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1846,7 +1908,8 @@ func testUpdateLoadBalancerAddProxyProtocol(t *testing.T, client *linodego.Clien
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -1905,7 +1968,7 @@ func testUpdateLoadBalancerAddProxyProtocol(t *testing.T, client *linodego.Clien
 				t.Fatalf("failed to create NodeBalancer: %s", err)
 			}
 
-			svc.Status.LoadBalancer = *makeLoadBalancerStatus(svc, nodeBalancer)
+			svc.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svc, nodeBalancer)
 			svc.SetAnnotations(map[string]string{
 				annotations.AnnLinodeDefaultProxyProtocol: string(tc.proxyProtocolConfig),
 			})
@@ -1974,7 +2037,8 @@ func testUpdateLoadBalancerAddNewFirewall(t *testing.T, client *linodego.Client,
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -2082,7 +2146,8 @@ func testUpdateLoadBalancerAddNewFirewallACL(t *testing.T, client *linodego.Clie
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -2216,7 +2281,8 @@ func testUpdateLoadBalancerDeleteFirewallRemoveACL(t *testing.T, client *linodeg
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -2317,7 +2383,8 @@ func testUpdateLoadBalancerUpdateFirewallRemoveACLaddID(t *testing.T, client *li
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -2464,7 +2531,8 @@ func testUpdateLoadBalancerUpdateFirewallRemoveIDaddACL(t *testing.T, client *li
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -2618,7 +2686,8 @@ func testUpdateLoadBalancerUpdateFirewallACL(t *testing.T, client *linodego.Clie
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -2861,7 +2930,8 @@ func testUpdateLoadBalancerUpdateFirewall(t *testing.T, client *linodego.Client,
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -3001,7 +3071,8 @@ func testUpdateLoadBalancerDeleteFirewallRemoveID(t *testing.T, client *linodego
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -3106,7 +3177,8 @@ func testUpdateLoadBalancerAddReservedIP(t *testing.T, client *linodego.Client,
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, region).(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, region, opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -3125,7 +3197,7 @@ func testUpdateLoadBalancerAddReservedIP(t *testing.T, client *linodego.Client,
 	}
 
 	initialIP := *nodeBalancer.IPv4
-	svc.Status.LoadBalancer = *makeLoadBalancerStatus(svc, nodeBalancer)
+	svc.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svc, nodeBalancer)
 
 	ipaddr, err := client.ReserveIPAddress(t.Context(), linodego.ReserveIPOptions{
 		Region: lb.zone,
@@ -3197,7 +3269,8 @@ func testUpdateLoadBalancerAddNodeBalancerID(t *testing.T, client *linodego.Clie
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -3215,7 +3288,7 @@ func testUpdateLoadBalancerAddNodeBalancerID(t *testing.T, client *linodego.Clie
 		t.Fatalf("failed to create NodeBalancer: %s", err)
 	}
 
-	svc.Status.LoadBalancer = *makeLoadBalancerStatus(svc, nodeBalancer)
+	svc.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svc, nodeBalancer)
 
 	newNodeBalancer, err := client.CreateNodeBalancer(t.Context(), linodego.NodeBalancerCreateOptions{
 		Region: lb.zone,
@@ -3239,7 +3312,7 @@ func testUpdateLoadBalancerAddNodeBalancerID(t *testing.T, client *linodego.Clie
 		t.Errorf("GetLoadBalancer returned an error: %s", err)
 	}
 
-	expectedLBStatus := makeLoadBalancerStatus(svc, newNodeBalancer)
+	expectedLBStatus := makeLoadBalancerStatus(opts, svc, newNodeBalancer)
 	if !reflect.DeepEqual(expectedLBStatus, lbStatus) {
 		t.Errorf("LoadBalancer status mismatch: expected %v, got %v", expectedLBStatus, lbStatus)
 	}
@@ -3996,7 +4069,7 @@ func Test_getHealthCheckType(t *testing.T) {
 				Protocol: v1.ProtocolTCP,
 				Port:     int32(443),
 			}
-			hType, err := getHealthCheckType(test.service, port)
+			hType, err := getHealthCheckType(test.service, port, 0)
 			if !reflect.DeepEqual(hType, test.healthType) {
 				t.Error("unexpected health check type")
 				t.Logf("expected: %v", test.healthType)
@@ -4012,6 +4085,114 @@ func Test_getHealthCheckType(t *testing.T) {
 	}
 }
 
+func Test_newNodeBalancerConfigExternalTrafficPolicyLocal(t *testing.T) {
+	testcases := []struct {
+		name                 string
+		protocol             v1.Protocol
+		trafficPolicy        v1.ServiceExternalTrafficPolicy
+		annotations          map[string]string
+		expectedCheck        linodego.ConfigCheck
+		expectedCheckPath    string
+		expectedUDPCheckPort int
+	}{
+		{
+			name:                 "udp local checks health check node port",
+			protocol:             v1.ProtocolUDP,
+			trafficPolicy:        v1.ServiceExternalTrafficPolicyLocal,
+			expectedCheck:        linodego.CheckHTTP,
+			expectedCheckPath:    "/healthz",
+			expectedUDPCheckPort: 32000,
+		},
+		{
+			name:                 "udp cluster has no check",
+			protocol:             v1.ProtocolUDP,
+			trafficPolicy:        v1.ServiceExternalTrafficPolicyCluster,
+			expectedCheck:        linodego.CheckNone,
+			expectedUDPCheckPort: 80,
+		},
+		{
+			name:          "udp local respects annotations",
+			protocol:      v1.ProtocolUDP,
+			trafficPolicy: v1.ServiceExternalTrafficPolicyLocal,
+			annotations: map[string]string{
+				annotations.AnnLinodeHealthCheckType: "none",
+				annotations.AnnLinodeUDPCheckPort:    "8080",
+			},
+			expectedCheck:        linodego.CheckNone,
+			expectedUDPCheckPort: 8080,
+		},
+		{
+			name:          "udp local respects udp check port",
+			protocol:      v1.ProtocolUDP,
+			trafficPolicy: v1.ServiceExternalTrafficPolicyLocal,
+			annotations: map[string]string{
+				annotations.AnnLinodeUDPCheckPort: "8080",
+			},
+			expectedCheck:        linodego.CheckNone,
+			expectedUDPCheckPort: 8080,
+		},
+		{
+			name:          "udp local respects port udp check port",
+			protocol:      v1.ProtocolUDP,
+			trafficPolicy: v1.ServiceExternalTrafficPolicyLocal,
+			annotations: map[string]string{
+				annotations.AnnLinodePortConfigPrefix + "53": `{"udp-check-port": "8080"}`,
+			},
+			expectedCheck:        linodego.CheckNone,
+			expectedUDPCheckPort: 8080,
+		},
+		{
+			name:          "udp local with http check on udp check port",
+			protocol:      v1.ProtocolUDP,
+			trafficPolicy: v1.ServiceExternalTrafficPolicyLocal,
+			annotations: map[string]string{
+				annotations.AnnLinodeHealthCheckType: "http",
+				annotations.AnnLinodeUDPCheckPort:    "8080",
+			},
+			expectedCheck:        linodego.CheckHTTP,
+			expectedCheckPath:    "/",
+			expectedUDPCheckPort: 8080,
+		},
+		{
+			name:          "tcp local keeps connection check",
+			protocol:      v1.ProtocolTCP,
+			trafficPolicy: v1.ServiceExternalTrafficPolicyLocal,
+			expectedCheck: linodego.CheckConnection,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			port := v1.ServicePort{Protocol: tc.protocol, Port: 53, NodePort: 30053}
+			service := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tc.annotations},
+				Spec: v1.ServiceSpec{
+					Type:                  v1.ServiceTypeLoadBalancer,
+					ExternalTrafficPolicy: tc.trafficPolicy,
+					Ports:                 []v1.ServicePort{port},
+				},
+			}
+			if tc.trafficPolicy == v1.ServiceExternalTrafficPolicyLocal {
+				service.Spec.HealthCheckNodePort = 32000
+			}
+
+			config, _, err := newNodeBalancerConfig(service, port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Check != tc.expectedCheck {
+				t.Errorf("expected check %q, got %q", tc.expectedCheck, config.Check)
+			}
+			if config.CheckPath != tc.expectedCheckPath {
+				t.Errorf("expected check path %q, got %q", tc.expectedCheckPath, config.CheckPath)
+			}
+			if config.UDPCheckPort != tc.expectedUDPCheckPort {
+				t.Errorf("expected UDP check port %d, got %d", tc.expectedUDPCheckPort, config.UDPCheckPort)
+			}
+		})
+	}
+}
+
 func Test_getNodePrivateIP(t *testing.T) {
 	testcases := []struct {
 		name     string
@@ -4253,10 +4434,6 @@ func Test_getNodeBackendIP(t *testing.T) {
 }
 
 func Test_resolveIPv6NodeBalancerBackendState(t *testing.T) {
-	prev := options.Options.EnableIPv6ForNodeBalancerBackends
-	defer func() {
-		options.Options.EnableIPv6ForNodeBalancerBackends = prev
-	}()
 
 	testcases := []struct {
 		name            string
@@ -4296,10 +4473,11 @@ func Test_resolveIPv6NodeBalancerBackendState(t *testing.T) {
 		},
 	}
 
+	opts := &options.Config{}
 	for _, test := range testcases {
 		t.Run(test.name, func(t *testing.T) {
-			options.Options.EnableIPv6ForNodeBalancerBackends = test.globalFlag
-			useIPv6Backends := resolveIPv6NodeBalancerBackendState(test.service)
+			opts.EnableIPv6ForNodeBalancerBackends = test.globalFlag
+			useIPv6Backends := resolveIPv6NodeBalancerBackendState(opts, test.service)
 			if useIPv6Backends != test.expectedUseIPv6 {
 				t.Fatalf("expected useIPv6Backends=%t, got %t", test.expectedUseIPv6, useIPv6Backends)
 			}
@@ -4308,20 +4486,12 @@ func Test_resolveIPv6NodeBalancerBackendState(t *testing.T) {
 }
 
 func Test_buildLoadBalancerRequestOmitsVPCConfigForIPv6Backends(t *testing.T) {
-	prevVPCNames := options.Options.VPCNames
-	prevSubnetNames := options.Options.SubnetNames
-	prevDisableVPC := options.Options.DisableNodeBalancerVPCBackends
-	prevEnableIPv6Backends := options.Options.EnableIPv6ForNodeBalancerBackends
-	defer func() {
-		options.Options.VPCNames = prevVPCNames
-		options.Options.SubnetNames = prevSubnetNames
-		options.Options.DisableNodeBalancerVPCBackends = prevDisableVPC
-		options.Options.EnableIPv6ForNodeBalancerBackends = prevEnableIPv6Backends
-	}()
 
-	options.Options.VPCNames = []string{"test-vpc"}
-	options.Options.SubnetNames = []string{"default"}
-	options.Options.DisableNodeBalancerVPCBackends = false
+	opts := &options.Config{
+		VPCNames:                       []string{"test-vpc"},
+		SubnetNames:                    []string{"default"},
+		DisableNodeBalancerVPCBackends: false,
+	}
 
 	testcases := []struct {
 		name        string
@@ -4347,7 +4517,7 @@ func Test_buildLoadBalancerRequestOmitsVPCConfigForIPv6Backends(t *testing.T) {
 
 	for _, test := range testcases {
 		t.Run(test.name, func(t *testing.T) {
-			options.Options.EnableIPv6ForNodeBalancerBackends = test.globalFlag
+			opts.EnableIPv6ForNodeBalancerBackends = test.globalFlag
 
 			fake := newFake(t)
 			ts := httptest.NewServer(fake)
@@ -4358,7 +4528,7 @@ func Test_buildLoadBalancerRequestOmitsVPCConfigForIPv6Backends(t *testing.T) {
 				t.Fatal(err)
 			}
 			client.SetBaseURL(ts.URL)
-			lb, ok := newLoadbalancers(&client, "us-west").(*loadbalancers)
+			lb, ok := newLoadbalancers(&client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 			if !ok {
 				t.Fatal("type assertion failed")
 			}
@@ -4455,7 +4625,7 @@ func Test_buildLoadBalancerRequestOmitsVPCConfigForIPv6Backends(t *testing.T) {
 }
 
 func Test_buildNodeBalancerNodeConfigRebuildOptionsOmitsSubnetIDForIPv6Backends(t *testing.T) {
-	lb := &loadbalancers{}
+	lb := &loadbalancers{options: &options.Config{}}
 	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc-test", Namespace: "default"}}
 	node := &v1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4475,6 +4645,104 @@ func Test_buildNodeBalancerNodeConfigRebuildOptionsOmitsSubnetIDForIPv6Backends(
 	}
 }
 
+func Test_buildNodeBalancerNodeConfigRebuildOptionsWeightAndMode(t *testing.T) {
+	testcases := []struct {
+		name           string
+		node           *v1.Node
+		protocol       linodego.ConfigProtocol
+		expectedWeight int
+		expectedMode   linodego.NodeMode
+	}{
+		{
+			name:           "defaults",
+			node:           &v1.Node{},
+			protocol:       linodego.ProtocolTCP,
+			expectedWeight: 100,
+			expectedMode:   linodego.ModeAccept,
+		},
+		{
+			name: "weight annotation",
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{annotations.AnnLinodeNodeBalancerWeight: "20"},
+			}},
+			protocol:       linodego.ProtocolTCP,
+			expectedWeight: 20,
+			expectedMode:   linodego.ModeAccept,
+		},
+		{
+			name: "weight label",
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{annotations.AnnLinodeNodeBalancerWeight: "200"},
+			}},
+			protocol:       linodego.ProtocolTCP,
+			expectedWeight: 200,
+			expectedMode:   linodego.ModeAccept,
+		},
+		{
+			name: "annotation wins over label",
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{annotations.AnnLinodeNodeBalancerWeight: "30"},
+				Labels:      map[string]string{annotations.AnnLinodeNodeBalancerWeight: "200"},
+			}},
+			protocol:       linodego.ProtocolTCP,
+			expectedWeight: 30,
+			expectedMode:   linodego.ModeAccept,
+		},
+		{
+			name: "invalid weight",
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{annotations.AnnLinodeNodeBalancerWeight: "256"},
+			}},
+			protocol:       linodego.ProtocolTCP,
+			expectedWeight: 100,
+			expectedMode:   linodego.ModeAccept,
+		},
+		{
+			name:           "cordoned node",
+			node:           &v1.Node{Spec: v1.NodeSpec{Unschedulable: true}},
+			protocol:       linodego.ProtocolHTTP,
+			expectedWeight: 100,
+			expectedMode:   linodego.ModeDrain,
+		},
+		{
+			name: "drain annotation",
+			node: &v1.Node{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{annotations.AnnLinodeNodeBalancerDrain: "true"},
+			}},
+			protocol:       linodego.ProtocolTCP,
+			expectedWeight: 100,
+			expectedMode:   linodego.ModeDrain,
+		},
+		{
+			name:           "udp leaves mode unset",
+			node:           &v1.Node{Spec: v1.NodeSpec{Unschedulable: true}},
+			protocol:       linodego.ProtocolUDP,
+			expectedWeight: 100,
+			expectedMode:   "",
+		},
+	}
+
+	lb := &loadbalancers{options: &options.Config{}}
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc-test", Namespace: "default"}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.node.Name = "node-1"
+			tc.node.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}
+
+			opts, err := lb.buildNodeBalancerNodeConfigRebuildOptions(service, tc.node, 30000, 0, false, tc.protocol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.Weight != tc.expectedWeight {
+				t.Errorf("expected weight %d, got %d", tc.expectedWeight, opts.Weight)
+			}
+			if opts.Mode != tc.expectedMode {
+				t.Errorf("expected mode %q, got %q", tc.expectedMode, opts.Mode)
+			}
+		})
+	}
+}
+
 func Test_formatNodeBalancerBackendAddress(t *testing.T) {
 	if got := formatNodeBalancerBackendAddress("192.168.0.10", 30000); got != "192.168.0.10:30000" {
 		t.Fatalf("unexpected IPv4 backend address format: %s", got)
@@ -4554,7 +4822,8 @@ func testBuildLoadBalancerRequest(t *testing.T, client *linodego.Client, _ *fake
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -4606,7 +4875,8 @@ func testEnsureLoadBalancerPreserveAnnotation(t *testing.T, client *linodego.Cli
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -4646,7 +4916,7 @@ func testEnsureLoadBalancerPreserveAnnotation(t *testing.T, client *linodego.Cli
 				t.Fatal(err)
 			}
 
-			svc.Status.LoadBalancer = *makeLoadBalancerStatus(svc, nb)
+			svc.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svc, nb)
 			err = lb.EnsureLoadBalancerDeleted(t.Context(), "linodelb", svc)
 
 			didDelete := fake.didRequestOccur(http.MethodDelete, fmt.Sprintf("/nodebalancers/%d", nb.ID), "")
@@ -4659,6 +4929,10 @@ func testEnsureLoadBalancerPreserveAnnotation(t *testing.T, client *linodego.Cli
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
 			}
+
+			if !test.deleted && !slices.Contains(fake.nb[strconv.Itoa(nb.ID)].Tags, preserveNodeBalancerTag) {
+				t.Fatalf("preserved load balancer was not tagged %s", preserveNodeBalancerTag)
+			}
 		})
 	}
 }
@@ -4723,7 +4997,8 @@ func testEnsureLoadBalancerDeleted(t *testing.T, client *linodego.Client, fake *
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -4737,7 +5012,7 @@ func testEnsureLoadBalancerDeleted(t *testing.T, client *linodego.Client, fake *
 				if err != nil {
 					t.Fatal(err)
 				}
-				test.service.Status.LoadBalancer = *makeLoadBalancerStatus(test.service, nb)
+				test.service.Status.LoadBalancer = *makeLoadBalancerStatus(opts, test.service, nb)
 			}
 
 			err := lb.EnsureLoadBalancerDeleted(t.Context(), test.clusterName, test.service)
@@ -4771,7 +5046,7 @@ func testEnsureLoadBalancerDeleted(t *testing.T, client *linodego.Client, fake *
 		if err != nil {
 			t.Fatal(err)
 		}
-		service.Status.LoadBalancer = *makeLoadBalancerStatus(service, nb)
+		service.Status.LoadBalancer = *makeLoadBalancerStatus(opts, service, nb)
 
 		err = lb.EnsureLoadBalancerDeleted(t.Context(), "linodelb", service)
 		if err == nil {
@@ -4819,7 +5094,8 @@ func testEnsureExistingLoadBalancer(t *testing.T, client *linodego.Client, _ *fa
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -4832,7 +5108,7 @@ func testEnsureExistingLoadBalancer(t *testing.T, client *linodego.Client, _ *fa
 		t.Fatal(err)
 	}
 
-	svc.Status.LoadBalancer = *makeLoadBalancerStatus(svc, nb)
+	svc.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svc, nb)
 	defer func() { _ = lb.EnsureLoadBalancerDeleted(t.Context(), "linodelb", svc) }()
 	getLBStatus, exists, err := lb.GetLoadBalancer(t.Context(), "linodelb", svc)
 	if err != nil {
@@ -4946,14 +5222,15 @@ func testMakeLoadBalancerStatus(t *testing.T, client *linodego.Client, _ *fakeAP
 			IP:       ipv4,
 		}},
 	}
-	status := makeLoadBalancerStatus(svc, nb)
+	opts := &options.Config{}
+	status := makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status for basic service to be %#v; got %#v", expectedStatus, status)
 	}
 
 	svc.Annotations[annotations.AnnLinodeHostnameOnlyIngress] = "true"
 	expectedStatus.Ingress[0] = v1.LoadBalancerIngress{Hostname: hostname}
-	status = makeLoadBalancerStatus(svc, nb)
+	status = makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status for %q annotated service to be %#v; got %#v", annotations.AnnLinodeHostnameOnlyIngress, expectedStatus, status)
 	}
@@ -4979,20 +5256,22 @@ func testMakeLoadBalancerStatusWithIPv6(t *testing.T, client *linodego.Client, _
 	}
 
 	// Test with EnableIPv6ForLoadBalancers = false (default)
-	options.Options.EnableIPv6ForLoadBalancers = false
+	opts := &options.Config{
+		EnableIPv6ForLoadBalancers: false,
+	}
 	expectedStatus := &v1.LoadBalancerStatus{
 		Ingress: []v1.LoadBalancerIngress{{
 			Hostname: hostname,
 			IP:       ipv4,
 		}},
 	}
-	status := makeLoadBalancerStatus(svc, nb)
+	status := makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status with EnableIPv6ForLoadBalancers=false to be %#v; got %#v", expectedStatus, status)
 	}
 
 	// Test with EnableIPv6ForLoadBalancers = true
-	options.Options.EnableIPv6ForLoadBalancers = true
+	opts.EnableIPv6ForLoadBalancers = true
 	expectedStatus = &v1.LoadBalancerStatus{
 		Ingress: []v1.LoadBalancerIngress{
 			{
@@ -5005,25 +5284,25 @@ func testMakeLoadBalancerStatusWithIPv6(t *testing.T, client *linodego.Client, _
 			},
 		},
 	}
-	status = makeLoadBalancerStatus(svc, nb)
+	status = makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status with EnableIPv6ForLoadBalancers=true to be %#v; got %#v", expectedStatus, status)
 	}
 
 	// Test with per-service annotation
 	// Reset the global flag to false and set the annotation
-	options.Options.EnableIPv6ForLoadBalancers = false
+	opts.EnableIPv6ForLoadBalancers = false
 	svc.Annotations[annotations.AnnLinodeEnableIPv6Ingress] = "true"
 
 	// Expect the same result as when the global flag is enabled
-	status = makeLoadBalancerStatus(svc, nb)
+	status = makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status with %s=true annotation to be %#v; got %#v",
 			annotations.AnnLinodeEnableIPv6Ingress, expectedStatus, status)
 	}
 
 	// Set the global flag to true and set the annotation to false
-	options.Options.EnableIPv6ForLoadBalancers = true
+	opts.EnableIPv6ForLoadBalancers = true
 	svc.Annotations[annotations.AnnLinodeEnableIPv6Ingress] = "false"
 	expectedStatus = &v1.LoadBalancerStatus{
 		Ingress: []v1.LoadBalancerIngress{{
@@ -5033,14 +5312,14 @@ func testMakeLoadBalancerStatusWithIPv6(t *testing.T, client *linodego.Client, _
 	}
 
 	// Expect the annotation to take precedence over the global flag, resulting in only the IPv4 address being included
-	status = makeLoadBalancerStatus(svc, nb)
+	status = makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status with %s=false annotation to be %#v; got %#v",
 			annotations.AnnLinodeEnableIPv6Ingress, expectedStatus, status)
 	}
 
 	// Reset the flag to its default value
-	options.Options.EnableIPv6ForLoadBalancers = false
+	opts.EnableIPv6ForLoadBalancers = false
 }
 
 func testMakeLoadBalancerStatusEnvVar(t *testing.T, client *linodego.Client, _ *fakeAPI) {
@@ -5066,32 +5345,25 @@ func testMakeLoadBalancerStatusEnvVar(t *testing.T, client *linodego.Client, _ *
 			IP:       ipv4,
 		}},
 	}
-	status := makeLoadBalancerStatus(svc, nb)
+	opts := &options.Config{}
+	status := makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status for basic service to be %#v; got %#v", expectedStatus, status)
 	}
 
-	t.Setenv("LINODE_HOSTNAME_ONLY_INGRESS", "true")
+	opts.HostnameOnlyIngress = true
 	expectedStatus.Ingress[0] = v1.LoadBalancerIngress{Hostname: hostname}
-	status = makeLoadBalancerStatus(svc, nb)
+	status = makeLoadBalancerStatus(opts, svc, nb)
 	if !reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status for %q annotated service to be %#v; got %#v", annotations.AnnLinodeHostnameOnlyIngress, expectedStatus, status)
 	}
 
-	t.Setenv("LINODE_HOSTNAME_ONLY_INGRESS", "false")
-	expectedStatus.Ingress[0] = v1.LoadBalancerIngress{Hostname: hostname}
-	status = makeLoadBalancerStatus(svc, nb)
-	if reflect.DeepEqual(status, expectedStatus) {
-		t.Errorf("expected status for %q annotated service to be %#v; got %#v", annotations.AnnLinodeHostnameOnlyIngress, expectedStatus, status)
-	}
-
-	t.Setenv("LINODE_HOSTNAME_ONLY_INGRESS", "banana")
+	opts.HostnameOnlyIngress = false
 	expectedStatus.Ingress[0] = v1.LoadBalancerIngress{Hostname: hostname}
-	status = makeLoadBalancerStatus(svc, nb)
+	status = makeLoadBalancerStatus(opts, svc, nb)
 	if reflect.DeepEqual(status, expectedStatus) {
 		t.Errorf("expected status for %q annotated service to be %#v; got %#v", annotations.AnnLinodeHostnameOnlyIngress, expectedStatus, status)
 	}
-	os.Unsetenv("LINODE_HOSTNAME_ONLY_INGRESS")
 }
 
 func testCleanupDoesntCall(t *testing.T, client *linodego.Client, fakeAPI *fakeAPI) {
@@ -5114,9 +5386,10 @@ func testCleanupDoesntCall(t *testing.T, client *linodego.Client, fakeAPI *fakeA
 			Annotations: map[string]string{annotations.AnnLinodeNodeBalancerID: strconv.Itoa(nb2.ID)},
 		},
 	}
-	svc.Status.LoadBalancer = *makeLoadBalancerStatus(svc, nb1)
-	svcAnn.Status.LoadBalancer = *makeLoadBalancerStatus(svcAnn, nb1)
-	lb, assertion := newLoadbalancers(client, region).(*loadbalancers)
+	opts := &options.Config{}
+	svc.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svc, nb1)
+	svcAnn.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svcAnn, nb1)
+	lb, assertion := newLoadbalancers(client, region, opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -5168,7 +5441,8 @@ func testUpdateLoadBalancerNoNodes(t *testing.T, client *linodego.Client, _ *fak
 		},
 	}
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -5185,7 +5459,7 @@ func testUpdateLoadBalancerNoNodes(t *testing.T, client *linodego.Client, _ *fak
 	if err != nil {
 		t.Fatalf("failed to create NodeBalancer: %s", err)
 	}
-	svc.Status.LoadBalancer = *makeLoadBalancerStatus(svc, nodeBalancer)
+	svc.Status.LoadBalancer = *makeLoadBalancerStatus(opts, svc, nodeBalancer)
 	stubService(fakeClientset, svc)
 	svc.SetAnnotations(map[string]string{
 		annotations.AnnLinodeNodeBalancerID: strconv.Itoa(nodeBalancer.ID),
@@ -5206,7 +5480,8 @@ func testUpdateLoadBalancerNoNodes(t *testing.T, client *linodego.Client, _ *fak
 func testGetNodeBalancerByStatus(t *testing.T, client *linodego.Client, _ *fakeAPI) {
 	t.Helper()
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -5249,7 +5524,7 @@ func testGetNodeBalancerByStatus(t *testing.T, client *linodego.Client, _ *fakeA
 			if err != nil {
 				t.Fatal(err)
 			}
-			test.service.Status.LoadBalancer = *makeLoadBalancerStatus(test.service, expectedNB)
+			test.service.Status.LoadBalancer = *makeLoadBalancerStatus(opts, test.service, expectedNB)
 
 			stubService(fakeClientset, test.service)
 			actualNB, err := lb.getNodeBalancerByStatus(t.Context(), test.service)
@@ -5271,7 +5546,8 @@ func testGetNodeBalancerByStatus(t *testing.T, client *linodego.Client, _ *fakeA
 func testGetNodeBalancerForServiceIDDoesNotExist(t *testing.T, client *linodego.Client, _ *fakeAPI) {
 	t.Helper()
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -5315,7 +5591,8 @@ func testGetNodeBalancerForServiceIDDoesNotExist(t *testing.T, client *linodego.
 func testEnsureNewLoadBalancerWithNodeBalancerID(t *testing.T, client *linodego.Client, _ *fakeAPI) {
 	t.Helper()
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -5414,7 +5691,8 @@ func testEnsureNewLoadBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI
 			},
 		},
 	}
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -5432,7 +5710,8 @@ func testEnsureNewLoadBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI
 func testGetLoadBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI) {
 	t.Helper()
 
-	lb, assertion := newLoadbalancers(client, "us-west").(*loadbalancers)
+	opts := &options.Config{}
+	lb, assertion := newLoadbalancers(client, "us-west", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -5504,7 +5783,7 @@ func testGetLoadBalancer(t *testing.T, client *linodego.Client, _ *fakeAPI) {
 
 	defer func() { _ = lb.EnsureLoadBalancerDeleted(t.Context(), "linodelb", svc) }()
 
-	lbStatus := makeLoadBalancerStatus(svc, nb)
+	lbStatus := makeLoadBalancerStatus(opts, svc, nb)
 	if lbStatus != nil {
 		svc.Status.LoadBalancer = *lbStatus
 	}
@@ -5853,8 +6132,8 @@ func Test_loadbalancers_GetLinodeNBType(t *testing.T) {
 				client:     tt.fields.client,
 				zone:       tt.fields.zone,
 				kubeClient: tt.fields.kubeClient,
+				options:    &options.Config{Reloadable: options.Reloadable{DefaultNBType: string(tt.defaultNB)}},
 			}
-			options.Options.DefaultNBType = string(tt.defaultNB)
 			if got := l.GetLinodeNBType(tt.args.service); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("loadbalancers.GetLinodeNBType() = %v, want %v", got, tt.want)
 			}
@@ -5883,15 +6162,13 @@ func Test_validateNodeBalancerBackendIPv4Range(t *testing.T) {
 		},
 	}
 
-	nbBackendSubnet := options.Options.NodeBalancerBackendIPv4Subnet
-	defer func() {
-		options.Options.NodeBalancerBackendIPv4Subnet = nbBackendSubnet
-	}()
-	options.Options.NodeBalancerBackendIPv4Subnet = "10.100.0.0/24"
+	opts := &options.Config{
+		NodeBalancerBackendIPv4Subnet: "10.100.0.0/24",
+	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := validateNodeBalancerBackendIPv4Range(tt.args.backendIPv4Range); (err != nil) != tt.wantErr {
+			if err := validateNodeBalancerBackendIPv4Range(opts, tt.args.backendIPv4Range); (err != nil) != tt.wantErr {
 				t.Errorf("validateNodeBalancerBackendIPv4Range() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -6027,9 +6304,10 @@ func Test_makeLoadBalancerStatus_FrontendVPC(t *testing.T) {
 		},
 	}
 
+	opts := &options.Config{}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := makeLoadBalancerStatus(tt.args.service, tt.args.nb)
+			got := makeLoadBalancerStatus(opts, tt.args.service, tt.args.nb)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("makeLoadBalancerStatus() = %v, want %v", got, tt.want)
 			}
@@ -6228,8 +6506,11 @@ func Test_getFrontendVPCCreateOptions(t *testing.T) {
 				tt.prepareMock(mockClient)
 			}
 
+			opts := &options.Config{}
 			l := &loadbalancers{
-				client: mockClient,
+				client:  mockClient,
+				options: opts,
+				vpcs:    services.NewVPCCache(opts),
 			}
 			got, err := l.getFrontendVPCCreateOptions(context.Background(), tt.args.service)
 			if (err != nil) != tt.wantErr {
@@ -6242,3 +6523,69 @@ func Test_getFrontendVPCCreateOptions(t *testing.T) {
 		})
 	}
 }
+
+func Test_serviceLocks(t *testing.T) {
+	var locks serviceLocks
+	web := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	api := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}}
+
+	unlock := locks.lock(web)
+
+	// Other services are not blocked
+	locks.lock(api)()
+
+	acquired := make(chan struct{})
+	go func() {
+		defer locks.lock(web)()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lock of the same service acquired twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-acquired
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		locks.mu.Lock()
+		held := len(locks.locks)
+		locks.mu.Unlock()
+		if held == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected released locks to be dropped, %d are left", held)
+		}
+	}
+}
+
+func Test_createServiceEvent(t *testing.T) {
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid"}}
+
+	// Without a kube client and outside of a cluster the event is dropped instead of panicking
+	lb := &loadbalancers{options: &options.Config{}}
+	lb.createServiceEvent(t.Context(), svc, "test-event", "Normal", "TestReason", "message")
+
+	kubeClient := fake.NewClientset()
+	lb = &loadbalancers{kubeClient: kubeClient, options: &options.Config{}}
+	lb.createServiceEvent(t.Context(), svc, "test-event", "Warning", "TestReason", "something happened")
+
+	events, err := kubeClient.CoreV1().Events("default").List(t.Context(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %s", err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events.Items))
+	}
+	event := events.Items[0]
+	if !strings.HasPrefix(event.Name, "test-event-") {
+		t.Errorf("expected event name to start with test-event-, got %s", event.Name)
+	}
+	if event.InvolvedObject.Name != "web" || event.InvolvedObject.UID != "web-uid" {
+		t.Errorf("expected event to involve service web, got %+v", event.InvolvedObject)
+	}
+	if event.Type != "Warning" || event.Reason != "TestReason" || event.Message != "something happened" {
+		t.Errorf("unexpected event %s/%s: %s", event.Type, event.Reason, event.Message)
+	}
+}
@@ -3,15 +3,127 @@ package linode
 import (
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/component-base/metrics/legacyregistry"
 
 	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
 )
 
+const (
+	configUpdateResultSkipped = "skipped"
+	configUpdateResultApplied = "applied"
+
+	configReloadResultApplied  = "applied"
+	configReloadResultRejected = "rejected"
+	configReloadResultFailed   = "failed"
+)
+
 var registerOnce sync.Once
 
+// nodeBalancerConfigUpdatesCounterVec counts per-port NodeBalancer config reconciliations,
+// labeled by whether the config was rebuilt or left alone because it was already up to date.
+var nodeBalancerConfigUpdatesCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ccm_linode_nodebalancer_config_updates_total",
+		Help: "NodeBalancer config reconciliations, by whether an update was applied or skipped",
+	},
+	[]string{"result"})
+
+// nodeBalancerCertificateExpiryGaugeVec exports when the TLS certificate deployed on each HTTPS
+// NodeBalancer port expires.
+var nodeBalancerCertificateExpiryGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_nodebalancer_certificate_expiry_timestamp_seconds",
+		Help: "Expiry of the TLS certificate deployed on a NodeBalancer port, in seconds since the epoch",
+	},
+	[]string{"namespace", "service", "port"})
+
+// orphanedNodeBalancersGauge exports how many NodeBalancers of the cluster the garbage collector
+// found without a Service in its last run.
+var orphanedNodeBalancersGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_orphaned_nodebalancers",
+		Help: "NodeBalancers of the cluster not used by any service, as of the last garbage collection",
+	})
+
+// orphanedResourcesDeletedCounterVec counts the orphaned NodeBalancers and firewalls deleted by
+// the garbage collector.
+var orphanedResourcesDeletedCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ccm_linode_orphaned_resources_deleted_total",
+		Help: "Orphaned NodeBalancers and firewalls deleted by the garbage collector, by resource",
+	},
+	[]string{"resource"})
+
+// nodeBalancerBackendsGaugeVec exports how many backends of each NodeBalancer port pass or fail
+// their health checks, as polled by the NodeBalancer health controller or, without it, by the
+// NodeBalancer statistics collector.
+var nodeBalancerBackendsGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_nodebalancer_backends",
+		Help: "Backends of a NodeBalancer port, by whether they pass their health checks",
+	},
+	[]string{"namespace", "service", "port", "status"})
+
+// nodeBalancerConnectionsGaugeVec exports the connection rate of the NodeBalancer of each
+// service, as of the latest NodeBalancer statistics.
+var nodeBalancerConnectionsGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_nodebalancer_connections_per_second",
+		Help: "Connections per second to the NodeBalancer of a service, as of its latest statistics",
+	},
+	[]string{"namespace", "service"})
+
+// nodeBalancerTrafficGaugeVec exports the inbound and outbound traffic of the NodeBalancer of
+// each service, as of the latest NodeBalancer statistics.
+var nodeBalancerTrafficGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_nodebalancer_traffic_bits_per_second",
+		Help: "Traffic of the NodeBalancer of a service in bits per second, by direction, as of its latest statistics",
+	},
+	[]string{"namespace", "service", "direction"})
+
+// nodeBalancerStatsSkippedServicesGauge exports how many services the NodeBalancer statistics
+// collector left out in its last collection because of its service limit.
+var nodeBalancerStatsSkippedServicesGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_nodebalancer_stats_skipped_services",
+		Help: "Services whose NodeBalancer statistics were not collected because of the service limit, as of the last collection",
+	})
+
+// configGenerationGauge exports the generation of the configuration in effect, which starts at 1
+// and grows with every change of the configuration file that is applied without a restart.
+var configGenerationGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "ccm_linode_config_generation",
+		Help: "Generation of the configuration in effect, incremented whenever a change of the configuration file is applied",
+	})
+
+// configReloadsCounterVec counts the changes of the configuration file, by whether they were
+// applied, rejected because they change settings that require a restart, or failed to load.
+var configReloadsCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ccm_linode_config_reloads_total",
+		Help: "Changes of the configuration file, by whether they were applied, rejected or failed to load",
+	},
+	[]string{"result"})
+
 func registerMetrics() {
 	registerOnce.Do(func() {
 		legacyregistry.RawMustRegister(client.ClientMethodCounterVec)
+		legacyregistry.RawMustRegister(client.ClientMethodDurationHistogramVec)
+		legacyregistry.RawMustRegister(client.ClientMethodRateLimitedCounterVec)
+		legacyregistry.RawMustRegister(client.ClientMethodInFlightGaugeVec)
+		legacyregistry.RawMustRegister(client.ClientCacheRequestsCounterVec)
+		legacyregistry.RawMustRegister(nodeBalancerConfigUpdatesCounterVec)
+		legacyregistry.RawMustRegister(nodeBalancerCertificateExpiryGaugeVec)
+		legacyregistry.RawMustRegister(orphanedNodeBalancersGauge)
+		legacyregistry.RawMustRegister(orphanedResourcesDeletedCounterVec)
+		legacyregistry.RawMustRegister(nodeBalancerBackendsGaugeVec)
+		legacyregistry.RawMustRegister(nodeBalancerConnectionsGaugeVec)
+		legacyregistry.RawMustRegister(nodeBalancerTrafficGaugeVec)
+		legacyregistry.RawMustRegister(nodeBalancerStatsSkippedServicesGauge)
+		legacyregistry.RawMustRegister(configGenerationGauge)
+		legacyregistry.RawMustRegister(configReloadsCounterVec)
 	})
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"net/http"
-	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -34,8 +33,6 @@ const (
 	listNodeContextTimeout = 30 * time.Second
 )
 
-var registeredK8sNodeCache *k8sNodeCache = newK8sNodeCache()
-
 type nodeRequest struct {
 	node      *v1.Node
 	timestamp time.Time
@@ -48,9 +45,11 @@ type nodeController struct {
 	instances  *services.Instances
 	kubeclient kubernetes.Interface
 	informer   v1informers.NodeInformer
+	options    *options.Config
+	// k8sNodes is shared with the route controller, which looks up nodes by name
+	k8sNodes *k8sNodeCache
 
 	metadataLastUpdate map[string]time.Time
-	ttl                time.Duration
 
 	queue         workqueue.TypedDelayingInterface[nodeRequest]
 	nodeLastAdded map[string]time.Time
@@ -62,14 +61,23 @@ type k8sNodeCache struct {
 	nodes       map[string]*v1.Node
 	providerIDs map[string]string
 	lastUpdate  time.Time
-	ttl         time.Duration
+	options     *options.Config
+}
+
+// ttl returns how long the cached nodes are used, which can change when the configuration is
+// reloaded
+func (c *k8sNodeCache) ttl() time.Duration {
+	if ttl := c.options.Current().K8sNodeCacheTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultK8sNodeCacheTTL
 }
 
 // updateCache updates the k8s node cache with the latest nodes from the k8s API server.
 func (c *k8sNodeCache) updateCache(kubeclient kubernetes.Interface) {
 	c.Lock()
 	defer c.Unlock()
-	if time.Since(c.lastUpdate) < c.ttl {
+	if time.Since(c.lastUpdate) < c.ttl() {
 		return
 	}
 
@@ -142,35 +150,22 @@ func (c *k8sNodeCache) getProviderID(nodeName string) (string, bool) {
 }
 
 // newK8sNodeCache returns new k8s node cache instance
-func newK8sNodeCache() *k8sNodeCache {
-	timeout := defaultK8sNodeCacheTTL
-	if raw, ok := os.LookupEnv("K8S_NODECACHE_TTL"); ok {
-		if t, err := strconv.Atoi(raw); t > 0 && err == nil {
-			timeout = time.Duration(t) * time.Second
-		}
-	}
-
+func newK8sNodeCache(opts *options.Config) *k8sNodeCache {
 	return &k8sNodeCache{
 		nodes:       make(map[string]*v1.Node, 0),
 		providerIDs: make(map[string]string, 0),
-		ttl:         timeout,
+		options:     opts,
 	}
 }
 
-func newNodeController(kubeclient kubernetes.Interface, client client.Client, informer v1informers.NodeInformer, instanceCache *services.Instances) *nodeController {
-	timeout := defaultMetadataTTL
-	if raw, ok := os.LookupEnv("LINODE_METADATA_TTL"); ok {
-		if t, err := strconv.Atoi(raw); t > 0 && err == nil {
-			timeout = time.Duration(t) * time.Second
-		}
-	}
-
+func newNodeController(kubeclient kubernetes.Interface, client client.Client, informer v1informers.NodeInformer, instanceCache *services.Instances, opts *options.Config, k8sNodes *k8sNodeCache) *nodeController {
 	return &nodeController{
 		client:             client,
 		instances:          instanceCache,
 		kubeclient:         kubeclient,
 		informer:           informer,
-		ttl:                timeout,
+		options:            opts,
+		k8sNodes:           k8sNodes,
 		metadataLastUpdate: make(map[string]time.Time),
 		queue:              workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[nodeRequest]{Name: "ccm_node"}),
 		nodeLastAdded:      make(map[string]time.Time),
@@ -250,7 +245,7 @@ func (s *nodeController) processNext() bool {
 		}
 	}
 
-	registeredK8sNodeCache.updateCache(s.kubeclient)
+	s.k8sNodes.updateCache(s.kubeclient)
 	return true
 }
 
@@ -266,6 +261,15 @@ func (s *nodeController) SetLastMetadataUpdate(nodeName string) {
 	s.metadataLastUpdate[nodeName] = time.Now()
 }
 
+// ttl returns how long the metadata of a node is not refreshed, which can change when the
+// configuration is reloaded
+func (s *nodeController) ttl() time.Duration {
+	if ttl := s.options.Current().MetadataTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultMetadataTTL
+}
+
 func (s *nodeController) handleNode(ctx context.Context, node *v1.Node) error {
 	klog.V(3).InfoS("NodeController handling node metadata",
 		"node", klog.KObj(node))
@@ -277,10 +281,11 @@ func (s *nodeController) handleNode(ctx context.Context, node *v1.Node) error {
 	configuredPublicIPv6, foundIPv6Annotation := node.Annotations[annotations.AnnLinodeNodePublicIPv6]
 
 	metaAge := time.Since(lastUpdate)
-	if foundLabel && foundPrivateIPAnnotation && foundIPv6Annotation && metaAge < s.ttl {
+	ttl := s.ttl()
+	if foundLabel && foundPrivateIPAnnotation && foundIPv6Annotation && metaAge < ttl {
 		klog.V(3).InfoS("Skipping refresh, ttl not reached",
 			"node", klog.KObj(node),
-			"ttl", s.ttl,
+			"ttl", ttl,
 			"metadata_age", metaAge,
 		)
 		return nil
@@ -296,7 +301,7 @@ func (s *nodeController) handleNode(ctx context.Context, node *v1.Node) error {
 	// linode API response for linode will contain only one private ip
 	// if any private ip is configured.
 	for _, addr := range linode.IPv4 {
-		if ccmUtils.IsPrivate(addr, options.Options.LinodeExternalNetwork) {
+		if ccmUtils.IsPrivate(addr, s.options.LinodeExternalNetwork) {
 			expectedPrivateIP = addr.String()
 			break
 		}
@@ -341,7 +346,7 @@ func (s *nodeController) handleNode(ctx context.Context, node *v1.Node) error {
 	}
 
 	if updatedNode != nil {
-		registeredK8sNodeCache.addNodeToCache(updatedNode)
+		s.k8sNodes.addNodeToCache(updatedNode)
 	}
 	s.SetLastMetadataUpdate(node.Name)
 
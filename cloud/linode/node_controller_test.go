@@ -20,6 +20,7 @@ import (
 	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
 	linodeClient "github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
 	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client/mocks"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
 	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
 )
 
@@ -32,9 +33,10 @@ func TestNodeController_Run(t *testing.T) {
 	informer := informers.NewSharedInformerFactory(kubeClient, 0).Core().V1().Nodes()
 	mockQueue := workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[nodeRequest]{Name: "test"})
 
-	nodeCtrl := newNodeController(kubeClient, client, informer, services.NewInstances(client))
+	opts := &options.Config{}
+	nodeCtrl := newNodeController(kubeClient, client, informer, services.NewInstances(client, opts, services.NewVPCCache(opts)), opts, newK8sNodeCache(opts))
 	nodeCtrl.queue = mockQueue
-	nodeCtrl.ttl = 1 * time.Second
+	opts.MetadataTTL = 1 * time.Second
 
 	// Add test node
 	node := &v1.Node{
@@ -83,12 +85,15 @@ func TestNodeController_processNext(t *testing.T) {
 	_, err := kubeClient.CoreV1().Nodes().Create(t.Context(), node, metav1.CreateOptions{})
 	require.NoError(t, err, "expected no error during node creation")
 
+	opts := &options.Config{}
+	vpcs := services.NewVPCCache(opts)
 	controller := &nodeController{
 		kubeclient:         kubeClient,
-		instances:          services.NewInstances(client),
+		instances:          services.NewInstances(client, opts, vpcs),
+		options:            opts,
+		k8sNodes:           newK8sNodeCache(opts),
 		queue:              queue,
 		metadataLastUpdate: make(map[string]time.Time),
-		ttl:                defaultMetadataTTL,
 		nodeLastAdded:      make(map[string]time.Time),
 	}
 
@@ -104,7 +109,7 @@ func TestNodeController_processNext(t *testing.T) {
 
 	t.Run("should return no error if timestamp for node being processed is older than the most recent request", func(t *testing.T) {
 		controller.addNodeToQueue(node)
-		controller.nodeLastAdded["test"] = time.Now().Add(controller.ttl)
+		controller.nodeLastAdded["test"] = time.Now().Add(controller.ttl())
 		result := controller.processNext()
 		assert.True(t, result, "processNext should return true")
 		if queue.Len() != 0 {
@@ -139,8 +144,8 @@ func TestNodeController_processNext(t *testing.T) {
 		defer func() {
 			controller.instances = currInstances
 		}()
-		controller.instances = services.NewInstances(client)
-		registeredK8sNodeCache.lastUpdate = time.Now().Add(-15 * time.Minute)
+		controller.instances = services.NewInstances(client, opts, vpcs)
+		controller.k8sNodes.lastUpdate = time.Now().Add(-15 * time.Minute)
 		controller.addNodeToQueue(node2)
 		publicIP := net.ParseIP("172.234.31.123")
 		privateIP := net.ParseIP("192.168.159.135")
@@ -170,7 +175,7 @@ func TestNodeController_processNext(t *testing.T) {
 		controller.queue = queue
 		controller.addNodeToQueue(node)
 		client := mocks.NewMockClient(ctrl)
-		controller.instances = services.NewInstances(client)
+		controller.instances = services.NewInstances(client, opts, vpcs)
 		retryInterval = 1 * time.Nanosecond
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, &linodego.Error{Code: http.StatusTooManyRequests, Message: "Too many requests"})
 		result := controller.processNext()
@@ -186,7 +191,7 @@ func TestNodeController_processNext(t *testing.T) {
 		controller.queue = queue
 		controller.addNodeToQueue(node)
 		client := mocks.NewMockClient(ctrl)
-		controller.instances = services.NewInstances(client)
+		controller.instances = services.NewInstances(client, opts, vpcs)
 		retryInterval = 1 * time.Nanosecond
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, &linodego.Error{Code: http.StatusInternalServerError, Message: "Too many requests"})
 		result := controller.processNext()
@@ -215,15 +220,14 @@ func TestNodeController_handleNode(t *testing.T) {
 	_, err := kubeClient.CoreV1().Nodes().Create(t.Context(), node, metav1.CreateOptions{})
 	require.NoError(t, err, "expected no error during node creation")
 
-	instCache := services.NewInstances(client)
+	opts := &options.Config{Reloadable: options.Reloadable{MetadataTTL: 30 * time.Second, K8sNodeCacheTTL: 60 * time.Second}}
+	vpcs := services.NewVPCCache(opts)
+	instCache := services.NewInstances(client, opts, vpcs)
 
-	t.Setenv("LINODE_METADATA_TTL", "30")
-	nodeCtrl := newNodeController(kubeClient, client, nil, instCache)
-	assert.Equal(t, 30*time.Second, nodeCtrl.ttl, "expected ttl to be 30 seconds")
-
-	t.Setenv("K8S_NODECACHE_TTL", "60")
-	currK8sNodeCache := newK8sNodeCache()
-	assert.Equal(t, 60*time.Second, currK8sNodeCache.ttl, "expected ttl to be 60 seconds")
+	currK8sNodeCache := newK8sNodeCache(opts)
+	nodeCtrl := newNodeController(kubeClient, client, nil, instCache, opts, currK8sNodeCache)
+	assert.Equal(t, 30*time.Second, nodeCtrl.ttl(), "expected ttl to be 30 seconds")
+	assert.Equal(t, 60*time.Second, currK8sNodeCache.ttl(), "expected ttl to be 60 seconds")
 
 	// Test: Successful metadata update
 	publicIP := net.ParseIP("172.234.31.123")
@@ -250,16 +254,16 @@ func TestNodeController_handleNode(t *testing.T) {
 
 	// Lookup failure for linode instance
 	client = mocks.NewMockClient(ctrl)
-	nodeCtrl.instances = services.NewInstances(client)
-	nodeCtrl.metadataLastUpdate["test-node"] = time.Now().Add(-2 * nodeCtrl.ttl)
+	nodeCtrl.instances = services.NewInstances(client, opts, vpcs)
+	nodeCtrl.metadataLastUpdate["test-node"] = time.Now().Add(-2 * nodeCtrl.ttl())
 	client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, errors.New("lookup failed"))
 	err = nodeCtrl.handleNode(t.Context(), node)
 	require.Error(t, err, "expected error during handleNode, got nil")
 
 	// All fields already set
 	client = mocks.NewMockClient(ctrl)
-	nodeCtrl.instances = services.NewInstances(client)
-	nodeCtrl.metadataLastUpdate["test-node"] = time.Now().Add(-2 * nodeCtrl.ttl)
+	nodeCtrl.instances = services.NewInstances(client, opts, vpcs)
+	nodeCtrl.metadataLastUpdate["test-node"] = time.Now().Add(-2 * nodeCtrl.ttl())
 	client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{
 		{ID: 123, Label: "test-node", IPv4: []net.IP{publicIP, privateIP}, IPv6: publicIPv6SLAAC, HostUUID: "123"},
 	}, nil)
@@ -281,7 +285,7 @@ func Test_k8sNodeCache_addNodeToCache(t *testing.T) {
 		Spec: v1.NodeSpec{ProviderID: "linode://123"},
 	}
 
-	currK8sNodeCache := newK8sNodeCache()
+	currK8sNodeCache := newK8sNodeCache(&options.Config{})
 	currK8sNodeCache.addNodeToCache(node)
 
 	if _, exists := currK8sNodeCache.nodes[node.Name]; !exists {
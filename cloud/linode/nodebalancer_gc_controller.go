@@ -0,0 +1,244 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/appscode/go/wait"
+	"github.com/linode/linodego/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	v1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
+)
+
+// preserveNodeBalancerTag marks a NodeBalancer that must never be garbage collected. It is added
+// when the Service of a NodeBalancer annotated with AnnLinodeLoadBalancerPreserve is deleted, and
+// can be added by hand to keep any other NodeBalancer.
+const preserveNodeBalancerTag = "ccm-preserve"
+
+// defaultClusterName is the default of --cluster-name. Clusters that didn't set their own name
+// can't tell their NodeBalancers apart from those of other such clusters in the same account.
+const defaultClusterName = "kubernetes"
+
+// nodeBalancerGCController periodically deletes NodeBalancers, and the firewalls the CCM created
+// for them, that were created for this cluster but no longer belong to any Service. NodeBalancers
+// are only deleted once they have been orphaned for the grace period.
+type nodeBalancerGCController struct {
+	client   client.Client
+	options  *options.Config
+	informer v1informers.ServiceInformer
+
+	interval    time.Duration
+	gracePeriod time.Duration
+	reportOnly  bool
+
+	// orphanedSince records when each NodeBalancer was first found orphaned
+	orphanedSince map[int]time.Time
+	now           func() time.Time
+}
+
+func newNodeBalancerGCController(client client.Client, opts *options.Config, informer v1informers.ServiceInformer) *nodeBalancerGCController {
+	return &nodeBalancerGCController{
+		client:        client,
+		options:       opts,
+		informer:      informer,
+		interval:      opts.NodeBalancerGCInterval,
+		gracePeriod:   opts.NodeBalancerGCGracePeriod,
+		reportOnly:    opts.NodeBalancerGCReportOnly,
+		orphanedSince: make(map[int]time.Time),
+		now:           time.Now,
+	}
+}
+
+func (c *nodeBalancerGCController) Run(stopCh <-chan struct{}) {
+	if c.options.ClusterName == "" {
+		klog.Error("NodeBalancerGCController requires a cluster name to identify the NodeBalancers of this cluster, not starting")
+		return
+	}
+	if c.options.ClusterName == defaultClusterName && !c.reportOnly {
+		klog.Warningf("NodeBalancerGCController only reports orphaned NodeBalancers while --cluster-name is the default %q, set a name unique to this cluster to delete them", defaultClusterName)
+		c.reportOnly = true
+	}
+	if !cache.WaitForCacheSync(stopCh, c.informer.Informer().HasSynced) {
+		klog.Error("NodeBalancerGCController failed to sync the service informer")
+		return
+	}
+
+	wait.Until(func() {
+		if err := c.collect(context.Background()); err != nil {
+			klog.Errorf("NodeBalancerGCController failed to collect orphaned NodeBalancers: %s", err)
+		}
+	}, c.interval, stopCh)
+}
+
+// collect finds the orphaned NodeBalancers of the cluster and deletes those that have been
+// orphaned for longer than the grace period, unless running in report-only mode.
+func (c *nodeBalancerGCController) collect(ctx context.Context) error {
+	nodeBalancers, err := c.client.ListNodeBalancers(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list NodeBalancers: %w", err)
+	}
+	serviceList, err := c.informer.Lister().List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	owned := newNodeBalancerOwnership(serviceList)
+	now := c.now()
+	orphans := sets.New[int]()
+	for i := range nodeBalancers {
+		nb := &nodeBalancers[i]
+		if !isClusterNodeBalancer(c.options, nb) || owned.owns(nb) {
+			continue
+		}
+		if slices.Contains(nb.Tags, preserveNodeBalancerTag) {
+			klog.V(3).Infof("NodeBalancer (%d) is not used by any service but is tagged %s, keeping it", nb.ID, preserveNodeBalancerTag)
+			continue
+		}
+
+		orphans.Insert(nb.ID)
+		since, seen := c.orphanedSince[nb.ID]
+		if !seen {
+			since = now
+			c.orphanedSince[nb.ID] = now
+			klog.Infof("NodeBalancer (%d) is not used by any service", nb.ID)
+		}
+
+		if c.reportOnly {
+			klog.Infof("NodeBalancer (%d) has been orphaned since %s, not deleting it in report-only mode", nb.ID, since.Format(time.RFC3339))
+			continue
+		}
+		if now.Sub(since) < c.gracePeriod {
+			continue
+		}
+		if err := c.deleteOrphan(ctx, nb); err != nil {
+			klog.Errorf("failed to delete orphaned NodeBalancer (%d): %s", nb.ID, err)
+			continue
+		}
+		orphans.Delete(nb.ID)
+	}
+
+	for id := range c.orphanedSince {
+		if !orphans.Has(id) {
+			delete(c.orphanedSince, id)
+		}
+	}
+	orphanedNodeBalancersGauge.Set(float64(orphans.Len()))
+	return nil
+}
+
+// deleteOrphan deletes nb along with the firewalls the CCM created for it.
+func (c *nodeBalancerGCController) deleteOrphan(ctx context.Context, nb *linodego.NodeBalancer) error {
+	firewalls, err := c.client.ListNodeBalancerFirewalls(ctx, nb.ID, &linodego.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list firewalls: %w", err)
+	}
+
+	fwClient := services.LinodeClient{Client: c.client}
+	for i := range firewalls {
+		firewall := &firewalls[i]
+		// Firewalls created by the CCM are labeled and tagged like its NodeBalancers, firewalls
+		// referenced by ID belong to the user
+		if !strings.HasPrefix(firewall.Label, c.options.NodeBalancerPrefix+"-") || !hasClusterOwnershipTag(c.options, firewall.Tags) {
+			continue
+		}
+		if err := fwClient.DeleteFirewall(ctx, firewall); err != nil {
+			return fmt.Errorf("failed to delete firewall (%d): %w", firewall.ID, err)
+		}
+		klog.Infof("deleted firewall (%d) of orphaned NodeBalancer (%d)", firewall.ID, nb.ID)
+		orphanedResourcesDeletedCounterVec.WithLabelValues("firewall").Inc()
+	}
+
+	if err := c.client.DeleteNodeBalancer(ctx, nb.ID); err != nil {
+		return err
+	}
+	klog.Infof("deleted orphaned NodeBalancer (%d)", nb.ID)
+	orphanedResourcesDeletedCounterVec.WithLabelValues("nodebalancer").Inc()
+	delete(c.orphanedSince, nb.ID)
+	return nil
+}
+
+// isClusterNodeBalancer reports whether nb was created by the CCM of this cluster, judging by its
+// cluster ownership tag and label prefix. The plain cluster name tag isn't enough, since users
+// can add it to any NodeBalancer.
+func isClusterNodeBalancer(opts *options.Config, nb *linodego.NodeBalancer) bool {
+	return nb.Label != nil &&
+		strings.HasPrefix(*nb.Label, opts.NodeBalancerPrefix+"-") &&
+		hasClusterOwnershipTag(opts, nb.Tags)
+}
+
+// hasClusterOwnershipTag reports whether tags hold the cluster ownership tag of this cluster.
+func hasClusterOwnershipTag(opts *options.Config, tags []string) bool {
+	return opts.ClusterName != "" && slices.Contains(tags, ownerTag(ownerClusterTagPrefix, opts.ClusterName))
+}
+
+// nodeBalancerOwnership indexes the NodeBalancers referenced by Services, either through the
+// NodeBalancer ID annotation, through the addresses in their load balancer status or through
+// the ownership tags naming them or their UID.
+type nodeBalancerOwnership struct {
+	ids       sets.Set[int]
+	addresses sets.Set[string]
+	services  sets.Set[string]
+	uids      sets.Set[string]
+}
+
+// ownerKey identifies a Service by its namespace and name ownership tags.
+func ownerKey(namespaceTag, serviceTag string) string {
+	return namespaceTag + " " + serviceTag
+}
+
+// newNodeBalancerOwnership indexes the NodeBalancers referenced by serviceList. Only Services
+// this CCM reconciles count, so a NodeBalancer left behind by a Service that changed its type
+// or load balancer class is collected like any other orphan.
+func newNodeBalancerOwnership(serviceList []*v1.Service) nodeBalancerOwnership {
+	owned := nodeBalancerOwnership{ids: sets.New[int](), addresses: sets.New[string](), services: sets.New[string](), uids: sets.New[string]()}
+	for _, service := range serviceList {
+		if service.Spec.Type != v1.ServiceTypeLoadBalancer || service.Spec.LoadBalancerClass != nil {
+			continue
+		}
+		owned.services.Insert(ownerKey(ownerTag(ownerNamespaceTagPrefix, service.Namespace), ownerTag(ownerServiceTagPrefix, service.Name)))
+		if service.UID != "" {
+			owned.uids.Insert(ownerTag(ownerUIDTagPrefix, string(service.UID)))
+		}
+		if id, err := strconv.Atoi(service.GetAnnotations()[annotations.AnnLinodeNodeBalancerID]); err == nil && id != 0 {
+			owned.ids.Insert(id)
+		}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				owned.addresses.Insert(ingress.IP)
+			}
+			if ingress.Hostname != "" {
+				owned.addresses.Insert(ingress.Hostname)
+			}
+		}
+	}
+	return owned
+}
+
+func (o nodeBalancerOwnership) owns(nb *linodego.NodeBalancer) bool {
+	if o.ids.Has(nb.ID) {
+		return true
+	}
+	for _, address := range []*string{nb.IPv4, nb.IPv6, nb.Hostname} {
+		if address != nil && o.addresses.Has(*address) {
+			return true
+		}
+	}
+	owner := ownerTagsByPrefix(nb.Tags)
+	if owner[ownerUIDTagPrefix] != "" && o.uids.Has(owner[ownerUIDTagPrefix]) {
+		return true
+	}
+	return owner[ownerServiceTagPrefix] != "" && o.services.Has(ownerKey(owner[ownerNamespaceTagPrefix], owner[ownerServiceTagPrefix]))
+}
@@ -0,0 +1,180 @@
+package linode
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_nodeBalancerGCController(t *testing.T) {
+	opts := &options.Config{ClusterName: "gc-cluster", NodeBalancerPrefix: "ccm"}
+
+	newFakeWithNodeBalancers := func(t *testing.T) *fakeAPI {
+		t.Helper()
+
+		fakeLinode := newFake(t)
+		addNodeBalancer := func(id int, label, ip string, tags ...string) {
+			fakeLinode.nb[strconv.Itoa(id)] = &linodego.NodeBalancer{
+				ID:       id,
+				Label:    ptr.To(label),
+				IPv4:     ptr.To(ip),
+				Hostname: ptr.To(label + ".nodebalancer.linode.com"),
+				Tags:     tags,
+			}
+		}
+		addFirewall := func(id int, label string, nodeBalancerID int) {
+			fakeLinode.fw[id] = &linodego.Firewall{ID: id, Label: label, Tags: []string{"gc-cluster", "ccm-cluster:gc-cluster"}}
+			fakeLinode.fwd[id] = map[int]*linodego.FirewallDevice{
+				id: {ID: id, Entity: linodego.FirewallDeviceEntity{ID: nodeBalancerID, Type: "nodebalancer"}},
+			}
+		}
+
+		addNodeBalancer(1, "ccm-owned-by-status", "10.0.0.1", "gc-cluster", "ccm-cluster:gc-cluster")
+		addNodeBalancer(2, "ccm-owned-by-id", "10.0.0.2", "gc-cluster", "ccm-cluster:gc-cluster")
+		addNodeBalancer(3, "ccm-orphan", "10.0.0.3", "gc-cluster", "ccm-cluster:gc-cluster")
+		addFirewall(30, "ccm-orphan", 3)
+		addNodeBalancer(4, "ccm-orphan-user-firewall", "10.0.0.4", "gc-cluster", "ccm-cluster:gc-cluster")
+		addFirewall(40, "user-firewall", 4)
+		addNodeBalancer(5, "ccm-preserved", "10.0.0.5", "gc-cluster", "ccm-cluster:gc-cluster", preserveNodeBalancerTag)
+		addNodeBalancer(6, "ccm-other-cluster", "10.0.0.6", "other-cluster", "ccm-cluster:other-cluster")
+		addNodeBalancer(7, "manual", "10.0.0.7", "gc-cluster", "ccm-cluster:gc-cluster")
+		addNodeBalancer(8, "ccm-owned-by-tags", "10.0.0.8", "gc-cluster", "ccm-cluster:gc-cluster", "ccm-namespace:default", "ccm-service:by-tags")
+		addNodeBalancer(9, "ccm-was-load-balancer", "10.0.0.9", "gc-cluster", "ccm-cluster:gc-cluster")
+		addNodeBalancer(10, "ccm-other-class", "10.0.0.10", "gc-cluster", "ccm-cluster:gc-cluster")
+		// The plain cluster name tag can be set by users on any NodeBalancer
+		addNodeBalancer(11, "ccm-cluster-name-only", "10.0.0.11", "gc-cluster")
+		return fakeLinode
+	}
+
+	newController := func(t *testing.T, fakeLinode *fakeAPI, reportOnly bool) *nodeBalancerGCController {
+		t.Helper()
+
+		factory := informers.NewSharedInformerFactory(fake.NewClientset(), 0)
+		serviceInformer := factory.Core().V1().Services()
+		indexer := serviceInformer.Informer().GetIndexer()
+		require.NoError(t, indexer.Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "by-status", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+			}},
+		}))
+		require.NoError(t, indexer.Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "by-id",
+				Namespace:   "default",
+				Annotations: map[string]string{annotations.AnnLinodeNodeBalancerID: "2"},
+			},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		}))
+		require.NoError(t, indexer.Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "by-tags", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		}))
+		// Services the CCM doesn't reconcile don't keep their former NodeBalancers alive
+		require.NoError(t, indexer.Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "now-cluster-ip",
+				Namespace:   "default",
+				Annotations: map[string]string{annotations.AnnLinodeNodeBalancerID: "9"},
+			},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP},
+		}))
+		require.NoError(t, indexer.Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-class", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, LoadBalancerClass: ptr.To("example.com/other")},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "10.0.0.10"}},
+			}},
+		}))
+
+		controller := newNodeBalancerGCController(newFakeLinodeClient(t, fakeLinode), opts, serviceInformer)
+		controller.gracePeriod = time.Hour
+		controller.reportOnly = reportOnly
+		return controller
+	}
+
+	t.Run("deletes orphans after the grace period", func(t *testing.T) {
+		fakeLinode := newFakeWithNodeBalancers(t)
+		controller := newController(t, fakeLinode, false)
+		now := time.Now()
+		controller.now = func() time.Time { return now }
+
+		require.NoError(t, controller.collect(t.Context()))
+		assert.Len(t, fakeLinode.nb, 11)
+		assert.InDelta(t, 4, testutil.ToFloat64(orphanedNodeBalancersGauge), 0)
+
+		now = now.Add(2 * time.Hour)
+		require.NoError(t, controller.collect(t.Context()))
+		assert.InDelta(t, 0, testutil.ToFloat64(orphanedNodeBalancersGauge), 0)
+		for _, id := range []string{"1", "2", "5", "6", "7", "8", "11"} {
+			assert.Contains(t, fakeLinode.nb, id)
+		}
+		assert.NotContains(t, fakeLinode.nb, "3")
+		assert.NotContains(t, fakeLinode.nb, "4")
+		assert.NotContains(t, fakeLinode.nb, "9")
+		assert.NotContains(t, fakeLinode.nb, "10")
+		assert.NotContains(t, fakeLinode.fw, 30)
+		assert.Contains(t, fakeLinode.fw, 40)
+		assert.Empty(t, controller.orphanedSince)
+	})
+
+	t.Run("keeps orphans that regain a service", func(t *testing.T) {
+		fakeLinode := newFakeWithNodeBalancers(t)
+		controller := newController(t, fakeLinode, false)
+		now := time.Now()
+		controller.now = func() time.Time { return now }
+
+		require.NoError(t, controller.collect(t.Context()))
+		require.NoError(t, controller.informer.Informer().GetIndexer().Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "recreated", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{Hostname: "ccm-orphan.nodebalancer.linode.com"}},
+			}},
+		}))
+
+		now = now.Add(2 * time.Hour)
+		require.NoError(t, controller.collect(t.Context()))
+		assert.Contains(t, fakeLinode.nb, "3")
+		assert.NotContains(t, fakeLinode.nb, "4")
+		assert.NotContains(t, controller.orphanedSince, 3)
+	})
+
+	t.Run("report only", func(t *testing.T) {
+		fakeLinode := newFakeWithNodeBalancers(t)
+		controller := newController(t, fakeLinode, true)
+		now := time.Now()
+		controller.now = func() time.Time { return now }
+
+		require.NoError(t, controller.collect(t.Context()))
+		now = now.Add(2 * time.Hour)
+		require.NoError(t, controller.collect(t.Context()))
+		assert.Len(t, fakeLinode.nb, 11)
+		assert.Len(t, fakeLinode.fw, 2)
+		assert.InDelta(t, 4, testutil.ToFloat64(orphanedNodeBalancersGauge), 0)
+	})
+	t.Run("default cluster name forces report only", func(t *testing.T) {
+		factory := informers.NewSharedInformerFactory(fake.NewClientset(), 0)
+		controller := newNodeBalancerGCController(newFakeLinodeClient(t, newFake(t)),
+			&options.Config{ClusterName: defaultClusterName, NodeBalancerPrefix: "ccm"}, factory.Core().V1().Services())
+
+		stopCh := make(chan struct{})
+		close(stopCh)
+		controller.Run(stopCh)
+		assert.True(t, controller.reportOnly)
+	})
+}
@@ -0,0 +1,206 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/appscode/go/wait"
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	v1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	eventNodeBalancerBackendsName = "nodebalancer-backends"
+
+	// nodeBalancerBackendsHealthyCondition is the Service condition reporting whether every port
+	// of the NodeBalancer of the Service has a backend that passes its health checks.
+	nodeBalancerBackendsHealthyCondition = "NodeBalancerBackendsHealthy"
+
+	backendsHealthyReasonUp   = "BackendsUp"
+	backendsHealthyReasonDown = "BackendsDown"
+)
+
+// nodeBalancerHealthController periodically polls the backend health of the NodeBalancers of
+// provisioned LoadBalancer services, as reported by the health checks of the NodeBalancer, and
+// surfaces it as a Service condition, per-port metrics and events when every backend of a port
+// goes down or comes back up.
+type nodeBalancerHealthController struct {
+	loadbalancers *loadbalancers
+	informer      v1informers.ServiceInformer
+
+	interval time.Duration
+
+	// downPorts records the ports without healthy backends of each service as of the last poll
+	downPorts map[string]sets.Set[int]
+}
+
+func newNodeBalancerHealthController(loadbalancers *loadbalancers, informer v1informers.ServiceInformer) *nodeBalancerHealthController {
+	return &nodeBalancerHealthController{
+		loadbalancers: loadbalancers,
+		informer:      informer,
+		interval:      loadbalancers.options.NodeBalancerHealthInterval,
+		downPorts:     make(map[string]sets.Set[int]),
+	}
+}
+
+func (c *nodeBalancerHealthController) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.informer.Informer().HasSynced) {
+		klog.Error("NodeBalancerHealthController failed to sync the service informer")
+		return
+	}
+
+	wait.Until(func() {
+		if err := c.poll(context.Background()); err != nil {
+			klog.Errorf("NodeBalancerHealthController failed to poll NodeBalancer backend health: %s", err)
+		}
+	}, c.interval, stopCh)
+}
+
+// poll checks the backend health of the NodeBalancer of every provisioned LoadBalancer service
+// and stops exporting metrics for services that are gone.
+func (c *nodeBalancerHealthController) poll(ctx context.Context) error {
+	serviceList, err := c.informer.Lister().List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	seen := sets.New[string]()
+	for _, service := range serviceList {
+		if !isProvisionedLoadBalancer(service) {
+			continue
+		}
+		key := getServiceNn(service)
+		seen.Insert(key)
+		if err := c.checkService(ctx, service); err != nil {
+			klog.Errorf("failed to check NodeBalancer backend health for service (%s): %s", key, err)
+		}
+	}
+
+	for key := range c.downPorts {
+		if seen.Has(key) {
+			continue
+		}
+		namespace, name, _ := strings.Cut(key, "/")
+		forgetNodeBalancerBackends(&v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}})
+		delete(c.downPorts, key)
+	}
+	return nil
+}
+
+// isProvisionedLoadBalancer reports whether service is a LoadBalancer service handled by the CCM
+// whose NodeBalancer has been provisioned.
+func isProvisionedLoadBalancer(service *v1.Service) bool {
+	return service.Spec.Type == v1.ServiceTypeLoadBalancer && service.Spec.LoadBalancerClass == nil && len(service.Status.LoadBalancer.Ingress) > 0
+}
+
+// checkService reports the backend health of the NodeBalancer of service.
+func (c *nodeBalancerHealthController) checkService(ctx context.Context, service *v1.Service) error {
+	nb, err := c.loadbalancers.getNodeBalancerForService(ctx, service)
+	if err != nil {
+		return err
+	}
+	configs, err := c.loadbalancers.client.ListNodeBalancerConfigs(ctx, nb.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list configs of NodeBalancer (%d): %w", nb.ID, err)
+	}
+
+	forgetNodeBalancerBackends(service)
+	down := sets.New[int]()
+	for _, config := range configs {
+		if config.NodesStatus == nil {
+			continue
+		}
+		recordNodeBalancerBackends(service, &config)
+		if config.NodesStatus.Up == 0 {
+			down.Insert(config.Port)
+		}
+	}
+
+	key := getServiceNn(service)
+	previous, known := c.downPorts[key]
+	c.downPorts[key] = down
+	if !known {
+		previous = sets.New[int]()
+	}
+	if failed := down.Difference(previous); failed.Len() > 0 {
+		c.loadbalancers.createServiceEvent(ctx, service, eventNodeBalancerBackendsName, "Warning", "NodeBalancerBackendsDown",
+			fmt.Sprintf("All backends of NodeBalancer (%d) are down on %s", nb.ID, formatPorts(failed)))
+	}
+	if recovered := previous.Difference(down); recovered.Len() > 0 {
+		c.loadbalancers.createServiceEvent(ctx, service, eventNodeBalancerBackendsName, "Normal", "NodeBalancerBackendsRecovered",
+			fmt.Sprintf("Backends of NodeBalancer (%d) are up again on %s", nb.ID, formatPorts(recovered)))
+	}
+
+	return c.setBackendsHealthyCondition(ctx, service, nb, down)
+}
+
+// setBackendsHealthyCondition sets the NodeBalancerBackendsHealthy condition of service, unless
+// it already reports the same health.
+func (c *nodeBalancerHealthController) setBackendsHealthyCondition(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer, down sets.Set[int]) error {
+	condition := metav1.Condition{
+		Type:               nodeBalancerBackendsHealthyCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: service.Generation,
+		Reason:             backendsHealthyReasonUp,
+		Message:            fmt.Sprintf("Every port of NodeBalancer (%d) has a healthy backend", nb.ID),
+	}
+	if down.Len() > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = backendsHealthyReasonDown
+		condition.Message = fmt.Sprintf("NodeBalancer (%d) has no healthy backends on %s", nb.ID, formatPorts(down))
+	}
+
+	current := meta.FindStatusCondition(service.Status.Conditions, nodeBalancerBackendsHealthyCondition)
+	if current != nil && current.Status == condition.Status && current.Reason == condition.Reason &&
+		current.Message == condition.Message && current.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+
+	if err := c.loadbalancers.retrieveKubeClient(); err != nil {
+		return err
+	}
+	updated := service.DeepCopy()
+	meta.SetStatusCondition(&updated.Status.Conditions, condition)
+	if _, err := c.loadbalancers.kubeClient.CoreV1().Services(service.Namespace).UpdateStatus(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to set %s condition: %w", nodeBalancerBackendsHealthyCondition, err)
+	}
+	klog.V(3).Infof("set %s condition of service (%s) to %s: %s", nodeBalancerBackendsHealthyCondition, getServiceNn(service), condition.Status, condition.Message)
+	return nil
+}
+
+// formatPorts lists ports in ascending order for event and condition messages.
+func formatPorts(ports sets.Set[int]) string {
+	sorted := sets.List(ports)
+	formatted := make([]string, 0, len(sorted))
+	for _, port := range sorted {
+		formatted = append(formatted, strconv.Itoa(port))
+	}
+	if len(formatted) == 1 {
+		return "port " + formatted[0]
+	}
+	return "ports " + strings.Join(formatted, ", ")
+}
+
+// recordNodeBalancerBackends exports the number of healthy and unhealthy backends of a port of
+// the NodeBalancer of service.
+func recordNodeBalancerBackends(service *v1.Service, config *linodego.NodeBalancerConfig) {
+	port := strconv.Itoa(config.Port)
+	nodeBalancerBackendsGaugeVec.WithLabelValues(service.Namespace, service.Name, port, "up").Set(float64(config.NodesStatus.Up))
+	nodeBalancerBackendsGaugeVec.WithLabelValues(service.Namespace, service.Name, port, "down").Set(float64(config.NodesStatus.Down))
+}
+
+// forgetNodeBalancerBackends stops exporting the backend health of every port of service.
+func forgetNodeBalancerBackends(service *v1.Service) {
+	nodeBalancerBackendsGaugeVec.DeletePartialMatch(prometheus.Labels{"namespace": service.Namespace, "service": service.Name})
+}
@@ -0,0 +1,101 @@
+package linode
+
+import (
+	"testing"
+
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_nodeBalancerHealthController(t *testing.T) {
+	fakeLinode := newFake(t)
+	kubeClient := fake.NewClientset()
+	lb := newFakeLoadBalancers(t, fakeLinode, kubeClient, &options.Config{})
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: types.UID("web-uid")},
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeLoadBalancer,
+			Ports: []v1.ServicePort{
+				{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000},
+				{Name: "https", Protocol: "TCP", Port: 443, NodePort: 30001},
+			},
+		},
+	}
+	node := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}}}
+	status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+	require.NoError(t, err)
+	service.Status.LoadBalancer = *status
+	_, err = kubeClient.CoreV1().Services(service.Namespace).Create(t.Context(), service, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+	indexer := serviceInformer.Informer().GetIndexer()
+	require.NoError(t, indexer.Add(service))
+	controller := newNodeBalancerHealthController(lb, serviceInformer)
+
+	setPortHealth := func(port, up, down int) {
+		for _, config := range fakeLinode.nbc {
+			if config.Port == port {
+				config.NodesStatus = &linodego.NodeBalancerNodeStatus{Up: up, Down: down}
+			}
+		}
+	}
+	// poll polls the backend health and returns the condition it set on the service
+	poll := func(t *testing.T) *metav1.Condition {
+		t.Helper()
+
+		require.NoError(t, controller.poll(t.Context()))
+		current, err := kubeClient.CoreV1().Services(service.Namespace).Get(t.Context(), service.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NoError(t, indexer.Update(current))
+		return meta.FindStatusCondition(current.Status.Conditions, nodeBalancerBackendsHealthyCondition)
+	}
+	backends := func(port, status string) float64 {
+		return testutil.ToFloat64(nodeBalancerBackendsGaugeVec.WithLabelValues("default", "web", port, status))
+	}
+
+	setPortHealth(80, 1, 0)
+	setPortHealth(443, 1, 0)
+	condition := poll(t)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, backendsHealthyReasonUp, condition.Reason)
+	assert.Empty(t, eventReasons(t, lb))
+
+	setPortHealth(443, 0, 1)
+	condition = poll(t)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, backendsHealthyReasonDown, condition.Reason)
+	assert.Contains(t, condition.Message, "port 443")
+	assert.InDelta(t, 1, backends("80", "up"), 0)
+	assert.InDelta(t, 0, backends("443", "up"), 0)
+	assert.InDelta(t, 1, backends("443", "down"), 0)
+	assert.Equal(t, []string{"NodeBalancerBackendsDown"}, eventReasons(t, lb))
+
+	// Ports that stay down are only reported once
+	poll(t)
+	assert.Equal(t, []string{"NodeBalancerBackendsDown"}, eventReasons(t, lb))
+
+	setPortHealth(443, 2, 0)
+	condition = poll(t)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.ElementsMatch(t, []string{"NodeBalancerBackendsDown", "NodeBalancerBackendsRecovered"}, eventReasons(t, lb))
+
+	require.NoError(t, indexer.Delete(service))
+	require.NoError(t, controller.poll(t.Context()))
+	assert.Equal(t, 0, testutil.CollectAndCount(nodeBalancerBackendsGaugeVec))
+}
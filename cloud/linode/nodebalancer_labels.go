@@ -0,0 +1,114 @@
+package linode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+const (
+	// NodeBalancerLabelModeTimestamp labels NodeBalancers with the prefix and the creation time.
+	NodeBalancerLabelModeTimestamp = "timestamp"
+	// NodeBalancerLabelModeService labels NodeBalancers with the prefix, the rendered
+	// --nodebalancer-label-template and a hash of the identity of the service.
+	NodeBalancerLabelModeService = "service"
+
+	// DefaultNodeBalancerLabelTemplate is the default --nodebalancer-label-template.
+	DefaultNodeBalancerLabelTemplate = "{{ .Namespace }}-{{ .Name }}"
+
+	// maxNodeBalancerLabelLength is the longest label accepted by the Linode API
+	maxNodeBalancerLabelLength = 32
+	// labelHashLength is the number of hex characters of the service identity hash in labels
+	labelHashLength = 8
+)
+
+var (
+	supportedNodeBalancerLabelModes = []string{NodeBalancerLabelModeTimestamp, NodeBalancerLabelModeService}
+	invalidLabelCharacters          = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+	repeatedLabelSeparators         = regexp.MustCompile(`[-_]{2,}`)
+)
+
+// nodeBalancerLabelData is available to --nodebalancer-label-template.
+type nodeBalancerLabelData struct {
+	ClusterName string
+	Namespace   string
+	Name        string
+}
+
+// parseNodeBalancerLabelTemplate parses and test renders a --nodebalancer-label-template.
+func parseNodeBalancerLabelTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("nodebalancer-label").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nodebalancer-label-template: %w", err)
+	}
+	if err := tmpl.Execute(&strings.Builder{}, nodeBalancerLabelData{}); err != nil {
+		return nil, fmt.Errorf("invalid nodebalancer-label-template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// timestampNodeBalancerLabel returns a label made of the prefix and the current time.
+func timestampNodeBalancerLabel(opts *options.Config) string {
+	unixNano := strconv.FormatInt(time.Now().UnixNano(), 16)
+	return fmt.Sprintf("%s-%s", opts.NodeBalancerPrefix, unixNano[len(unixNano)-12:])
+}
+
+// serviceNodeBalancerLabel returns a stable label for service of clusterName. The rendered template
+// is reduced to the characters allowed in labels and truncated so that the label, which ends in a
+// hash of the cluster name, namespace and name of the service, fits the Linode label length limit.
+func serviceNodeBalancerLabel(opts *options.Config, clusterName string, service *v1.Service) (string, error) {
+	tmpl, err := parseNodeBalancerLabelTemplate(opts.NodeBalancerLabelTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, nodeBalancerLabelData{
+		ClusterName: clusterName,
+		Namespace:   service.Namespace,
+		Name:        service.Name,
+	}); err != nil {
+		return "", fmt.Errorf("failed to render nodebalancer-label-template: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(clusterName + "/" + service.Namespace + "/" + service.Name))
+	hash := hex.EncodeToString(sum[:])[:labelHashLength]
+
+	body := invalidLabelCharacters.ReplaceAllString(rendered.String(), "-")
+	body = repeatedLabelSeparators.ReplaceAllString(body, "-")
+	body = strings.Trim(body, "-_")
+
+	prefix := opts.NodeBalancerPrefix + "-"
+	if room := maxNodeBalancerLabelLength - len(prefix) - len(hash) - 1; len(body) > room {
+		body = strings.TrimRight(body[:max(room, 0)], "-_")
+	}
+	if body == "" {
+		return prefix + hash, nil
+	}
+	return prefix + body + "-" + hash, nil
+}
+
+// nodeBalancerLabel returns the label for a NodeBalancer of service according to
+// --nodebalancer-label-mode.
+func nodeBalancerLabel(opts *options.Config, clusterName string, service *v1.Service) string {
+	if opts.NodeBalancerLabelMode != NodeBalancerLabelModeService {
+		return timestampNodeBalancerLabel(opts)
+	}
+
+	label, err := serviceNodeBalancerLabel(opts, clusterName, service)
+	if err != nil {
+		klog.Errorf("falling back to a timestamp label for service (%s): %s", getServiceNn(service), err)
+		return timestampNodeBalancerLabel(opts)
+	}
+	return label
+}
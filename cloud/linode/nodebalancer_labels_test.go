@@ -0,0 +1,136 @@
+package linode
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_serviceNodeBalancerLabel(t *testing.T) {
+	opts := &options.Config{NodeBalancerPrefix: "ccm"}
+
+	validLabel := regexp.MustCompile(`^[a-zA-Z0-9_-]{3,32}$`)
+	newService := func(namespace, name string) *v1.Service {
+		return &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	testcases := []struct {
+		name     string
+		template string
+		cluster  string
+		service  *v1.Service
+		expected string
+	}{
+		{
+			name:     "default template",
+			template: DefaultNodeBalancerLabelTemplate,
+			cluster:  "prod",
+			service:  newService("default", "web"),
+			expected: "ccm-default-web-",
+		},
+		{
+			name:     "truncated",
+			template: DefaultNodeBalancerLabelTemplate,
+			cluster:  "prod",
+			service:  newService("payments", strings.Repeat("checkout-", 7)),
+			expected: "ccm-payments-checkout-c-",
+		},
+		{
+			name:     "invalid characters",
+			template: "{{ .ClusterName }}.{{ .Name }}",
+			cluster:  "prod.eu",
+			service:  newService("default", "web.v2"),
+			expected: "ccm-prod-eu-web-v2-",
+		},
+		{
+			name:     "empty template",
+			template: "",
+			cluster:  "prod",
+			service:  newService("default", "web"),
+			expected: "ccm-",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts.NodeBalancerLabelTemplate = tc.template
+			label, err := serviceNodeBalancerLabel(opts, tc.cluster, tc.service)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(label, tc.expected), label)
+			assert.Len(t, label, len(tc.expected)+labelHashLength)
+			assert.Regexp(t, validLabel, label)
+
+			again, err := serviceNodeBalancerLabel(opts, tc.cluster, tc.service)
+			require.NoError(t, err)
+			assert.Equal(t, label, again)
+		})
+	}
+
+	t.Run("collisions", func(t *testing.T) {
+		opts.NodeBalancerLabelTemplate = DefaultNodeBalancerLabelTemplate
+		// Both render to "a-b-c" but are different services
+		first, err := serviceNodeBalancerLabel(opts, "prod", newService("a-b", "c"))
+		require.NoError(t, err)
+		second, err := serviceNodeBalancerLabel(opts, "prod", newService("a", "b-c"))
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+
+		otherCluster, err := serviceNodeBalancerLabel(opts, "staging", newService("a-b", "c"))
+		require.NoError(t, err)
+		assert.NotEqual(t, first, otherCluster)
+	})
+}
+
+func Test_parseNodeBalancerLabelTemplate(t *testing.T) {
+	_, err := parseNodeBalancerLabelTemplate("{{ .Namespace }}-{{ .Name }}")
+	require.NoError(t, err)
+
+	_, err = parseNodeBalancerLabelTemplate("{{ .Namespace")
+	require.ErrorContains(t, err, "invalid nodebalancer-label-template")
+
+	_, err = parseNodeBalancerLabelTemplate("{{ .Service }}")
+	require.ErrorContains(t, err, "invalid nodebalancer-label-template")
+}
+
+func Test_updateNodeBalancerRenamesInServiceLabelMode(t *testing.T) {
+	opts := &options.Config{
+		NodeBalancerLabelMode:     NodeBalancerLabelModeService,
+		NodeBalancerLabelTemplate: DefaultNodeBalancerLabelTemplate,
+	}
+
+	fakeLinode := newFake(t)
+	lb := newFakeLoadBalancers(t, fakeLinode, fake.NewClientset(), opts)
+
+	fakeLinode.nb["1"] = &linodego.NodeBalancer{
+		ID:       1,
+		Label:    ptr.To("ccm-18f3c2a1b4d5"),
+		IPv4:     ptr.To("10.0.0.1"),
+		Hostname: ptr.To("1.nodebalancer.linode.com"),
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000}},
+		},
+	}
+	node := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.2"}}}}
+
+	nb, err := lb.client.GetNodeBalancer(t.Context(), 1)
+	require.NoError(t, err)
+	require.NoError(t, lb.updateNodeBalancer(t.Context(), "prod", service, []*v1.Node{node}, nb))
+
+	expected, err := serviceNodeBalancerLabel(opts, "prod", service)
+	require.NoError(t, err)
+	assert.Equal(t, expected, *fakeLinode.nb[strconv.Itoa(nb.ID)].Label)
+}
@@ -0,0 +1,207 @@
+package linode
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/linode/linodego/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+)
+
+// Ownership tags identify the cluster and Service a NodeBalancer was created for, so that it can
+// be found again when the LoadBalancer status of the Service is lost.
+const (
+	ownerClusterTagPrefix   = "ccm-cluster:"
+	ownerNamespaceTagPrefix = "ccm-namespace:"
+	ownerServiceTagPrefix   = "ccm-service:"
+	ownerUIDTagPrefix       = "ccm-uid:"
+
+	// maxTagLength is the longest tag accepted by the Linode API
+	maxTagLength = 50
+	// ownerTagHashLength is the length of the hash replacing the end of a value too long for a tag
+	ownerTagHashLength = 8
+)
+
+var ownerTagPrefixes = []string{ownerClusterTagPrefix, ownerNamespaceTagPrefix, ownerServiceTagPrefix, ownerUIDTagPrefix}
+
+// nodeBalancerOwnedError is returned when a Service references a NodeBalancer owned by another
+// Service or cluster.
+type nodeBalancerOwnedError struct {
+	nodeBalancerID int
+	owner          string
+}
+
+func (e nodeBalancerOwnedError) Error() string {
+	return fmt.Sprintf("NodeBalancer (%d) is owned by %s, release it by deleting that service with the %s annotation or by removing its ccm- ownership tags",
+		e.nodeBalancerID, e.owner, annotations.AnnLinodeLoadBalancerPreserve)
+}
+
+// ownerTag builds an ownership tag. A value too long for the maximum tag length is shortened and
+// suffixed with a hash of the full value, so that values sharing a long prefix get distinct tags.
+func ownerTag(prefix, value string) string {
+	tag := prefix + value
+	if len(tag) <= maxTagLength {
+		return tag
+	}
+	sum := sha256.Sum256([]byte(value))
+	hash := hex.EncodeToString(sum[:])[:ownerTagHashLength]
+	return tag[:maxTagLength-ownerTagHashLength-1] + "-" + hash
+}
+
+// nodeBalancerOwnershipTags returns the tags marking service of clusterName as the owner of a
+// NodeBalancer.
+func nodeBalancerOwnershipTags(clusterName string, service *v1.Service) []string {
+	tags := []string{}
+	if clusterName != "" {
+		tags = append(tags, ownerTag(ownerClusterTagPrefix, clusterName))
+	}
+	if service.Namespace != "" {
+		tags = append(tags, ownerTag(ownerNamespaceTagPrefix, service.Namespace))
+	}
+	tags = append(tags, ownerTag(ownerServiceTagPrefix, service.Name))
+	if service.UID != "" {
+		tags = append(tags, ownerTag(ownerUIDTagPrefix, string(service.UID)))
+	}
+	return tags
+}
+
+// ownerTagsByPrefix returns the ownership tags among tags, keyed by their prefix.
+func ownerTagsByPrefix(tags []string) map[string]string {
+	owner := make(map[string]string)
+	for _, tag := range tags {
+		for _, prefix := range ownerTagPrefixes {
+			if strings.HasPrefix(tag, prefix) {
+				owner[prefix] = tag
+			}
+		}
+	}
+	return owner
+}
+
+// isOwnershipTag reports whether tag is one of the ownership tags.
+func isOwnershipTag(tag string) bool {
+	return slices.ContainsFunc(ownerTagPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(tag, prefix)
+	})
+}
+
+// isReservedTag reports whether tag is one of the tags the CCM uses to track NodeBalancers, which
+// Services can't set through the tags annotation. Otherwise a Service could claim the NodeBalancer
+// of another Service by tagging its own with the other's ownership tags.
+func isReservedTag(tag string) bool {
+	return isOwnershipTag(tag) || strings.HasPrefix(tag, replacedByTagPrefix) || tag == releaseReservedIPv4Tag ||
+		strings.HasPrefix(tag, replacementForTagPrefix)
+}
+
+// ownsNodeBalancer reports whether the ownership tags of nb name service of clusterName, either by
+// its UID or by its namespace and name. A Service that was recreated under the same name still
+// owns its NodeBalancer even though its UID changed.
+func ownsNodeBalancer(clusterName string, service *v1.Service, nb *linodego.NodeBalancer) bool {
+	owner := ownerTagsByPrefix(nb.Tags)
+	expected := ownerTagsByPrefix(nodeBalancerOwnershipTags(clusterName, service))
+	if owner[ownerClusterTagPrefix] != expected[ownerClusterTagPrefix] {
+		return false
+	}
+	if service.UID != "" && owner[ownerUIDTagPrefix] == expected[ownerUIDTagPrefix] {
+		return true
+	}
+	return owner[ownerNamespaceTagPrefix] == expected[ownerNamespaceTagPrefix] &&
+		owner[ownerServiceTagPrefix] == expected[ownerServiceTagPrefix]
+}
+
+// checkNodeBalancerOwnership returns a nodeBalancerOwnedError unless nb is unowned or owned by
+// service. Unowned NodeBalancers, such as those created outside of the CCM or released by their
+// previous Service, can be adopted through the NodeBalancer ID annotation.
+func checkNodeBalancerOwnership(clusterName string, service *v1.Service, nb *linodego.NodeBalancer) error {
+	owner := ownerTagsByPrefix(nb.Tags)
+	if len(owner) == 0 || ownsNodeBalancer(clusterName, service, nb) {
+		return nil
+	}
+
+	names := make([]string, 0, len(owner))
+	for _, prefix := range []string{ownerClusterTagPrefix, ownerNamespaceTagPrefix, ownerServiceTagPrefix} {
+		if tag, ok := owner[prefix]; ok {
+			names = append(names, strings.TrimPrefix(tag, prefix))
+		}
+	}
+	return nodeBalancerOwnedError{nodeBalancerID: nb.ID, owner: strings.Join(names, "/")}
+}
+
+// getNodeBalancerByOwnershipTags looks up the NodeBalancer whose ownership tags name service,
+// preferring one that also carries its UID. Otherwise a NodeBalancer tagged with the namespace and
+// name of service is only taken over as that of a previous incarnation if no Service with the UID
+// it is tagged with exists anymore.
+func (l *loadbalancers) getNodeBalancerByOwnershipTags(ctx context.Context, service *v1.Service) (*linodego.NodeBalancer, error) {
+	if l.options.ClusterName == "" {
+		return nil, lbNotFoundError{serviceNn: getServiceNn(service)}
+	}
+
+	lbs, err := l.client.ListNodeBalancers(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	uidTag := ownerTag(ownerUIDTagPrefix, string(service.UID))
+	var candidates []*linodego.NodeBalancer
+	for i := range lbs {
+		nb := &lbs[i]
+		if !ownsNodeBalancer(l.options.ClusterName, service, nb) || isPendingReplacement(nb) {
+			continue
+		}
+		if service.UID != "" && slices.Contains(nb.Tags, uidTag) {
+			klog.V(2).Infof("found NodeBalancer (%d) for service (%s) via ownership tags", nb.ID, getServiceNn(service))
+			return nb, nil
+		}
+		candidates = append(candidates, nb)
+	}
+	if len(candidates) == 0 {
+		return nil, lbNotFoundError{serviceNn: getServiceNn(service)}
+	}
+
+	if err = l.retrieveKubeClient(); err != nil {
+		return nil, err
+	}
+	services, err := l.kubeClient.CoreV1().Services(service.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	uidTags := make(map[string]bool, len(services.Items))
+	for _, svc := range services.Items {
+		uidTags[ownerTag(ownerUIDTagPrefix, string(svc.UID))] = true
+	}
+
+	var matches []*linodego.NodeBalancer
+	for _, nb := range candidates {
+		owner := ownerTagsByPrefix(nb.Tags)[ownerUIDTagPrefix]
+		if owner == "" || uidTags[owner] {
+			klog.V(2).Infof("skipping NodeBalancer (%d) tagged with the name of service (%s), its owner %q may still exist",
+				nb.ID, getServiceNn(service), strings.TrimPrefix(owner, ownerUIDTagPrefix))
+			continue
+		}
+		matches = append(matches, nb)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, lbNotFoundError{serviceNn: getServiceNn(service)}
+	case 1:
+		klog.Infof("found NodeBalancer (%d) of a previous incarnation of service (%s) via ownership tags", matches[0].ID, getServiceNn(service))
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, nb := range matches {
+			ids = append(ids, strconv.Itoa(nb.ID))
+		}
+		return nil, fmt.Errorf("found %d NodeBalancers (%s) tagged as owned by service (%s), set the %s annotation to pick one",
+			len(matches), strings.Join(ids, ", "), getServiceNn(service), annotations.AnnLinodeNodeBalancerID)
+	}
+}
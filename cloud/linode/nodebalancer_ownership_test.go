@@ -0,0 +1,224 @@
+package linode
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_nodeBalancerOwnershipTags(t *testing.T) {
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      strings.Repeat("a", 63),
+		Namespace: "default",
+		UID:       "0b5f3a5e-2f40-4c39-9d8f-0d4c5d7b1a2e",
+	}}
+
+	tags := nodeBalancerOwnershipTags("prod", service)
+	assert.Equal(t, []string{
+		"ccm-cluster:prod",
+		"ccm-namespace:default",
+		"ccm-service:" + strings.Repeat("a", maxTagLength-len(ownerServiceTagPrefix)-ownerTagHashLength-1) + "-7d3e74a0",
+		"ccm-uid:0b5f3a5e-2f40-4c39-9d8f-0d4c5d7b1a2e",
+	}, tags)
+	assert.True(t, isOwnershipTag(tags[0]))
+	assert.False(t, isOwnershipTag("prod"))
+
+	// Names sharing a prefix longer than a tag get distinct tags
+	other := service.DeepCopy()
+	other.Name = strings.Repeat("a", 62) + "b"
+	assert.NotEqual(t, tags[2], nodeBalancerOwnershipTags("prod", other)[2])
+	assert.LessOrEqual(t, len(nodeBalancerOwnershipTags("prod", other)[2]), maxTagLength)
+}
+
+func Test_GetLoadBalancerTagsReservedTags(t *testing.T) {
+	lb := &loadbalancers{options: &options.Config{}}
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:      "web",
+		Namespace: "default",
+		UID:       "uid-1",
+		Annotations: map[string]string{
+			annotations.AnnLinodeLoadBalancerTags: "team-a,ccm-cluster:other,ccm-namespace:other,ccm-service:other,ccm-uid:other,ccm-replaced-by:1,ccm-replacement-for:1,ccm-preserve",
+		},
+	}}
+
+	tags := lb.GetLoadBalancerTags(t.Context(), "prod", service)
+	assert.Equal(t, []string{"prod", "ccm-cluster:prod", "ccm-namespace:default", "ccm-service:web", "ccm-uid:uid-1", "team-a", "ccm-preserve"}, tags)
+	assert.True(t, ownsNodeBalancer("prod", service, &linodego.NodeBalancer{Tags: tags}))
+}
+
+func Test_checkNodeBalancerOwnership(t *testing.T) {
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "new-uid"}}
+
+	testcases := []struct {
+		name          string
+		tags          []string
+		expectedError string
+	}{
+		{
+			name: "unowned",
+			tags: []string{"prod", "team-a"},
+		},
+		{
+			name: "owned by the service",
+			tags: []string{"prod", "ccm-cluster:prod", "ccm-namespace:default", "ccm-service:web", "ccm-uid:new-uid"},
+		},
+		{
+			name: "owned by a previous incarnation of the service",
+			tags: []string{"ccm-cluster:prod", "ccm-namespace:default", "ccm-service:web", "ccm-uid:old-uid"},
+		},
+		{
+			name:          "owned by another service",
+			tags:          []string{"ccm-cluster:prod", "ccm-namespace:default", "ccm-service:api", "ccm-uid:other"},
+			expectedError: "NodeBalancer (1) is owned by prod/default/api",
+		},
+		{
+			name:          "owned by another cluster",
+			tags:          []string{"ccm-cluster:staging", "ccm-namespace:default", "ccm-service:web"},
+			expectedError: "NodeBalancer (1) is owned by staging/default/web",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkNodeBalancerOwnership("prod", service, &linodego.NodeBalancer{ID: 1, Tags: tc.tags})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_getNodeBalancerByOwnershipTags(t *testing.T) {
+
+	fakeLinode := newFake(t)
+	lb := newFakeLoadBalancers(t, fakeLinode, fake.NewClientset(), &options.Config{ClusterName: "prod"})
+
+	addNodeBalancer := func(id int, tags ...string) {
+		fakeLinode.nb[strconv.Itoa(id)] = &linodego.NodeBalancer{
+			ID:       id,
+			Label:    ptr.To("ccm-" + strconv.Itoa(id)),
+			IPv4:     ptr.To("10.0.0." + strconv.Itoa(id)),
+			Hostname: ptr.To(strconv.Itoa(id) + ".nodebalancer.linode.com"),
+			Tags:     tags,
+		}
+	}
+	addNodeBalancer(1, "ccm-cluster:prod", "ccm-namespace:default", "ccm-service:web", "ccm-uid:old-uid")
+	addNodeBalancer(2, "ccm-cluster:staging", "ccm-namespace:default", "ccm-service:web")
+	addNodeBalancer(3, "ccm-cluster:prod", "ccm-namespace:default", "ccm-service:api", "ccm-uid:api-uid-1")
+	addNodeBalancer(4, "ccm-cluster:prod", "ccm-namespace:default", "ccm-service:api", "ccm-uid:api-uid-2")
+
+	newService := func(name, uid string) *v1.Service {
+		return &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid)}}
+	}
+
+	t.Run("service recreated without its status", func(t *testing.T) {
+		nb, err := lb.getNodeBalancerForService(t.Context(), newService("web", "new-uid"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, nb.ID)
+	})
+
+	t.Run("prefers the NodeBalancer with the service UID", func(t *testing.T) {
+		nb, err := lb.getNodeBalancerForService(t.Context(), newService("api", "api-uid-2"))
+		require.NoError(t, err)
+		assert.Equal(t, 4, nb.ID)
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		_, err := lb.getNodeBalancerForService(t.Context(), newService("api", "api-uid-3"))
+		assert.ErrorContains(t, err, "found 2 NodeBalancers")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := lb.getNodeBalancerForService(t.Context(), newService("worker", "worker-uid"))
+		assert.ErrorAs(t, err, &lbNotFoundError{})
+	})
+
+	t.Run("skips NodeBalancers of services that still exist", func(t *testing.T) {
+		owner := newService("cache", "cache-uid-1")
+		addNodeBalancer(5, "ccm-cluster:prod", "ccm-namespace:default", "ccm-service:cache", "ccm-uid:cache-uid-1")
+		_, err := lb.kubeClient.CoreV1().Services("default").Create(t.Context(), owner, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		_, err = lb.getNodeBalancerForService(t.Context(), newService("cache", "cache-uid-2"))
+		assert.ErrorAs(t, err, &lbNotFoundError{})
+	})
+
+	t.Run("long names sharing a prefix", func(t *testing.T) {
+		prefix := strings.Repeat("frontend-", 6)
+		owner := newService(prefix+"primary", "primary-uid")
+		_, err := lb.kubeClient.CoreV1().Services("default").Create(t.Context(), owner, metav1.CreateOptions{})
+		require.NoError(t, err)
+		addNodeBalancer(6, nodeBalancerOwnershipTags("prod", owner)...)
+
+		_, err = lb.getNodeBalancerForService(t.Context(), newService(prefix+"secondary", "secondary-uid"))
+		assert.ErrorAs(t, err, &lbNotFoundError{})
+
+		nb, err := lb.getNodeBalancerForService(t.Context(), owner)
+		require.NoError(t, err)
+		assert.Equal(t, 6, nb.ID)
+	})
+}
+
+func Test_adoptAndReleaseNodeBalancer(t *testing.T) {
+	fakeLinode := newFake(t)
+
+	node := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}}}
+	newService := func(name string, annotationsMap map[string]string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid"), Annotations: annotationsMap},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000}},
+			},
+		}
+	}
+
+	owner := newService("owner", map[string]string{annotations.AnnLinodeLoadBalancerPreserve: "true"})
+	kubeClient := fake.NewClientset()
+	lb := newFakeLoadBalancers(t, fakeLinode, kubeClient, &options.Config{})
+	status, err := lb.EnsureLoadBalancer(t.Context(), "prod", owner, []*v1.Node{node})
+	require.NoError(t, err)
+	owner.Status.LoadBalancer = *status
+	_, err = kubeClient.CoreV1().Services(owner.Namespace).Create(t.Context(), owner, metav1.CreateOptions{})
+	require.NoError(t, err)
+	nb, err := lb.getNodeBalancerForService(t.Context(), owner)
+	require.NoError(t, err)
+
+	// Another service can't take over the NodeBalancer while it is owned
+	adopter := newService("adopter", map[string]string{annotations.AnnLinodeNodeBalancerID: strconv.Itoa(nb.ID)})
+	_, err = lb.EnsureLoadBalancer(t.Context(), "prod", adopter, []*v1.Node{node})
+	require.ErrorAs(t, err, &nodeBalancerOwnedError{})
+
+	// Deleting the adopter must not delete the NodeBalancer of the owner
+	adopter.Status.LoadBalancer = *status
+	require.NoError(t, lb.EnsureLoadBalancerDeleted(t.Context(), "prod", adopter))
+	require.Contains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+
+	// Deleting the preserving owner releases the NodeBalancer
+	require.NoError(t, lb.EnsureLoadBalancerDeleted(t.Context(), "prod", owner))
+	released := fakeLinode.nb[strconv.Itoa(nb.ID)]
+	assert.Contains(t, released.Tags, preserveNodeBalancerTag)
+	assert.Empty(t, ownerTagsByPrefix(released.Tags))
+
+	// which lets the adopter take it over
+	adopter.Status = v1.ServiceStatus{}
+	_, err = lb.EnsureLoadBalancer(t.Context(), "prod", adopter, []*v1.Node{node})
+	require.NoError(t, err)
+	adopted := fakeLinode.nb[strconv.Itoa(nb.ID)]
+	assert.Equal(t, lb.GetLoadBalancerTags(t.Context(), "prod", adopter), adopted.Tags)
+	assert.NotContains(t, adopted.Tags, preserveNodeBalancerTag)
+}
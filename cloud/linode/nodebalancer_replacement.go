@@ -0,0 +1,265 @@
+package linode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/sentry"
+)
+
+const (
+	eventNodeBalancerReplacementName = "nodebalancer-replacement"
+
+	// replacedByTagPrefix tags a NodeBalancer that was replaced by a new one with the ID of its
+	// replacement, until the Service status references the replacement and it is deleted.
+	replacedByTagPrefix = "ccm-replaced-by:"
+	// releaseReservedIPv4Tag marks a replaced NodeBalancer whose reserved IPv4 address is deleted
+	// along with it.
+	releaseReservedIPv4Tag = "ccm-release-reserved-ipv4"
+)
+
+var (
+	// nodeBalancerReplacementPollInterval is how often the Service status is checked for the
+	// replacement NodeBalancer before the replaced one is deleted.
+	nodeBalancerReplacementPollInterval = 5 * time.Second
+	// nodeBalancerReplacementTimeout is how long the replaced NodeBalancer is kept at most while
+	// waiting for the Service status to reference the replacement.
+	nodeBalancerReplacementTimeout = 10 * time.Minute
+)
+
+// replacedByTag returns the tag marking a NodeBalancer as replaced by the NodeBalancer with id.
+func replacedByTag(id int) string {
+	return replacedByTagPrefix + strconv.Itoa(id)
+}
+
+// replacementNodeBalancerID returns the ID of the NodeBalancer that replaced nb, if any.
+func replacementNodeBalancerID(nb *linodego.NodeBalancer) (int, bool) {
+	for _, tag := range nb.Tags {
+		if rawID, ok := strings.CutPrefix(tag, replacedByTagPrefix); ok {
+			if id, err := strconv.Atoi(rawID); err == nil {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// carryOverFirewall returns service, or a copy of it that attaches the replacement of nb to the
+// firewall created for the firewall-acl annotation of service, so that the replacement does not
+// get a firewall of its own.
+func (l *loadbalancers) carryOverFirewall(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer) (*v1.Service, error) {
+	if _, ok := service.GetAnnotations()[annotations.AnnLinodeCloudFirewallID]; ok {
+		return service, nil
+	}
+	if _, ok := service.GetAnnotations()[annotations.AnnLinodeCloudFirewallACL]; !ok {
+		return service, nil
+	}
+
+	firewalls, err := l.client.ListNodeBalancerFirewalls(ctx, nb.ID, &linodego.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(firewalls) != 1 {
+		return service, nil
+	}
+
+	service = service.DeepCopy()
+	service.Annotations[annotations.AnnLinodeCloudFirewallID] = strconv.Itoa(firewalls[0].ID)
+	return service, nil
+}
+
+// releaseReplacedNodeBalancer removes the ownership tags of nb and, with service labels, renames it
+// so that its replacement can take its label.
+func (l *loadbalancers) releaseReplacedNodeBalancer(ctx context.Context, nb *linodego.NodeBalancer) (*linodego.NodeBalancer, error) {
+	update := nb.GetUpdateOptions()
+	update.Tags = slices.DeleteFunc(slices.Clone(nb.Tags), isOwnershipTag)
+	if l.options.NodeBalancerLabelMode == NodeBalancerLabelModeService {
+		label := timestampNodeBalancerLabel(l.options)
+		update.Label = &label
+	}
+	return l.client.UpdateNodeBalancer(ctx, nb.ID, update)
+}
+
+// switchToReplacement tags the released NodeBalancer replaced with the ID of replacement and
+// deletes it in the background once the Service status references replacement. The reserved IPv4
+// address of replaced is deleted with it when releaseIPv4 is set. reason prefixes the reasons of
+// the events reporting the progress.
+//
+// The tags outlive the CCM, so that a deletion interrupted by a restart is resumed by the next
+// reconcile of service, see resumeNodeBalancerReplacements.
+func (l *loadbalancers) switchToReplacement(
+	ctx context.Context,
+	service *v1.Service,
+	replaced, replacement *linodego.NodeBalancer,
+	reason string,
+	releaseIPv4 bool,
+) {
+	// The tag lets lookups by the current Service status find the replacement until the status
+	// is updated. Without it the status still leads to replaced, which is then replaced again.
+	update := replaced.GetUpdateOptions()
+	update.Tags = append(slices.DeleteFunc(slices.Clone(replaced.Tags), isOwnershipTag), replacedByTag(replacement.ID))
+	if releaseIPv4 {
+		update.Tags = append(update.Tags, releaseReservedIPv4Tag)
+	}
+	if tagged, err := l.client.UpdateNodeBalancer(ctx, replaced.ID, update); err != nil {
+		klog.Errorf("failed to tag NodeBalancer (%d) as replaced by NodeBalancer (%d): %s", replaced.ID, replacement.ID, err)
+		sentry.CaptureError(ctx, err)
+	} else {
+		replaced = tagged
+	}
+
+	l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Normal", reason+"Switching",
+		fmt.Sprintf("Switching the service to NodeBalancer (%d), NodeBalancer (%d) is deleted once the service status references it", replacement.ID, replaced.ID))
+
+	l.startNodeBalancerReplacementCleanup(ctx, service, replaced, replacement, reason)
+}
+
+// resumeNodeBalancerReplacements deletes the NodeBalancers tagged as replaced by nb, the current
+// NodeBalancer of service, whose deletion was not completed by an earlier reconcile, for example
+// because the CCM was restarted while waiting for the Service status.
+func (l *loadbalancers) resumeNodeBalancerReplacements(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer) {
+	if _, dryRun := l.client.(*client.ClientWithDryRun); dryRun {
+		return
+	}
+
+	replaced, err := l.listReplacedNodeBalancers(ctx, nb)
+	if err != nil {
+		klog.Errorf("failed to list NodeBalancers replaced by NodeBalancer (%d) for service (%s): %s", nb.ID, getServiceNn(service), err)
+		return
+	}
+	for i := range replaced {
+		l.startNodeBalancerReplacementCleanup(ctx, service, &replaced[i], nb, "NodeBalancerReplacement")
+	}
+}
+
+// listReplacedNodeBalancers returns the NodeBalancers tagged as replaced by nb.
+func (l *loadbalancers) listReplacedNodeBalancers(ctx context.Context, nb *linodego.NodeBalancer) ([]linodego.NodeBalancer, error) {
+	filter, err := json.Marshal(map[string]string{"tags": replacedByTag(nb.ID)})
+	if err != nil {
+		return nil, err
+	}
+	return l.client.ListNodeBalancers(ctx, linodego.NewListOptions(0, string(filter)))
+}
+
+// deleteReplacedNodeBalancers deletes the NodeBalancers replaced by nb, along with the reserved
+// IPv4 addresses marked to be released, when service and nb are deleted.
+func (l *loadbalancers) deleteReplacedNodeBalancers(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer) error {
+	replaced, err := l.listReplacedNodeBalancers(ctx, nb)
+	if err != nil {
+		return err
+	}
+	for i := range replaced {
+		if err = l.deleteReplacedNodeBalancer(ctx, service, &replaced[i], nb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startNodeBalancerReplacementCleanup deletes replaced in the background once the Service status
+// references replacement, unless that is already being waited for.
+func (l *loadbalancers) startNodeBalancerReplacementCleanup(
+	ctx context.Context,
+	service *v1.Service,
+	replaced, replacement *linodego.NodeBalancer,
+	reason string,
+) {
+	// A plan stops at the switch, the replaced NodeBalancer is only deleted once a real
+	// reconciliation updated the Service status
+	if _, dryRun := l.client.(*client.ClientWithDryRun); dryRun {
+		return
+	}
+
+	if err := l.retrieveKubeClient(); err != nil {
+		klog.Errorf("failed to wait for the status of service (%s) to delete NodeBalancer (%d): %s", getServiceNn(service), replaced.ID, err)
+		return
+	}
+	if _, running := l.replacementCleanups.LoadOrStore(replaced.ID, struct{}{}); running {
+		return
+	}
+	l.replacements.Go(func() {
+		defer l.replacementCleanups.Delete(replaced.ID)
+		l.completeNodeBalancerReplacement(context.WithoutCancel(ctx), service, replaced, replacement, reason)
+	})
+}
+
+// completeNodeBalancerReplacement deletes the replaced NodeBalancer once the status of service
+// references its replacement, or once service is deleted. The replaced NodeBalancer is kept if
+// neither happens within nodeBalancerReplacementTimeout, until the next reconcile of service
+// tries again.
+func (l *loadbalancers) completeNodeBalancerReplacement(
+	ctx context.Context,
+	service *v1.Service,
+	replaced, replacement *linodego.NodeBalancer,
+	reason string,
+) {
+	serviceNn := getServiceNn(service)
+	pollCtx, cancel := context.WithTimeout(ctx, nodeBalancerReplacementTimeout)
+	defer cancel()
+
+	err := wait.PollUntilContextCancel(pollCtx, nodeBalancerReplacementPollInterval, true, func(ctx context.Context) (bool, error) {
+		current, err := l.kubeClient.CoreV1().Services(service.Namespace).Get(ctx, service.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			klog.V(3).Infof("failed to get service (%s) while replacing NodeBalancer (%d): %s", serviceNn, replaced.ID, err)
+			return false, nil
+		}
+		if current.UID != service.UID {
+			return true, nil
+		}
+		return slices.ContainsFunc(current.Status.LoadBalancer.Ingress, func(ingress v1.LoadBalancerIngress) bool {
+			return replacement.Hostname != nil && ingress.Hostname == *replacement.Hostname
+		}), nil
+	})
+	if err != nil {
+		klog.Warningf("kept NodeBalancer (%d) as the status of service (%s) does not reference NodeBalancer (%d)", replaced.ID, serviceNn, replacement.ID)
+		l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Warning", reason+"Stalled",
+			fmt.Sprintf("The service status does not reference NodeBalancer (%d), NodeBalancer (%d) was kept and must be deleted manually", replacement.ID, replaced.ID))
+		return
+	}
+
+	if err = l.deleteReplacedNodeBalancer(ctx, service, replaced, replacement); err != nil {
+		sentry.CaptureError(ctx, err)
+		l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Warning", reason+"Failed", err.Error())
+		return
+	}
+
+	l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Normal", reason+"Completed",
+		fmt.Sprintf("Replaced NodeBalancer (%d) with NodeBalancer (%d)", replaced.ID, replacement.ID))
+}
+
+// deleteReplacedNodeBalancer deletes replaced, and its reserved IPv4 address if it is tagged to
+// be released.
+func (l *loadbalancers) deleteReplacedNodeBalancer(ctx context.Context, service *v1.Service, replaced, replacement *linodego.NodeBalancer) error {
+	serviceNn := getServiceNn(service)
+	if err := l.client.DeleteNodeBalancer(ctx, replaced.ID); err != nil && !linodego.IsNotFound(err) {
+		klog.Errorf("failed to delete NodeBalancer (%d) replaced by NodeBalancer (%d) for service (%s): %s", replaced.ID, replacement.ID, serviceNn, err)
+		return fmt.Errorf("failed to delete NodeBalancer (%d) replaced by NodeBalancer (%d): %w", replaced.ID, replacement.ID, err)
+	}
+	klog.Infof("deleted NodeBalancer (%d) replaced by NodeBalancer (%d) for service (%s)", replaced.ID, replacement.ID, serviceNn)
+
+	if slices.Contains(replaced.Tags, releaseReservedIPv4Tag) && replaced.IPv4 != nil {
+		if err := l.client.DeleteReservedIPAddress(ctx, *replaced.IPv4); err != nil && !linodego.IsNotFound(err) {
+			klog.Errorf("failed to delete reserved IP (%s) of NodeBalancer (%d) for service (%s): %s", *replaced.IPv4, replaced.ID, serviceNn, err)
+			return fmt.Errorf("failed to delete reserved IPv4 address %s of NodeBalancer (%d): %w", *replaced.IPv4, replaced.ID, err)
+		}
+		klog.Infof("deleted reserved IP (%s) of NodeBalancer (%d) for service (%s)", *replaced.IPv4, replaced.ID, serviceNn)
+	}
+	return nil
+}
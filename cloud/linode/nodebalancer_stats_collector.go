@@ -0,0 +1,171 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/appscode/go/wait"
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	v1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// nodeBalancerStatsCollector periodically fetches the statistics and backend health of the
+// NodeBalancers of provisioned LoadBalancer services and exports them as metrics labelled with
+// the namespace and name of the Service.
+//
+// The backend health is only collected without the NodeBalancer health controller, which
+// otherwise exports it for every Service.
+//
+// Every collection costs a few Linode API calls per Service, so at most maxServices Services,
+// in namespace and name order, are collected per interval. This bounds both the API calls and
+// the number of exported series.
+type nodeBalancerStatsCollector struct {
+	loadbalancers *loadbalancers
+	informer      v1informers.ServiceInformer
+
+	interval    time.Duration
+	maxServices int
+	// collectBackends is set when the NodeBalancer health controller does not export the
+	// backend health
+	collectBackends bool
+
+	// collected records the services whose metrics were exported by the last collection
+	collected sets.Set[string]
+}
+
+func newNodeBalancerStatsCollector(loadbalancers *loadbalancers, informer v1informers.ServiceInformer) *nodeBalancerStatsCollector {
+	return &nodeBalancerStatsCollector{
+		loadbalancers:   loadbalancers,
+		informer:        informer,
+		interval:        loadbalancers.options.NodeBalancerStatsInterval,
+		maxServices:     loadbalancers.options.NodeBalancerStatsMaxServices,
+		collectBackends: !loadbalancers.options.EnableNodeBalancerHealth,
+		collected:       sets.New[string](),
+	}
+}
+
+func (c *nodeBalancerStatsCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.informer.Informer().HasSynced) {
+		klog.Error("NodeBalancerStatsCollector failed to sync the service informer")
+		return
+	}
+
+	wait.Until(func() {
+		if err := c.collect(context.Background()); err != nil {
+			klog.Errorf("NodeBalancerStatsCollector failed to collect NodeBalancer statistics: %s", err)
+		}
+	}, c.interval, stopCh)
+}
+
+// collect exports the statistics of the NodeBalancers of up to maxServices services and stops
+// exporting them for services that are no longer collected.
+func (c *nodeBalancerStatsCollector) collect(ctx context.Context) error {
+	serviceList, err := c.informer.Lister().List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	serviceList = slices.DeleteFunc(slices.Clone(serviceList), func(service *v1.Service) bool {
+		return !isProvisionedLoadBalancer(service)
+	})
+	slices.SortFunc(serviceList, func(a, b *v1.Service) int {
+		return strings.Compare(getServiceNn(a), getServiceNn(b))
+	})
+	skipped := 0
+	if c.maxServices > 0 && len(serviceList) > c.maxServices {
+		skipped = len(serviceList) - c.maxServices
+		klog.Warningf("NodeBalancerStatsCollector is limited to %d services, not collecting statistics for %d services", c.maxServices, skipped)
+		serviceList = serviceList[:c.maxServices]
+	}
+	nodeBalancerStatsSkippedServicesGauge.Set(float64(skipped))
+
+	collected := sets.New[string]()
+	for _, service := range serviceList {
+		key := getServiceNn(service)
+		collected.Insert(key)
+		if err := c.collectService(ctx, service); err != nil {
+			klog.Errorf("failed to collect NodeBalancer statistics for service (%s): %s", key, err)
+		}
+	}
+
+	for key := range c.collected.Difference(collected) {
+		namespace, name, _ := strings.Cut(key, "/")
+		service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		forgetNodeBalancerStats(service)
+		if c.collectBackends {
+			forgetNodeBalancerBackends(service)
+		}
+	}
+	c.collected = collected
+	return nil
+}
+
+// collectService exports the statistics and backend health of the NodeBalancer of service.
+func (c *nodeBalancerStatsCollector) collectService(ctx context.Context, service *v1.Service) error {
+	nb, err := c.loadbalancers.getNodeBalancerForService(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	stats, err := c.loadbalancers.client.GetNodeBalancerStats(ctx, nb.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get statistics of NodeBalancer (%d): %w", nb.ID, err)
+	}
+	recordNodeBalancerStats(service, stats)
+	if !c.collectBackends {
+		return nil
+	}
+
+	configs, err := c.loadbalancers.client.ListNodeBalancerConfigs(ctx, nb.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list configs of NodeBalancer (%d): %w", nb.ID, err)
+	}
+	forgetNodeBalancerBackends(service)
+	for _, config := range configs {
+		if config.NodesStatus != nil {
+			recordNodeBalancerBackends(service, &config)
+		}
+	}
+	return nil
+}
+
+// recordNodeBalancerStats exports the latest data points of the statistics of the NodeBalancer
+// of service. Statistics without data points, as for new NodeBalancers, are not exported.
+func recordNodeBalancerStats(service *v1.Service, stats *linodego.NodeBalancerStats) {
+	if value, ok := latestDataPoint(stats.Data.Connections); ok {
+		nodeBalancerConnectionsGaugeVec.WithLabelValues(service.Namespace, service.Name).Set(value)
+	}
+	if value, ok := latestDataPoint(stats.Data.Traffic.In); ok {
+		nodeBalancerTrafficGaugeVec.WithLabelValues(service.Namespace, service.Name, "in").Set(value)
+	}
+	if value, ok := latestDataPoint(stats.Data.Traffic.Out); ok {
+		nodeBalancerTrafficGaugeVec.WithLabelValues(service.Namespace, service.Name, "out").Set(value)
+	}
+}
+
+// forgetNodeBalancerStats stops exporting the statistics of the NodeBalancer of service.
+func forgetNodeBalancerStats(service *v1.Service) {
+	nodeBalancerConnectionsGaugeVec.DeleteLabelValues(service.Namespace, service.Name)
+	nodeBalancerTrafficGaugeVec.DeletePartialMatch(prometheus.Labels{"namespace": service.Namespace, "service": service.Name})
+}
+
+// latestDataPoint returns the value of the latest [timestamp, value] data point of a NodeBalancer
+// statistic, which the Linode API returns in chronological order.
+func latestDataPoint(points [][]float64) (float64, bool) {
+	for i := len(points) - 1; i >= 0; i-- {
+		if len(points[i]) == 2 {
+			return points[i][1], true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+package linode
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_nodeBalancerStatsCollector(t *testing.T) {
+	fakeLinode := newFake(t)
+	kubeClient := fake.NewClientset()
+	lb := newFakeLoadBalancers(t, fakeLinode, kubeClient, &options.Config{})
+
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+	indexer := serviceInformer.Informer().GetIndexer()
+	node := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}}}
+
+	// createService provisions a NodeBalancer for a LoadBalancer service and returns its ID
+	createService := func(name string) (*v1.Service, int) {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000}},
+			},
+		}
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		service.Status.LoadBalancer = *status
+		_, err = kubeClient.CoreV1().Services(service.Namespace).Create(t.Context(), service, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, indexer.Add(service))
+
+		nb, err := lb.getNodeBalancerForService(t.Context(), service)
+		require.NoError(t, err)
+		for _, config := range fakeLinode.nbc {
+			if config.NodeBalancerID == nb.ID {
+				config.NodesStatus = &linodego.NodeBalancerNodeStatus{Up: 2, Down: 1}
+			}
+		}
+		return service, nb.ID
+	}
+
+	alpha, alphaID := createService("alpha")
+	fakeLinode.nbs[strconv.Itoa(alphaID)] = &linodego.NodeBalancerStats{
+		Data: linodego.NodeBalancerStatsData{
+			Connections: [][]float64{{1700000000000, 3}, {1700000300000, 5}},
+			Traffic: linodego.StatsTraffic{
+				In:  [][]float64{{1700000000000, 1000}, {1700000300000, 2000}},
+				Out: [][]float64{{1700000300000, 4000}},
+			},
+		},
+	}
+	beta, _ := createService("beta")
+	gamma, _ := createService("gamma")
+
+	collector := newNodeBalancerStatsCollector(lb, serviceInformer)
+	collector.maxServices = 2
+	require.NoError(t, collector.collect(t.Context()))
+
+	assert.InDelta(t, 5, testutil.ToFloat64(nodeBalancerConnectionsGaugeVec.WithLabelValues("default", "alpha")), 0)
+	assert.InDelta(t, 2000, testutil.ToFloat64(nodeBalancerTrafficGaugeVec.WithLabelValues("default", "alpha", "in")), 0)
+	assert.InDelta(t, 4000, testutil.ToFloat64(nodeBalancerTrafficGaugeVec.WithLabelValues("default", "alpha", "out")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(nodeBalancerBackendsGaugeVec.WithLabelValues("default", "beta", "80", "up")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(nodeBalancerBackendsGaugeVec.WithLabelValues("default", "beta", "80", "down")), 0)
+	// beta has no data points yet and gamma is over the limit
+	assert.Equal(t, 1, testutil.CollectAndCount(nodeBalancerConnectionsGaugeVec))
+	assert.Equal(t, 2, testutil.CollectAndCount(nodeBalancerTrafficGaugeVec))
+	assert.Equal(t, 4, testutil.CollectAndCount(nodeBalancerBackendsGaugeVec))
+	assert.InDelta(t, 1, testutil.ToFloat64(nodeBalancerStatsSkippedServicesGauge), 0)
+
+	// Deleting alpha makes room for gamma
+	require.NoError(t, indexer.Delete(alpha))
+	require.NoError(t, collector.collect(t.Context()))
+	assert.Equal(t, 0, testutil.CollectAndCount(nodeBalancerConnectionsGaugeVec))
+	assert.Equal(t, 0, testutil.CollectAndCount(nodeBalancerTrafficGaugeVec))
+	assert.InDelta(t, 2, testutil.ToFloat64(nodeBalancerBackendsGaugeVec.WithLabelValues("default", "gamma", "80", "up")), 0)
+	assert.Equal(t, 4, testutil.CollectAndCount(nodeBalancerBackendsGaugeVec))
+	assert.InDelta(t, 0, testutil.ToFloat64(nodeBalancerStatsSkippedServicesGauge), 0)
+
+	require.NoError(t, indexer.Delete(beta))
+	require.NoError(t, indexer.Delete(gamma))
+	require.NoError(t, collector.collect(t.Context()))
+	assert.Equal(t, 0, testutil.CollectAndCount(nodeBalancerBackendsGaugeVec))
+
+	// The backend health is left to the health controller when it is enabled
+	lb.options.EnableNodeBalancerHealth = true
+	collector = newNodeBalancerStatsCollector(lb, serviceInformer)
+	require.NoError(t, indexer.Add(beta))
+	recordNodeBalancerBackends(beta, &linodego.NodeBalancerConfig{Port: 80, NodesStatus: &linodego.NodeBalancerNodeStatus{Up: 3}})
+	t.Cleanup(func() { forgetNodeBalancerBackends(beta) })
+	require.NoError(t, collector.collect(t.Context()))
+	require.NoError(t, indexer.Delete(beta))
+	require.NoError(t, collector.collect(t.Context()))
+	assert.InDelta(t, 3, testutil.ToFloat64(nodeBalancerBackendsGaugeVec.WithLabelValues("default", "beta", "80", "up")), 0)
+	assert.Equal(t, 2, testutil.CollectAndCount(nodeBalancerBackendsGaugeVec))
+}
+
+func Test_latestDataPoint(t *testing.T) {
+	value, ok := latestDataPoint([][]float64{{1, 10}, {2, 20}, {3}})
+	assert.True(t, ok)
+	assert.InDelta(t, 20, value, 0)
+
+	_, ok = latestDataPoint(nil)
+	assert.False(t, ok)
+}
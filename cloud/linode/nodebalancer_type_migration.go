@@ -0,0 +1,123 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/linode/linodego/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/sentry"
+)
+
+// nodeBalancerTypeMismatch returns the NodeBalancer type requested by the annotation of service
+// and whether nb is of another type. Services without the annotation never mismatch, so that
+// changing --default-nodebalancer-type does not affect existing NodeBalancers.
+func nodeBalancerTypeMismatch(opts *options.Config, service *v1.Service, nb *linodego.NodeBalancer) (linodego.NodeBalancerPlanType, bool) {
+	if _, ok := service.GetAnnotations()[annotations.AnnLinodeNodeBalancerType]; !ok {
+		return "", false
+	}
+	nbType, _ := parseNodeBalancerType(opts, service)
+	return nbType, nbType != "" && nb.Type != "" && nb.Type != nbType
+}
+
+// nodeBalancerTypeMigrationBlocker returns why nb, the NodeBalancer of service, cannot be migrated
+// to another type, or an empty string if it can.
+func nodeBalancerTypeMigrationBlocker(service *v1.Service, nb *linodego.NodeBalancer) string {
+	allowed := getServiceBoolAnnotation(service, annotations.AnnLinodeNodeBalancerTypeMigration)
+	if allowed == nil || !*allowed {
+		return fmt.Sprintf("annotate the service with %s to migrate it to a new NodeBalancer", annotations.AnnLinodeNodeBalancerTypeMigration)
+	}
+	if _, ok := service.GetAnnotations()[annotations.AnnLinodeNodeBalancerID]; ok {
+		return fmt.Sprintf("NodeBalancers selected with %s are not migrated", annotations.AnnLinodeNodeBalancerID)
+	}
+	if holdsReservedIPv4(service, nb) {
+		downtime := getServiceBoolAnnotation(service, annotations.AnnLinodeNodeBalancerTypeMigrationDowntime)
+		if downtime == nil || !*downtime {
+			return fmt.Sprintf("its reserved IPv4 address %s can only move to a new NodeBalancer once this one is deleted, "+
+				"which makes the service unavailable until the new one is created; annotate the service with %s to accept the downtime",
+				*nb.IPv4, annotations.AnnLinodeNodeBalancerTypeMigrationDowntime)
+		}
+	}
+	return ""
+}
+
+// holdsReservedIPv4 reports whether nb holds the reserved IPv4 address requested by service.
+func holdsReservedIPv4(service *v1.Service, nb *linodego.NodeBalancer) bool {
+	reservedIPv4 := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerReservedIPv4]
+	return reservedIPv4 != "" && nb.IPv4 != nil && reservedIPv4 == *nb.IPv4
+}
+
+// migrateNodeBalancerType replaces nb with a NodeBalancer of the type requested by service and
+// returns the replacement.
+//
+// When nb holds the reserved IPv4 address of service, which nodeBalancerTypeMigrationBlocker only
+// allows with a second opt-in, nb is deleted first so that the address can be assigned to the
+// replacement. Otherwise the replacement is created next to nb, which is
+// released and tagged with the ID of the replacement, and nb is deleted in the background once the
+// Service status references the replacement.
+func (l *loadbalancers) migrateNodeBalancerType(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nodes []*v1.Node,
+	nb *linodego.NodeBalancer,
+) (*linodego.NodeBalancer, error) {
+	serviceNn := getServiceNn(service)
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: service %s", errNoNodesAvailable, serviceNn)
+	}
+	if err := checkNodeBalancerOwnership(clusterName, service, nb); err != nil {
+		return nil, err
+	}
+
+	nbType := l.GetLinodeNBType(service)
+	keepIPv4 := holdsReservedIPv4(service, nb)
+
+	message := fmt.Sprintf("Migrating NodeBalancer (%d) from type %s to %s", nb.ID, nb.Type, nbType)
+	if keepIPv4 {
+		message += fmt.Sprintf(", the NodeBalancer is recreated with reserved IPv4 address %s and is unavailable meanwhile", *nb.IPv4)
+	}
+	klog.Infof("%s for service (%s)", message, serviceNn)
+	l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Normal", "NodeBalancerTypeMigrationStarted", message)
+
+	createService, err := l.carryOverFirewall(ctx, service, nb)
+	if err != nil {
+		return nil, l.failNodeBalancerTypeMigration(ctx, service, nb, err)
+	}
+
+	released := nb
+	if keepIPv4 {
+		if err = l.client.DeleteNodeBalancer(ctx, nb.ID); err != nil {
+			return nil, l.failNodeBalancerTypeMigration(ctx, service, nb, err)
+		}
+	} else if released, err = l.releaseReplacedNodeBalancer(ctx, nb); err != nil {
+		return nil, l.failNodeBalancerTypeMigration(ctx, service, nb, err)
+	}
+
+	replacement, err := l.buildLoadBalancerRequest(ctx, clusterName, createService, nodes)
+	if err != nil {
+		return nil, l.failNodeBalancerTypeMigration(ctx, service, nb, err)
+	}
+	klog.Infof("created NodeBalancer (%d) of type %s to replace NodeBalancer (%d) for service (%s)", replacement.ID, nbType, nb.ID, serviceNn)
+
+	if keepIPv4 {
+		l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Normal", "NodeBalancerTypeMigrationCompleted",
+			fmt.Sprintf("Replaced NodeBalancer (%d) with NodeBalancer (%d) of type %s", nb.ID, replacement.ID, nbType))
+		return replacement, nil
+	}
+
+	l.switchToReplacement(ctx, service, released, replacement, "NodeBalancerTypeMigration", false)
+	return replacement, nil
+}
+
+func (l *loadbalancers) failNodeBalancerTypeMigration(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer, err error) error {
+	klog.Errorf("failed to migrate NodeBalancer (%d) for service (%s): %s", nb.ID, getServiceNn(service), err)
+	sentry.CaptureError(ctx, err)
+	l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Warning", "NodeBalancerTypeMigrationFailed",
+		fmt.Sprintf("Failed to migrate NodeBalancer (%d), the migration is retried: %s", nb.ID, err))
+	return err
+}
@@ -0,0 +1,260 @@
+package linode
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_nodeBalancerTypeMismatch(t *testing.T) {
+	testcases := []struct {
+		name        string
+		annotations map[string]string
+		nbType      linodego.NodeBalancerPlanType
+		mismatch    bool
+	}{
+		{
+			name:   "no annotation",
+			nbType: linodego.NBTypeCommon,
+		},
+		{
+			name:        "same type",
+			annotations: map[string]string{annotations.AnnLinodeNodeBalancerType: "Premium"},
+			nbType:      linodego.NBTypePremium,
+		},
+		{
+			name:        "different type",
+			annotations: map[string]string{annotations.AnnLinodeNodeBalancerType: "premium"},
+			nbType:      linodego.NBTypeCommon,
+			mismatch:    true,
+		},
+		{
+			name:        "unknown NodeBalancer type",
+			annotations: map[string]string{annotations.AnnLinodeNodeBalancerType: "premium"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			_, mismatch := nodeBalancerTypeMismatch(&options.Config{}, service, &linodego.NodeBalancer{Type: tc.nbType})
+			assert.Equal(t, tc.mismatch, mismatch)
+		})
+	}
+}
+
+func Test_migrateNodeBalancerType(t *testing.T) {
+	prevInterval := nodeBalancerReplacementPollInterval
+	defer func() { nodeBalancerReplacementPollInterval = prevInterval }()
+	nodeBalancerReplacementPollInterval = 10 * time.Millisecond
+
+	node := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}}}
+
+	// setup creates a common NodeBalancer for a service that then asks for a premium one
+	setup := func(t *testing.T, extraAnnotations map[string]string) (*fakeAPI, *loadbalancers, *v1.Service, *linodego.NodeBalancer) {
+		t.Helper()
+
+		fakeLinode := newFake(t)
+		kubeClient := fake.NewClientset()
+		lb := newFakeLoadBalancers(t, fakeLinode, kubeClient, &options.Config{})
+
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "web",
+				Namespace:   "default",
+				UID:         types.UID("web-uid"),
+				Annotations: map[string]string{annotations.AnnLinodeNodeBalancerType: "common"},
+			},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000}},
+			},
+		}
+		for key, value := range extraAnnotations {
+			service.Annotations[key] = value
+		}
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		service.Status.LoadBalancer = *status
+		_, err = kubeClient.CoreV1().Services(service.Namespace).Create(t.Context(), service, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		nb, err := lb.getNodeBalancerForService(t.Context(), service)
+		require.NoError(t, err)
+		require.Equal(t, linodego.NBTypeCommon, nb.Type)
+
+		service.Annotations[annotations.AnnLinodeNodeBalancerType] = "premium"
+		return fakeLinode, lb, service, nb
+	}
+
+	t.Run("warns without opt-in", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, nil)
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		assert.Len(t, fakeLinode.nb, 1)
+		assert.Equal(t, linodego.NBTypeCommon, fakeLinode.nb[strconv.Itoa(nb.ID)].Type)
+		assert.Contains(t, eventReasons(t, lb), "NodeBalancerTypeMismatch")
+	})
+
+	t.Run("replaces the NodeBalancer", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, map[string]string{annotations.AnnLinodeNodeBalancerTypeMigration: "true"})
+
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		require.Len(t, fakeLinode.nb, 2)
+		replaced := fakeLinode.nb[strconv.Itoa(nb.ID)]
+		assert.Contains(t, replaced.Tags, "prod")
+		assert.Empty(t, ownerTagsByPrefix(replaced.Tags))
+
+		// Until the status is updated it leads to the replacement
+		replacement, err := lb.getNodeBalancerForService(t.Context(), service)
+		require.NoError(t, err)
+		assert.NotEqual(t, nb.ID, replacement.ID)
+		assert.Equal(t, linodego.NBTypePremium, replacement.Type)
+		assert.Equal(t, lb.GetLoadBalancerTags(t.Context(), "prod", service), replacement.Tags)
+		assert.Equal(t, makeLoadBalancerStatus(lb.options, service, replacement), status)
+
+		service.Status.LoadBalancer = *status
+		_, err = lb.kubeClient.CoreV1().Services(service.Namespace).UpdateStatus(t.Context(), service, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		lb.replacements.Wait()
+
+		assert.NotContains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+		assert.Contains(t, fakeLinode.nb, strconv.Itoa(replacement.ID))
+		assert.Subset(t, eventReasons(t, lb), []string{
+			"NodeBalancerTypeMigrationStarted",
+			"NodeBalancerTypeMigrationSwitching",
+			"NodeBalancerTypeMigrationCompleted",
+		})
+	})
+
+	t.Run("resumes deleting the replaced NodeBalancer on the next reconcile", func(t *testing.T) {
+		prevTimeout := nodeBalancerReplacementTimeout
+		defer func() { nodeBalancerReplacementTimeout = prevTimeout }()
+		nodeBalancerReplacementTimeout = 50 * time.Millisecond
+
+		fakeLinode, lb, service, nb := setup(t, map[string]string{annotations.AnnLinodeNodeBalancerTypeMigration: "true"})
+
+		// The background deletion gives up, as it would be lost when the CCM restarts
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		lb.replacements.Wait()
+		require.Contains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+
+		nodeBalancerReplacementTimeout = prevTimeout
+		service.Status.LoadBalancer = *status
+		_, err = lb.kubeClient.CoreV1().Services(service.Namespace).UpdateStatus(t.Context(), service, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, lb.UpdateLoadBalancer(t.Context(), "prod", service, []*v1.Node{node}))
+		lb.replacements.Wait()
+
+		assert.NotContains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+		assert.Len(t, fakeLinode.nb, 1)
+		assert.Contains(t, eventReasons(t, lb), "NodeBalancerReplacementCompleted")
+	})
+
+	t.Run("deletes the replaced NodeBalancer with the service", func(t *testing.T) {
+		prevTimeout := nodeBalancerReplacementTimeout
+		defer func() { nodeBalancerReplacementTimeout = prevTimeout }()
+		nodeBalancerReplacementTimeout = 50 * time.Millisecond
+
+		fakeLinode, lb, service, _ := setup(t, map[string]string{annotations.AnnLinodeNodeBalancerTypeMigration: "true"})
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		lb.replacements.Wait()
+		require.Len(t, fakeLinode.nb, 2)
+
+		require.NoError(t, lb.EnsureLoadBalancerDeleted(t.Context(), "prod", service))
+		assert.Empty(t, fakeLinode.nb)
+	})
+
+	t.Run("plans the replacement without deleting the NodeBalancer", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, map[string]string{annotations.AnnLinodeNodeBalancerTypeMigration: "true"})
+		recorder := client.NewClientWithDryRun(lb.client)
+		lb.client = recorder
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		lb.replacements.Wait()
+
+		methods := []string{}
+		for _, call := range recorder.Calls() {
+			methods = append(methods, call.Method)
+		}
+		assert.Contains(t, methods, "CreateNodeBalancer")
+		assert.NotContains(t, methods, "DeleteNodeBalancer")
+		assert.Len(t, fakeLinode.nb, 1)
+		assert.Contains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+	})
+
+	t.Run("refuses to recreate the NodeBalancer of a reserved IPv4 address without opt-in", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, map[string]string{
+			annotations.AnnLinodeNodeBalancerTypeMigration: "true",
+			annotations.AnnLinodeLoadBalancerReservedIPv4:  "45.76.1.2",
+		})
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		assert.Len(t, fakeLinode.nb, 1)
+		assert.Equal(t, linodego.NBTypeCommon, fakeLinode.nb[strconv.Itoa(nb.ID)].Type)
+		assert.Contains(t, eventReasons(t, lb), "NodeBalancerTypeMismatch")
+		assert.NotContains(t, eventReasons(t, lb), "NodeBalancerTypeMigrationStarted")
+	})
+
+	t.Run("keeps the reserved IPv4 address", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, map[string]string{
+			annotations.AnnLinodeNodeBalancerTypeMigration:         "true",
+			annotations.AnnLinodeNodeBalancerTypeMigrationDowntime: "true",
+			annotations.AnnLinodeLoadBalancerReservedIPv4:          "45.76.1.2",
+		})
+		require.Equal(t, "45.76.1.2", *nb.IPv4)
+
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		require.Len(t, fakeLinode.nb, 1)
+		assert.NotContains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+		assert.Equal(t, "45.76.1.2", status.Ingress[0].IP)
+		for _, replacement := range fakeLinode.nb {
+			assert.Equal(t, linodego.NBTypePremium, replacement.Type)
+			assert.Equal(t, "45.76.1.2", *replacement.IPv4)
+		}
+		assert.Subset(t, eventReasons(t, lb), []string{"NodeBalancerTypeMigrationStarted", "NodeBalancerTypeMigrationCompleted"})
+	})
+
+	t.Run("refuses NodeBalancers selected by ID", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, map[string]string{annotations.AnnLinodeNodeBalancerTypeMigration: "true"})
+		service.Annotations[annotations.AnnLinodeNodeBalancerID] = strconv.Itoa(nb.ID)
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		assert.Len(t, fakeLinode.nb, 1)
+		assert.Contains(t, eventReasons(t, lb), "NodeBalancerTypeMismatch")
+	})
+}
+
+// eventReasons returns the reasons of the events of the services in the default namespace.
+func eventReasons(t *testing.T, lb *loadbalancers) []string {
+	t.Helper()
+
+	events, err := lb.kubeClient.CoreV1().Events("default").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	reasons := []string{}
+	for _, event := range events.Items {
+		reasons = append(reasons, event.Reason)
+	}
+	return reasons
+}
@@ -36,9 +36,7 @@ import (
 
 const (
 	maxAllowedNodeCIDRsIPv4 = 1
-)
 
-var (
 	// defaultNodeMaskCIDRIPv4 is default mask size for IPv4 node cidr
 	defaultNodeMaskCIDRIPv4 = 24
 	// defaultNodeMaskCIDRIPv6 is default mask size for IPv6 node cidr
@@ -50,12 +48,12 @@ func startNodeIpamController(stopCh <-chan struct{}, cloud *linodeCloud, nodeInf
 	var secondaryServiceCIDR *net.IPNet
 
 	// should we start nodeIPAM
-	if !options.Options.AllocateNodeCIDRs {
+	if !cloud.options.AllocateNodeCIDRs {
 		return nil
 	}
 
 	// failure: bad cidrs in config
-	clusterCIDRs, err := processCIDRs(options.Options.ClusterCIDRIPv4)
+	clusterCIDRs, err := processCIDRs(cloud.options.ClusterCIDRIPv4)
 	if err != nil {
 		return fmt.Errorf("processCIDRs failed: %w", err)
 	}
@@ -72,7 +70,7 @@ func startNodeIpamController(stopCh <-chan struct{}, cloud *linodeCloud, nodeInf
 		return fmt.Errorf("clusterCIDR %s is not ipv4", clusterCIDRs[0].String())
 	}
 
-	nodeCIDRMaskSizes := setNodeCIDRMaskSizes()
+	nodeCIDRMaskSizes := setNodeCIDRMaskSizes(cloud.options)
 
 	ctx := wait.ContextForChannel(stopCh)
 
@@ -80,14 +78,15 @@ func startNodeIpamController(stopCh <-chan struct{}, cloud *linodeCloud, nodeInf
 		ctx,
 		nodeInformer,
 		cloud,
-		cloud.client,
+		cloud.clients.routes,
 		kubeclient,
 		clusterCIDRs,
 		serviceCIDR,
 		secondaryServiceCIDR,
 		nodeCIDRMaskSizes,
 		ipam.CloudAllocatorType,
-		options.Options.DisableIPv6NodeCIDRAllocation,
+		cloud.options,
+		cloud.vpcs,
 	)
 	if err != nil {
 		return err
@@ -110,12 +109,13 @@ func processCIDRs(cidrsList string) ([]*net.IPNet, error) {
 	return cidrs, nil
 }
 
-func setNodeCIDRMaskSizes() []int {
-	if options.Options.NodeCIDRMaskSizeIPv4 != 0 {
-		defaultNodeMaskCIDRIPv4 = options.Options.NodeCIDRMaskSizeIPv4
+func setNodeCIDRMaskSizes(opts *options.Config) []int {
+	maskSizes := []int{defaultNodeMaskCIDRIPv4, defaultNodeMaskCIDRIPv6}
+	if opts.NodeCIDRMaskSizeIPv4 != 0 {
+		maskSizes[0] = opts.NodeCIDRMaskSizeIPv4
 	}
-	if options.Options.NodeCIDRMaskSizeIPv6 != 0 {
-		defaultNodeMaskCIDRIPv6 = options.Options.NodeCIDRMaskSizeIPv6
+	if opts.NodeCIDRMaskSizeIPv6 != 0 {
+		maskSizes[1] = opts.NodeCIDRMaskSizeIPv6
 	}
-	return []int{defaultNodeMaskCIDRIPv4, defaultNodeMaskCIDRIPv6}
+	return maskSizes
 }
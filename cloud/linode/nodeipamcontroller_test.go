@@ -58,19 +58,11 @@ func Test_setNodeCIDRMaskSizes(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			oldCIDRMaskSizeIPv4 := options.Options.NodeCIDRMaskSizeIPv4
-			oldCIDRMaskSizeIPv6 := options.Options.NodeCIDRMaskSizeIPv6
-			defer func() {
-				options.Options.NodeCIDRMaskSizeIPv4 = oldCIDRMaskSizeIPv4
-				options.Options.NodeCIDRMaskSizeIPv6 = oldCIDRMaskSizeIPv6
-			}()
-			if tt.args.ipv4NetMask != 0 {
-				options.Options.NodeCIDRMaskSizeIPv4 = tt.args.ipv4NetMask
+			opts := &options.Config{
+				NodeCIDRMaskSizeIPv4: tt.args.ipv4NetMask,
+				NodeCIDRMaskSizeIPv6: tt.args.ipv6NetMask,
 			}
-			if tt.args.ipv6NetMask != 0 {
-				options.Options.NodeCIDRMaskSizeIPv6 = tt.args.ipv6NetMask
-			}
-			got := setNodeCIDRMaskSizes()
+			got := setNodeCIDRMaskSizes(opts)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("setNodeCIDRMaskSizes() = %v, want %v", got, tt.want)
 			}
@@ -214,14 +206,10 @@ func Test_startNodeIpamController(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		currAllocateNodeCIDRs := options.Options.AllocateNodeCIDRs
-		currClusterCIDR := options.Options.ClusterCIDRIPv4
-		defer func() {
-			options.Options.AllocateNodeCIDRs = currAllocateNodeCIDRs
-			options.Options.ClusterCIDRIPv4 = currClusterCIDR
-		}()
-		options.Options.AllocateNodeCIDRs = tt.args.allocateNodeCIDRs
-		options.Options.ClusterCIDRIPv4 = tt.args.clusterCIDR
+		tt.args.cloud.options = &options.Config{
+			AllocateNodeCIDRs: tt.args.allocateNodeCIDRs,
+			ClusterCIDRIPv4:   tt.args.clusterCIDR,
+		}
 		t.Run(tt.name, func(t *testing.T) {
 			if err := startNodeIpamController(tt.args.stopCh, &tt.args.cloud, tt.args.nodeInformer, tt.args.kubeclient); (err != nil) != tt.wantErr {
 				t.Errorf("startNodeIpamController() error = %v, wantErr %v", err, tt.wantErr)
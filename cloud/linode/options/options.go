@@ -2,15 +2,18 @@ package options
 
 import (
 	"net"
+	"sync/atomic"
+	"time"
 
 	"github.com/spf13/pflag"
 )
 
-// Options is a configuration object for this cloudprovider implementation.
+// Config is the configuration of this cloudprovider implementation.
 // We expect it to be initialized with flags external to this package, likely in
-// main.go
-var Options struct {
+// main.go, and passed to the cloud provider and the components it creates.
+type Config struct {
 	KubeconfigFlag           *pflag.Flag
+	Flags                    *pflag.FlagSet
 	LinodeGoDebug            bool
 	EnableRouteController    bool
 	EnableTokenHealthChecker bool
@@ -23,8 +26,6 @@ var Options struct {
 	BGPNodeSelector                   string
 	IpHolderSuffix                    string
 	LinodeExternalNetwork             *net.IPNet
-	NodeBalancerTags                  []string
-	DefaultNBType                     string
 	NodeBalancerBackendIPv4Subnet     string
 	NodeBalancerBackendIPv4SubnetID   int
 	NodeBalancerBackendIPv4SubnetName string
@@ -38,5 +39,65 @@ var Options struct {
 	NodeCIDRMaskSizeIPv4              int
 	NodeCIDRMaskSizeIPv6              int
 	NodeBalancerPrefix                string
+	NodeBalancerLabelMode             string
+	NodeBalancerLabelTemplate         string
 	LinodeTagFilter                   string
+	EnableServiceWebhook              bool
+	ServiceWebhookPort                int
+	ServiceWebhookCertDir             string
+	ClusterName                       string
+	EnableEndpointAwareBackends       bool
+	TLSSecretNamespaceAllowlist       []string
+	Plan                              bool
+	EnableNodeBalancerGC              bool
+	NodeBalancerGCInterval            time.Duration
+	NodeBalancerGCGracePeriod         time.Duration
+	NodeBalancerGCReportOnly          bool
+	EnableNodeBalancerHealth          bool
+	NodeBalancerHealthInterval        time.Duration
+	EnableNodeBalancerStats           bool
+	NodeBalancerStatsInterval         time.Duration
+	NodeBalancerStatsMaxServices      int
+	LinodeAPIRequestsPerSecond        float64
+	LinodeAPIBurst                    int
+	LinodeAPIMethodRequestsPerSecond  map[string]string
+	LinodeAPIMaxRetries               int
+	LinodeAPIRetryBaseDelay           time.Duration
+	LinodeAPIRetryMaxDelay            time.Duration
+	LinodeAPICacheTTL                 time.Duration
+	LinodeAPIRequestTimeout           time.Duration
+	PrintEffectiveConfig              bool
+	ConfigReloadInterval              time.Duration
+	// Reloadable are the settings that can be changed while the CCM is running, read them with
+	// Current.
+	Reloadable
+
+	reloaded atomic.Pointer[Reloadable]
+}
+
+// Reloadable are the settings of Config that are safe to change without restarting the CCM.
+type Reloadable struct {
+	NodeBalancerTags       []string
+	DefaultNBType          string
+	HostnameOnlyIngress    bool
+	LinodeAPITokenCacheTTL time.Duration
+	RoutesCacheTTL         time.Duration
+	K8sNodeCacheTTL        time.Duration
+	MetadataTTL            time.Duration
+	InstanceCacheTTL       time.Duration
+}
+
+// Current returns the reloadable settings in effect: those passed to the last call of Reload, or
+// the ones of c if the settings were never reloaded.
+func (c *Config) Current() Reloadable {
+	if r := c.reloaded.Load(); r != nil {
+		return *r
+	}
+	return c.Reloadable
+}
+
+// Reload puts r into effect. It is safe to call while other goroutines read the settings with
+// Current.
+func (c *Config) Reload(r Reloadable) {
+	c.reloaded.Store(&r)
 }
@@ -0,0 +1,145 @@
+package linode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+)
+
+const (
+	planActionEnsure = "ensure"
+	planActionDelete = "delete"
+)
+
+// LoadBalancerPlan lists the Linode API writes that reconciling a Service would make.
+type LoadBalancerPlan struct {
+	Service string               `json:"service"`
+	Action  string               `json:"action"`
+	Calls   []client.PlannedCall `json:"calls"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// readOnlyRoundTripper fails every Kubernetes API request that could modify an object, so that
+// events and status updates are not written while planning.
+type readOnlyRoundTripper struct {
+	base http.RoundTripper
+}
+
+func (r readOnlyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return r.base.RoundTrip(req)
+	default:
+		return nil, fmt.Errorf("%s %s is not allowed in plan mode", req.Method, req.URL.Path)
+	}
+}
+
+// PlanLoadBalancers runs the load balancer reconciliation for every LoadBalancer Service against
+// a Linode client that records writes instead of performing them, and writes the planned calls
+// per Service to out as JSON. Neither Linode nor Kubernetes resources are modified.
+func PlanLoadBalancers(ctx context.Context, cloud cloudprovider.Interface, kubeConfig *rest.Config, out io.Writer) error {
+	lcloud, ok := cloud.(*linodeCloud)
+	if !ok {
+		return errors.New("plan mode requires the linode cloud provider")
+	}
+	lb, ok := lcloud.loadbalancers.(*loadbalancers)
+	if !ok {
+		return errors.New("plan mode requires NodeBalancer load balancers")
+	}
+
+	readOnlyConfig := rest.CopyConfig(kubeConfig)
+	readOnlyConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return readOnlyRoundTripper{base: rt}
+	})
+	kubeClient, err := kubernetes.NewForConfig(readOnlyConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(readOnlyConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	recorder := client.NewClientWithDryRun(lcloud.clients.loadBalancers)
+	planner := &loadbalancers{
+		client:        recorder,
+		zone:          lb.zone,
+		options:       lb.options,
+		vpcs:          lb.vpcs,
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+	}
+
+	plans, err := planner.planLoadBalancers(ctx, recorder)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(plans)
+}
+
+// planLoadBalancers reconciles every LoadBalancer Service in turn, collecting the writes that
+// recorder blocked for each of them. l must use recorder as its Linode client.
+func (l *loadbalancers) planLoadBalancers(ctx context.Context, recorder *client.ClientWithDryRun) ([]LoadBalancerPlan, error) {
+	services, err := l.kubeClient.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	nodeList, err := l.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	nodes := make([]*v1.Node, 0, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes = append(nodes, &nodeList.Items[i])
+	}
+	nodes = loadBalancerBackendNodes(nodes)
+
+	sort.Slice(services.Items, func(i, j int) bool {
+		return getServiceNn(&services.Items[i]) < getServiceNn(&services.Items[j])
+	})
+
+	plans := []LoadBalancerPlan{}
+	for i := range services.Items {
+		service := &services.Items[i]
+		// The cloud-provider service controller leaves Services with a load balancer class to other controllers
+		if service.Spec.Type != v1.ServiceTypeLoadBalancer || service.Spec.LoadBalancerClass != nil {
+			continue
+		}
+
+		recorder.Reset()
+		plan := LoadBalancerPlan{Service: getServiceNn(service), Action: planActionEnsure}
+		if service.DeletionTimestamp != nil {
+			plan.Action = planActionDelete
+			err = l.EnsureLoadBalancerDeleted(ctx, l.options.ClusterName, service)
+		} else {
+			_, err = l.EnsureLoadBalancer(ctx, l.options.ClusterName, service, nodes)
+		}
+		if err != nil {
+			klog.Warningf("planning service (%s) failed: %s", plan.Service, err)
+			plan.Error = err.Error()
+		}
+		plan.Calls = recorder.Calls()
+		if plan.Calls == nil {
+			plan.Calls = []client.PlannedCall{}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
@@ -0,0 +1,164 @@
+package linode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_planLoadBalancers(t *testing.T) {
+	fakeLinode := newFake(t)
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       v1.NodeSpec{ProviderID: "linode://1"},
+		Status: v1.NodeStatus{Addresses: []v1.NodeAddress{
+			{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+		}},
+	}
+	newService := func(name string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{},
+			},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{
+					{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000},
+				},
+			},
+		}
+	}
+
+	// Provision a NodeBalancer for the existing service, then change its health check
+	opts := &options.Config{}
+	existing := newService("existing")
+	provisioner := newFakeLoadBalancers(t, fakeLinode, fake.NewClientset(), opts)
+	status, err := provisioner.EnsureLoadBalancer(t.Context(), opts.ClusterName, existing, []*v1.Node{node})
+	require.NoError(t, err)
+	existing.Status.LoadBalancer = *status
+	existing.Annotations[annotations.AnnLinodeHealthCheckInterval] = "10"
+
+	clusterIP := newService("internal")
+	clusterIP.Spec.Type = v1.ServiceTypeClusterIP
+
+	kubeClient := fake.NewClientset(node, existing, newService("new"), clusterIP)
+	planner := newFakeLoadBalancers(t, fakeLinode, kubeClient, opts)
+	recorder := client.NewClientWithDryRun(planner.client)
+	planner.client = recorder
+
+	nodeBalancers := len(fakeLinode.nb)
+	configs := make(map[string]linodego.NodeBalancerConfig, len(fakeLinode.nbc))
+	for id, config := range fakeLinode.nbc {
+		configs[id] = *config
+	}
+
+	plans, err := planner.planLoadBalancers(t.Context(), recorder)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+
+	assert.Equal(t, "default/existing", plans[0].Service)
+	assert.Equal(t, planActionEnsure, plans[0].Action)
+	assert.Empty(t, plans[0].Error)
+	require.Len(t, plans[0].Calls, 1)
+	assert.Equal(t, client.PlannedRebuild, plans[0].Calls[0].Operation)
+	assert.NotNil(t, plans[0].Calls[0].Current)
+	desired, ok := plans[0].Calls[0].Desired.(linodego.NodeBalancerConfigRebuildOptions)
+	require.True(t, ok)
+	assert.Equal(t, 10, desired.CheckInterval)
+
+	assert.Equal(t, "default/new", plans[1].Service)
+	assert.Empty(t, plans[1].Error)
+	require.Len(t, plans[1].Calls, 1)
+	assert.Equal(t, client.PlannedCreate, plans[1].Calls[0].Operation)
+	assert.Equal(t, "CreateNodeBalancer", plans[1].Calls[0].Method)
+
+	// Nothing was written to the Linode API
+	assert.Len(t, fakeLinode.nb, nodeBalancers)
+	for id, config := range fakeLinode.nbc {
+		assert.Equal(t, configs[id], *config)
+	}
+}
+
+func Test_readOnlyRoundTripper(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	httpClient := &http.Client{Transport: readOnlyRoundTripper{base: http.DefaultTransport}}
+
+	resp, err := httpClient.Get(ts.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	_, err = httpClient.Post(ts.URL, "application/json", nil) //nolint:noctx // test only
+	assert.ErrorContains(t, err, "not allowed in plan mode")
+}
+
+func Test_planLoadBalancersReservedIPv4Handover(t *testing.T) {
+	fakeLinode := newFake(t)
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       v1.NodeSpec{ProviderID: "linode://1"},
+		Status: v1.NodeStatus{Addresses: []v1.NodeAddress{
+			{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+		}},
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+			Annotations: map[string]string{
+				annotations.AnnLinodeLoadBalancerReservedIPv4:         "45.76.1.2",
+				annotations.AnnLinodeLoadBalancerReservedIPv4Handover: "true",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeLoadBalancer,
+			Ports: []v1.ServicePort{{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000}},
+		},
+	}
+
+	opts := &options.Config{ClusterName: "prod"}
+	provisioner := newFakeLoadBalancers(t, fakeLinode, fake.NewClientset(), opts)
+	status, err := provisioner.EnsureLoadBalancer(t.Context(), opts.ClusterName, service, []*v1.Node{node})
+	require.NoError(t, err)
+	service.Status.LoadBalancer = *status
+	service.Annotations[annotations.AnnLinodeLoadBalancerReservedIPv4] = "45.76.1.3"
+
+	planner := newFakeLoadBalancers(t, fakeLinode, fake.NewClientset(node, service), opts)
+	recorder := client.NewClientWithDryRun(planner.client)
+	planner.client = recorder
+	nodeBalancers := len(fakeLinode.nb)
+
+	plans, err := planner.planLoadBalancers(t.Context(), recorder)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Empty(t, plans[0].Error)
+
+	// The planned replacement is assumed healthy, so the plan covers the switch to it
+	methods := make([]string, 0, len(plans[0].Calls))
+	var targets []string
+	for _, call := range plans[0].Calls {
+		methods = append(methods, call.Method)
+		targets = append(targets, call.Target)
+	}
+	assert.Contains(t, methods, "CreateNodeBalancer")
+	assert.Contains(t, targets, "nodebalancer -1")
+	assert.Len(t, fakeLinode.nb, nodeBalancers)
+}
@@ -0,0 +1,268 @@
+package linode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/linode/linodego/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/sentry"
+)
+
+// replacementForTagPrefix tags the NodeBalancer created on a new reserved IPv4 address with the ID
+// of the NodeBalancer it is about to replace, until the service is switched to it. Lookups of the
+// NodeBalancer of the service skip NodeBalancers carrying it.
+const replacementForTagPrefix = "ccm-replacement-for:"
+
+var errReplacementNotHealthy = errors.New("replacement NodeBalancer has no healthy backends")
+
+// replacementForTag returns the tag marking a NodeBalancer as the replacement being prepared for
+// the NodeBalancer with id.
+func replacementForTag(id int) string {
+	return replacementForTagPrefix + strconv.Itoa(id)
+}
+
+// isPendingReplacement reports whether nb is a replacement that the service was not switched to.
+func isPendingReplacement(nb *linodego.NodeBalancer) bool {
+	return slices.ContainsFunc(nb.Tags, func(tag string) bool {
+		return strings.HasPrefix(tag, replacementForTagPrefix)
+	})
+}
+
+// reservedIPv4HandoverTarget returns the reserved IPv4 address requested by the annotation of
+// service and whether nb holds another address.
+func reservedIPv4HandoverTarget(service *v1.Service, nb *linodego.NodeBalancer) (string, bool) {
+	ipv4, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerReservedIPv4]
+	return ipv4, ok && ipv4 != "" && nb.IPv4 != nil && ipv4 != *nb.IPv4
+}
+
+// reservedIPv4HandoverBlocker returns why the NodeBalancer of service cannot be replaced by one on
+// a new reserved IPv4 address, or an empty string if it can.
+func reservedIPv4HandoverBlocker(service *v1.Service) string {
+	allowed := getServiceBoolAnnotation(service, annotations.AnnLinodeLoadBalancerReservedIPv4Handover)
+	if allowed == nil || !*allowed {
+		return fmt.Sprintf("annotate the service with %s to move it to a new NodeBalancer", annotations.AnnLinodeLoadBalancerReservedIPv4Handover)
+	}
+	if _, ok := service.GetAnnotations()[annotations.AnnLinodeNodeBalancerID]; ok {
+		return fmt.Sprintf("NodeBalancers selected with %s are not replaced", annotations.AnnLinodeNodeBalancerID)
+	}
+	return ""
+}
+
+// handOverReservedIPv4 moves service from nb to a NodeBalancer on the reserved IPv4 address
+// requested by its annotation, and returns that NodeBalancer once it can serve the service.
+//
+// nb keeps serving, and is updated like any other NodeBalancer of service, until every config of
+// the replacement has a healthy backend. Until then errReplacementNotHealthy is returned so that
+// the service is reconciled again. The replacement is created next to nb with the ownership tags
+// of service and tagged with the ID of nb, which is how later reconciles find it, and carries a
+// temporary label while nb holds that of service. At the switch nb is released, tagged with the ID
+// of the replacement and deleted in the background once the Service status references the
+// replacement, together with its reserved IPv4 address unless the service retains reserved
+// addresses.
+func (l *loadbalancers) handOverReservedIPv4(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nodes []*v1.Node,
+	nb *linodego.NodeBalancer,
+) (*linodego.NodeBalancer, error) {
+	serviceNn := getServiceNn(service)
+	if err := checkNodeBalancerOwnership(clusterName, service, nb); err != nil {
+		return nil, err
+	}
+
+	ipv4, _ := reservedIPv4HandoverTarget(service, nb)
+	if _, err := netip.ParseAddr(ipv4); err != nil {
+		return nil, fmt.Errorf("invalid value %q for %s: %w", ipv4, annotations.AnnLinodeLoadBalancerReservedIPv4, err)
+	}
+
+	// nb keeps serving the service until the switch, so it follows the service meanwhile
+	if err := l.updateNodeBalancer(ctx, clusterName, service, nodes, nb); err != nil {
+		return nil, err
+	}
+
+	replacement, err := l.getPendingReplacement(ctx, clusterName, service, nb, ipv4)
+	if err != nil {
+		return nil, l.failReservedIPv4Handover(ctx, service, nb, err)
+	}
+	if replacement == nil {
+		message := fmt.Sprintf("Creating a NodeBalancer on reserved IPv4 address %s to replace NodeBalancer (%d) on %s", ipv4, nb.ID, *nb.IPv4)
+		klog.Infof("%s for service (%s)", message, serviceNn)
+		l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Normal", "ReservedIPv4HandoverStarted", message)
+
+		if replacement, err = l.createPendingReplacement(ctx, clusterName, service, nodes, nb); err != nil {
+			return nil, l.failReservedIPv4Handover(ctx, service, nb, err)
+		}
+		klog.Infof("created NodeBalancer (%d) on reserved IP (%s) to replace NodeBalancer (%d) for service (%s)", replacement.ID, ipv4, nb.ID, serviceNn)
+	} else if err = l.updateNodeBalancerConfigs(ctx, clusterName, service, nodes, replacement); err != nil {
+		return nil, l.failReservedIPv4Handover(ctx, service, nb, err)
+	}
+
+	healthy, err := l.nodeBalancerBackendsHealthy(ctx, replacement)
+	if err != nil {
+		return nil, err
+	}
+	if !healthy {
+		l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Normal", "ReservedIPv4HandoverWaiting",
+			fmt.Sprintf("Waiting for the backends of NodeBalancer (%d) on %s to become healthy, NodeBalancer (%d) keeps serving the service", replacement.ID, ipv4, nb.ID))
+		return nil, fmt.Errorf("%w: NodeBalancer (%d) for service %s", errReplacementNotHealthy, replacement.ID, serviceNn)
+	}
+
+	// With service labels, nb gives up its label before the replacement takes it over. A reconcile
+	// interrupted before nb is tagged as replaced takes nb back, as the replacement is still pending.
+	released, err := l.releaseReplacedNodeBalancer(ctx, nb)
+	if err != nil {
+		return nil, l.failReservedIPv4Handover(ctx, service, nb, err)
+	}
+	l.switchToReplacement(ctx, service, released, replacement, "ReservedIPv4Handover", !l.shouldRetainReservedIP(service))
+
+	// Updating the replacement drops its marker and gives it the label of service. If this fails,
+	// the next reconcile finds the replacement through the tag of nb and updates it again.
+	if err = l.updateNodeBalancer(ctx, clusterName, service, nodes, replacement); err != nil {
+		return nil, err
+	}
+	return replacement, nil
+}
+
+// getPendingReplacement returns the replacement being prepared for nb on the reserved IPv4
+// address ipv4, or nil if there is none. Replacements on another address, whose handover was
+// redirected by changing the annotation again, are deleted.
+func (l *loadbalancers) getPendingReplacement(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nb *linodego.NodeBalancer,
+	ipv4 string,
+) (*linodego.NodeBalancer, error) {
+	pending, err := l.listPendingReplacements(ctx, clusterName, service, nb)
+	if err != nil {
+		return nil, err
+	}
+
+	var replacement *linodego.NodeBalancer
+	for i := range pending {
+		if replacement == nil && pending[i].IPv4 != nil && *pending[i].IPv4 == ipv4 {
+			replacement = &pending[i]
+			continue
+		}
+		if err = l.deletePendingReplacement(ctx, service, nb, &pending[i]); err != nil {
+			return nil, err
+		}
+	}
+	return replacement, nil
+}
+
+// listPendingReplacements returns the replacements of nb owned by service that the service was
+// not switched to.
+func (l *loadbalancers) listPendingReplacements(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nb *linodego.NodeBalancer,
+) ([]linodego.NodeBalancer, error) {
+	filter, err := json.Marshal(map[string]string{"tags": replacementForTag(nb.ID)})
+	if err != nil {
+		return nil, err
+	}
+	lbs, err := l.client.ListNodeBalancers(ctx, linodego.NewListOptions(0, string(filter)))
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(lbs, func(candidate linodego.NodeBalancer) bool {
+		return !ownsNodeBalancer(clusterName, service, &candidate)
+	}), nil
+}
+
+// createPendingReplacement creates the replacement of nb for service. It is tagged as pending and,
+// with service labels, gets a temporary label, as nb holds that of service until the switch.
+func (l *loadbalancers) createPendingReplacement(
+	ctx context.Context,
+	clusterName string,
+	service *v1.Service,
+	nodes []*v1.Node,
+	nb *linodego.NodeBalancer,
+) (*linodego.NodeBalancer, error) {
+	createService, err := l.carryOverFirewall(ctx, service, nb)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := l.buildNodeBalancerConfigCreateOptions(ctx, clusterName, createService, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	label := l.GetLoadBalancerName(ctx, clusterName, createService)
+	if l.options.NodeBalancerLabelMode == NodeBalancerLabelModeService {
+		label = timestampNodeBalancerLabel(l.options)
+	}
+	tags := append(l.GetLoadBalancerTags(ctx, clusterName, createService), replacementForTag(nb.ID))
+	return l.createLabeledNodeBalancer(ctx, createService, label, tags, configs)
+}
+
+// deleteAbandonedReplacements deletes the replacements of nb that were being prepared for a
+// reserved IPv4 handover service no longer asks for, because its annotation was reverted, the
+// handover opt-in was removed or the service is being deleted.
+func (l *loadbalancers) deleteAbandonedReplacements(ctx context.Context, clusterName string, service *v1.Service, nb *linodego.NodeBalancer) error {
+	pending, err := l.listPendingReplacements(ctx, clusterName, service, nb)
+	if err != nil {
+		return err
+	}
+	for i := range pending {
+		if err = l.deletePendingReplacement(ctx, service, nb, &pending[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePendingReplacement deletes replacement, which the service was not switched to. Its
+// reserved IPv4 address was requested through the annotation and is kept.
+func (l *loadbalancers) deletePendingReplacement(ctx context.Context, service *v1.Service, nb, replacement *linodego.NodeBalancer) error {
+	if err := l.client.DeleteNodeBalancer(ctx, replacement.ID); err != nil && !linodego.IsNotFound(err) {
+		return fmt.Errorf("failed to delete NodeBalancer (%d) prepared to replace NodeBalancer (%d): %w", replacement.ID, nb.ID, err)
+	}
+	klog.Infof("deleted NodeBalancer (%d) prepared to replace NodeBalancer (%d) for service (%s)", replacement.ID, nb.ID, getServiceNn(service))
+	l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Normal", "ReservedIPv4HandoverAbandoned",
+		fmt.Sprintf("Deleted NodeBalancer (%d) prepared to replace NodeBalancer (%d), the service no longer asks for its address", replacement.ID, nb.ID))
+	return nil
+}
+
+// nodeBalancerBackendsHealthy returns whether every config of nb has a backend that passes its
+// health checks. A replacement created by a plan has no health checks yet and is assumed to pass
+// them, so that the plan shows the switch.
+func (l *loadbalancers) nodeBalancerBackendsHealthy(ctx context.Context, nb *linodego.NodeBalancer) (bool, error) {
+	if recorder, dryRun := l.client.(*client.ClientWithDryRun); dryRun && recorder.IsPlannedNodeBalancer(nb.ID) {
+		return true, nil
+	}
+
+	configs, err := l.client.ListNodeBalancerConfigs(ctx, nb.ID, nil)
+	if err != nil {
+		return false, err
+	}
+	for _, config := range configs {
+		if config.NodesStatus == nil || config.NodesStatus.Up == 0 {
+			klog.V(3).Infof("NodeBalancer (%d) has no healthy backends on port %d", nb.ID, config.Port)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (l *loadbalancers) failReservedIPv4Handover(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer, err error) error {
+	klog.Errorf("failed to replace NodeBalancer (%d) for service (%s): %s", nb.ID, getServiceNn(service), err)
+	sentry.CaptureError(ctx, err)
+	l.createServiceEvent(ctx, service, eventNodeBalancerReplacementName, "Warning", "ReservedIPv4HandoverFailed",
+		fmt.Sprintf("Failed to replace NodeBalancer (%d), the handover is retried: %s", nb.ID, err))
+	return err
+}
@@ -0,0 +1,217 @@
+package linode
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_handOverReservedIPv4(t *testing.T) {
+	prevInterval := nodeBalancerReplacementPollInterval
+	defer func() { nodeBalancerReplacementPollInterval = prevInterval }()
+	nodeBalancerReplacementPollInterval = 10 * time.Millisecond
+
+	node := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}}}
+
+	// setup creates a NodeBalancer on a reserved IPv4 address for a service that then asks for another one
+	setup := func(t *testing.T, opts *options.Config, extraAnnotations map[string]string) (*fakeAPI, *loadbalancers, *v1.Service, *linodego.NodeBalancer) {
+		t.Helper()
+
+		fakeLinode := newFake(t)
+		kubeClient := fake.NewClientset()
+		lb := newFakeLoadBalancers(t, fakeLinode, kubeClient, opts)
+
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "web",
+				Namespace:   "default",
+				UID:         types.UID("web-uid"),
+				Annotations: map[string]string{annotations.AnnLinodeLoadBalancerReservedIPv4: "45.76.1.2"},
+			},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{{Name: "http", Protocol: "TCP", Port: 80, NodePort: 30000}},
+			},
+		}
+		for key, value := range extraAnnotations {
+			service.Annotations[key] = value
+		}
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		service.Status.LoadBalancer = *status
+		_, err = kubeClient.CoreV1().Services(service.Namespace).Create(t.Context(), service, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		nb, err := lb.getNodeBalancerForService(t.Context(), service)
+		require.NoError(t, err)
+
+		service.Annotations[annotations.AnnLinodeLoadBalancerReservedIPv4] = "45.76.1.3"
+		return fakeLinode, lb, service, nb
+	}
+
+	setHealthy := func(fakeLinode *fakeAPI, nodeBalancerID int) {
+		for _, config := range fakeLinode.nbc {
+			if config.NodeBalancerID == nodeBalancerID {
+				config.NodesStatus = &linodego.NodeBalancerNodeStatus{Up: 1}
+			}
+		}
+	}
+
+	otherNodeBalancer := func(t *testing.T, fakeLinode *fakeAPI, nb *linodego.NodeBalancer) *linodego.NodeBalancer {
+		t.Helper()
+
+		require.Len(t, fakeLinode.nb, 2)
+		for _, candidate := range fakeLinode.nb {
+			if candidate.ID != nb.ID {
+				return candidate
+			}
+		}
+		return nil
+	}
+
+	handOver := map[string]string{
+		annotations.AnnLinodeLoadBalancerReservedIPv4Handover: "true",
+		annotations.AnnLinodeLoadBalancerRetainReservedIPv4:   "false",
+	}
+
+	t.Run("hands over once the replacement is healthy", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, &options.Config{}, handOver)
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.ErrorIs(t, err, errReplacementNotHealthy)
+		replacement := otherNodeBalancer(t, fakeLinode, nb)
+		assert.Equal(t, "45.76.1.3", *replacement.IPv4)
+		assert.Equal(t, append(lb.GetLoadBalancerTags(t.Context(), "prod", service), replacementForTag(nb.ID)), replacement.Tags)
+
+		// The old NodeBalancer keeps serving, and its ownership tags, until the replacement is healthy
+		assert.Equal(t, lb.GetLoadBalancerTags(t.Context(), "prod", service), fakeLinode.nb[strconv.Itoa(nb.ID)].Tags)
+		current, err := lb.getNodeBalancerForService(t.Context(), service)
+		require.NoError(t, err)
+		assert.Equal(t, nb.ID, current.ID)
+		_, err = lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.ErrorIs(t, err, errReplacementNotHealthy)
+		assert.Equal(t, replacement.ID, otherNodeBalancer(t, fakeLinode, nb).ID)
+		setHealthy(fakeLinode, replacement.ID)
+
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		assert.Equal(t, "45.76.1.3", status.Ingress[0].IP)
+		assert.Equal(t, lb.GetLoadBalancerTags(t.Context(), "prod", service), fakeLinode.nb[strconv.Itoa(replacement.ID)].Tags)
+		assert.Empty(t, ownerTagsByPrefix(fakeLinode.nb[strconv.Itoa(nb.ID)].Tags))
+
+		service.Status.LoadBalancer = *status
+		_, err = lb.kubeClient.CoreV1().Services(service.Namespace).UpdateStatus(t.Context(), service, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		lb.replacements.Wait()
+
+		assert.NotContains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+		assert.Contains(t, fakeLinode.nb, strconv.Itoa(replacement.ID))
+		assert.True(t, fakeLinode.didRequestOccur(http.MethodDelete, "/networking/reserved/ips/45.76.1.2", ""))
+		assert.Subset(t, eventReasons(t, lb), []string{
+			"ReservedIPv4HandoverStarted",
+			"ReservedIPv4HandoverWaiting",
+			"ReservedIPv4HandoverSwitching",
+			"ReservedIPv4HandoverCompleted",
+		})
+	})
+
+	t.Run("updates the old NodeBalancer and swaps labels at the switch", func(t *testing.T) {
+		opts := &options.Config{
+			NodeBalancerPrefix:        "ccm",
+			NodeBalancerLabelMode:     NodeBalancerLabelModeService,
+			NodeBalancerLabelTemplate: DefaultNodeBalancerLabelTemplate,
+		}
+		fakeLinode, lb, service, nb := setup(t, opts, handOver)
+		label := lb.GetLoadBalancerName(t.Context(), "prod", service)
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.ErrorIs(t, err, errReplacementNotHealthy)
+		replacement := otherNodeBalancer(t, fakeLinode, nb)
+		assert.Equal(t, label, *fakeLinode.nb[strconv.Itoa(nb.ID)].Label)
+		assert.NotEqual(t, label, *replacement.Label)
+
+		// Backends added during the handover reach the NodeBalancer that still serves the service
+		other := &v1.Node{Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.2"}}}}
+		_, err = lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node, other})
+		require.ErrorIs(t, err, errReplacementNotHealthy)
+		configs, err := lb.client.ListNodeBalancerConfigs(t.Context(), nb.ID, nil)
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+		backends, err := lb.client.ListNodeBalancerNodes(t.Context(), nb.ID, configs[0].ID, nil)
+		require.NoError(t, err)
+		assert.Len(t, backends, 2)
+
+		setHealthy(fakeLinode, replacement.ID)
+		_, err = lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node, other})
+		require.NoError(t, err)
+		assert.Equal(t, label, *fakeLinode.nb[strconv.Itoa(replacement.ID)].Label)
+		assert.NotEqual(t, label, *fakeLinode.nb[strconv.Itoa(nb.ID)].Label)
+	})
+
+	t.Run("deletes the replacement when the handover is abandoned", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, &options.Config{}, handOver)
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.ErrorIs(t, err, errReplacementNotHealthy)
+		replacement := otherNodeBalancer(t, fakeLinode, nb)
+
+		service.Annotations[annotations.AnnLinodeLoadBalancerReservedIPv4] = "45.76.1.2"
+		status, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		assert.Equal(t, "45.76.1.2", status.Ingress[0].IP)
+		assert.NotContains(t, fakeLinode.nb, strconv.Itoa(replacement.ID))
+		assert.Contains(t, fakeLinode.nb, strconv.Itoa(nb.ID))
+		assert.Contains(t, eventReasons(t, lb), "ReservedIPv4HandoverAbandoned")
+	})
+
+	t.Run("deletes the replacement along with the service", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, &options.Config{}, handOver)
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.ErrorIs(t, err, errReplacementNotHealthy)
+		replacement := otherNodeBalancer(t, fakeLinode, nb)
+
+		require.NoError(t, lb.EnsureLoadBalancerDeleted(t.Context(), "prod", service))
+		assert.Empty(t, fakeLinode.nb)
+		assert.False(t, fakeLinode.didRequestOccur(http.MethodDelete, "/networking/reserved/ips/"+*replacement.IPv4, ""))
+	})
+
+	t.Run("does not take over an unowned NodeBalancer on the new address", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, &options.Config{}, handOver)
+		fakeLinode.nb["1"] = &linodego.NodeBalancer{
+			ID:       1,
+			Label:    ptr.To("unrelated"),
+			IPv4:     ptr.To("45.76.1.3"),
+			Hostname: ptr.To("1.nodebalancer.linode.com"),
+		}
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.ErrorIs(t, err, errReplacementNotHealthy)
+		assert.Len(t, fakeLinode.nb, 3)
+		assert.Empty(t, fakeLinode.nb["1"].Tags)
+		assert.Equal(t, lb.GetLoadBalancerTags(t.Context(), "prod", service), fakeLinode.nb[strconv.Itoa(nb.ID)].Tags)
+	})
+
+	t.Run("ignores the change without opt-in", func(t *testing.T) {
+		fakeLinode, lb, service, nb := setup(t, &options.Config{}, nil)
+
+		_, err := lb.EnsureLoadBalancer(t.Context(), "prod", service, []*v1.Node{node})
+		require.NoError(t, err)
+		assert.Len(t, fakeLinode.nb, 1)
+		assert.Equal(t, "45.76.1.2", *fakeLinode.nb[strconv.Itoa(nb.ID)].IPv4)
+		assert.Contains(t, eventReasons(t, lb), "NodeBalancerIPChangeIgnored")
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,13 +22,23 @@ import (
 	ccmUtils "github.com/linode/linode-cloud-controller-manager/cloud/linode/utils"
 )
 
-var ipv6ConfiguredRoutes []*cloudprovider.Route
+const defaultRoutesCacheTTL = 60 * time.Second
 
 type routeCache struct {
 	Mu         sync.RWMutex
 	routes     map[int][]linodego.VPCIP
 	lastUpdate time.Time
-	ttl        time.Duration
+	options    *options.Config
+	vpcs       *services.VPCCache
+}
+
+// ttl returns how long the cached routes are used, which can change when the configuration is
+// reloaded
+func (rc *routeCache) ttl() time.Duration {
+	if ttl := rc.options.Current().RoutesCacheTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultRoutesCacheTTL
 }
 
 // RefreshCache checks if cache has expired and updates it accordingly
@@ -37,17 +46,17 @@ func (rc *routeCache) refreshRoutes(ctx context.Context, client client.Client) {
 	rc.Mu.Lock()
 	defer rc.Mu.Unlock()
 
-	if time.Since(rc.lastUpdate) < rc.ttl {
+	if time.Since(rc.lastUpdate) < rc.ttl() {
 		return
 	}
 
 	vpcNodes := map[int][]linodego.VPCIP{}
-	for _, v := range options.Options.VPCNames {
+	for _, v := range rc.options.VPCNames {
 		vpcName := strings.TrimSpace(v)
 		if vpcName == "" {
 			continue
 		}
-		resp, err := services.GetVPCIPAddresses(ctx, client, vpcName)
+		resp, err := rc.vpcs.GetVPCIPAddresses(ctx, client, vpcName)
 		if err != nil {
 			klog.Errorf("failed updating cache for VPC %s. Error: %s", vpcName, err.Error())
 			continue
@@ -64,28 +73,29 @@ func (rc *routeCache) refreshRoutes(ctx context.Context, client client.Client) {
 type routes struct {
 	client     client.Client
 	instances  *services.Instances
+	vpcs       *services.VPCCache
+	k8sNodes   *k8sNodeCache
 	routeCache *routeCache
-}
 
-func newRoutes(client client.Client, instanceCache *services.Instances) (cloudprovider.Routes, error) {
-	timeout := 60
-	if raw, ok := os.LookupEnv("LINODE_ROUTES_CACHE_TTL_SECONDS"); ok {
-		if t, err := strconv.Atoi(raw); t > 0 && err == nil {
-			timeout = t
-		}
-	}
-	klog.V(3).Infof("TTL for routeCache set to %d seconds", timeout)
+	ipv6RoutesMu sync.Mutex
+	// ipv6Routes are the IPv6 routes reported as configured without configuring them
+	ipv6Routes []*cloudprovider.Route
+}
 
-	if options.Options.EnableRouteController && len(options.Options.VPCNames) == 0 {
+func newRoutes(client client.Client, instanceCache *services.Instances, opts *options.Config, vpcs *services.VPCCache, k8sNodes *k8sNodeCache) (cloudprovider.Routes, error) {
+	if opts.EnableRouteController && len(opts.VPCNames) == 0 {
 		return nil, fmt.Errorf("cannot enable route controller as vpc-names is empty")
 	}
 
 	return &routes{
 		client:    client,
 		instances: instanceCache,
+		vpcs:      vpcs,
+		k8sNodes:  k8sNodes,
 		routeCache: &routeCache{
-			routes: make(map[int][]linodego.VPCIP, 0),
-			ttl:    time.Duration(timeout) * time.Second,
+			routes:  make(map[int][]linodego.VPCIP, 0),
+			options: opts,
+			vpcs:    vpcs,
 		},
 	}, nil
 }
@@ -118,7 +128,7 @@ func (r *routes) getInstanceFromName(ctx context.Context, name string) (*linodeg
 	}
 
 	// fetch providerID from k8s node cache if it exists
-	if id, ok := registeredK8sNodeCache.getProviderID(name); ok {
+	if id, ok := r.k8sNodes.getProviderID(name); ok {
 		node.Spec.ProviderID = id
 	}
 
@@ -140,7 +150,9 @@ func (r *routes) CreateRoute(ctx context.Context, clusterName string, nameHint s
 	}
 	if ipAddr.To4() == nil {
 		// "configure" the route so route controller doesn't keep creating it
-		ipv6ConfiguredRoutes = append(ipv6ConfiguredRoutes, route)
+		r.ipv6RoutesMu.Lock()
+		defer r.ipv6RoutesMu.Unlock()
+		r.ipv6Routes = append(r.ipv6Routes, route)
 		return nil
 	}
 
@@ -160,7 +172,7 @@ func (r *routes) CreateRoute(ctx context.Context, clusterName string, nameHint s
 	linodeInterfaceRoutes := []linodego.VPCInterfaceIPv4RangeCreateOptions{}
 	intfVPCIP := linodego.VPCIP{}
 
-	for _, vpcid := range services.GetAllVPCIDs() {
+	for _, vpcid := range r.vpcs.GetAllVPCIDs() {
 		for _, ir := range instanceRoutes {
 			if ir.VPCID != vpcid {
 				continue
@@ -212,7 +224,7 @@ func (r *routes) DeleteRoute(ctx context.Context, clusterName string, route *clo
 	linodeInterfaceRoutes := []linodego.VPCInterfaceIPv4RangeCreateOptions{}
 	intfVPCIP := linodego.VPCIP{}
 
-	for _, vpcid := range services.GetAllVPCIDs() {
+	for _, vpcid := range r.vpcs.GetAllVPCIDs() {
 		for _, ir := range instanceRoutes {
 			if ir.VPCID != vpcid {
 				continue
@@ -285,7 +297,7 @@ func (r *routes) ListRoutes(ctx context.Context, clusterName string) ([]*cloudpr
 	var configuredRoutes []*cloudprovider.Route
 	for _, instance := range instances {
 		providerID := ccmUtils.ProviderIDPrefix + strconv.Itoa(instance.ID)
-		label, found := registeredK8sNodeCache.getNodeLabel(providerID, instance.Label)
+		label, found := r.k8sNodes.getNodeLabel(providerID, instance.Label)
 		if !found {
 			klog.V(4).Infof("Node %s not found in k8s node cache, skipping listing its routes", instance.Label)
 			continue
@@ -300,7 +312,7 @@ func (r *routes) ListRoutes(ctx context.Context, clusterName string) ([]*cloudpr
 		}
 
 		// check for configured routes
-		for _, vpcid := range services.GetAllVPCIDs() {
+		for _, vpcid := range r.vpcs.GetAllVPCIDs() {
 			for _, ir := range instanceRoutes {
 				if ir.Address != nil || ir.VPCID != vpcid {
 					continue
@@ -318,7 +330,9 @@ func (r *routes) ListRoutes(ctx context.Context, clusterName string) ([]*cloudpr
 	}
 
 	// add in "configured" IPv6 routes so cloud-provider is happy
-	configuredRoutes = append(configuredRoutes, ipv6ConfiguredRoutes...)
+	r.ipv6RoutesMu.Lock()
+	configuredRoutes = append(configuredRoutes, r.ipv6Routes...)
+	r.ipv6RoutesMu.Unlock()
 
 	return configuredRoutes, nil
 }
@@ -23,12 +23,12 @@ import (
 )
 
 func TestListRoutes(t *testing.T) {
-	options.Options.VPCNames = []string{"test", "abc"}
-	services.VpcIDs["test"] = 1
-	services.VpcIDs["abc"] = 2
-	options.Options.SubnetNames = []string{"default"}
-	services.SubnetIDs["default"] = 1
-	options.Options.EnableRouteController = true
+	opts := &options.Config{VPCNames: []string{"test", "abc"}, SubnetNames: []string{"default"}, EnableRouteController: true}
+	testVPCID, abcVPCID := 1, 2
+	vpcs := services.NewVPCCache(opts)
+	vpcs.SetVPCID("test", testVPCID)
+	vpcs.SetVPCID("abc", abcVPCID)
+	vpcs.SetSubnetID("default", 1)
 
 	nodeID := 123
 	name := "mock-instance"
@@ -50,8 +50,8 @@ func TestListRoutes(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, nil)
@@ -75,8 +75,8 @@ func TestListRoutes(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -92,7 +92,7 @@ func TestListRoutes(t *testing.T) {
 		{
 			Address:      &vpcIP,
 			AddressRange: nil,
-			VPCID:        services.VpcIDs["test"],
+			VPCID:        testVPCID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
@@ -103,8 +103,10 @@ func TestListRoutes(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		k8sNodes := newK8sNodeCache(opts)
+		k8sNodes.addNodeToCache(node)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, k8sNodes)
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -121,21 +123,21 @@ func TestListRoutes(t *testing.T) {
 		{
 			Address:      &vpcIP,
 			AddressRange: nil,
-			VPCID:        services.VpcIDs["test"],
+			VPCID:        testVPCID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
 		{
 			Address:      nil,
 			AddressRange: &addressRange1,
-			VPCID:        services.VpcIDs["test"],
+			VPCID:        testVPCID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
 		{
 			Address:      nil,
 			AddressRange: &addressRange2,
-			VPCID:        services.VpcIDs["test"],
+			VPCID:        testVPCID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
@@ -146,14 +148,10 @@ func TestListRoutes(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		existingK8sCache := registeredK8sNodeCache
-		defer func() {
-			registeredK8sNodeCache = existingK8sCache
-		}()
-		registeredK8sNodeCache = newK8sNodeCache()
-		registeredK8sNodeCache.addNodeToCache(node)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		k8sNodes := newK8sNodeCache(opts)
+		k8sNodes.addNodeToCache(node)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, k8sNodes)
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -195,14 +193,10 @@ func TestListRoutes(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		existingK8sCache := registeredK8sNodeCache
-		defer func() {
-			registeredK8sNodeCache = existingK8sCache
-		}()
-		registeredK8sNodeCache = newK8sNodeCache()
-		registeredK8sNodeCache.addNodeToCache(node)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		k8sNodes := newK8sNodeCache(opts)
+		k8sNodes.addNodeToCache(node)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, k8sNodes)
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -218,14 +212,10 @@ func TestListRoutes(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		existingK8sCache := registeredK8sNodeCache
-		defer func() {
-			registeredK8sNodeCache = existingK8sCache
-		}()
-		registeredK8sNodeCache = newK8sNodeCache()
-		registeredK8sNodeCache.addNodeToCache(node)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		k8sNodes := newK8sNodeCache(opts)
+		k8sNodes.addNodeToCache(node)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, k8sNodes)
 		require.NoError(t, err)
 
 		vpcIP2 := "10.0.0.3"
@@ -256,35 +246,35 @@ func TestListRoutes(t *testing.T) {
 			{
 				Address:      &vpcIP2,
 				AddressRange: nil,
-				VPCID:        services.VpcIDs["abc"],
+				VPCID:        abcVPCID,
 				NAT1To1:      nil,
 				LinodeID:     instance2ID,
 			},
 			{
 				Address:      nil,
 				AddressRange: &addressRange3,
-				VPCID:        services.VpcIDs["abc"],
+				VPCID:        abcVPCID,
 				NAT1To1:      nil,
 				LinodeID:     instance2ID,
 			},
 			{
 				Address:      nil,
 				AddressRange: &addressRange4,
-				VPCID:        services.VpcIDs["abc"],
+				VPCID:        abcVPCID,
 				NAT1To1:      nil,
 				LinodeID:     instance2ID,
 			},
 			{
 				Address:      &vpcIP2,
 				AddressRange: nil,
-				VPCID:        services.VpcIDs["abc"],
+				VPCID:        abcVPCID,
 				NAT1To1:      nil,
 				LinodeID:     instance3ID,
 			},
 			{
 				Address:      nil,
 				AddressRange: &addressRange5,
-				VPCID:        services.VpcIDs["abc"],
+				VPCID:        abcVPCID,
 				NAT1To1:      nil,
 				LinodeID:     instance3ID,
 			},
@@ -299,7 +289,7 @@ func TestListRoutes(t *testing.T) {
 			Spec: v1.NodeSpec{ProviderID: ccmUtils.ProviderIDPrefix + strconv.Itoa(instance2ID)},
 		}
 
-		registeredK8sNodeCache.addNodeToCache(node2)
+		k8sNodes.addNodeToCache(node2)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance, validInstance2, validInstance3}, nil)
 		c1 := client.EXPECT().ListVPCIPAddresses(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(routesInVPC, nil)
@@ -324,9 +314,10 @@ func TestListRoutes(t *testing.T) {
 
 func TestCreateRoute(t *testing.T) {
 	ctx := t.Context()
-	options.Options.VPCNames = []string{"dummy"}
-	services.VpcIDs["dummy"] = 1
-	options.Options.EnableRouteController = true
+	opts := &options.Config{VPCNames: []string{"dummy"}, EnableRouteController: true}
+	vpcID := 1
+	vpcs := services.NewVPCCache(opts)
+	vpcs.SetVPCID("dummy", vpcID)
 
 	nodeID := 123
 	name := "mock-instance"
@@ -355,14 +346,14 @@ func TestCreateRoute(t *testing.T) {
 		{
 			Address:      &vpcIP,
 			AddressRange: nil,
-			VPCID:        services.VpcIDs["dummy"],
+			VPCID:        vpcID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
 	}
 
 	instanceConfigIntfWithVPCAndRoute := linodego.InstanceConfigInterface{
-		VPCID:    ptr.To(services.VpcIDs["dummy"]),
+		VPCID:    ptr.To(vpcID),
 		IPv4:     &linodego.VPCIPv4{VPC: vpcIP},
 		IPRanges: []string{"10.10.10.0/24"},
 	}
@@ -376,14 +367,10 @@ func TestCreateRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		existingK8sCache := registeredK8sNodeCache
-		defer func() {
-			registeredK8sNodeCache = existingK8sCache
-		}()
-		registeredK8sNodeCache = newK8sNodeCache()
-		registeredK8sNodeCache.addNodeToCache(node)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		k8sNodes := newK8sNodeCache(opts)
+		k8sNodes.addNodeToCache(node)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, k8sNodes)
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -395,7 +382,7 @@ func TestCreateRoute(t *testing.T) {
 	})
 
 	interfaceWithVPCAndRoute := linodego.LinodeInterface{
-		ID: services.VpcIDs["dummy"],
+		ID: vpcID,
 		VPC: &linodego.VPCInterface{
 			IPv4: linodego.VPCInterfaceIPv4{
 				Ranges: []linodego.VPCInterfaceIPv4Range{{Range: "10.10.10.0/24"}},
@@ -407,14 +394,10 @@ func TestCreateRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		existingK8sCache := registeredK8sNodeCache
-		defer func() {
-			registeredK8sNodeCache = existingK8sCache
-		}()
-		registeredK8sNodeCache = newK8sNodeCache()
-		registeredK8sNodeCache.addNodeToCache(node)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		k8sNodes := newK8sNodeCache(opts)
+		k8sNodes.addNodeToCache(node)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, k8sNodes)
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -435,8 +418,8 @@ func TestCreateRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		err = routeController.CreateRoute(ctx, "dummy", "dummy", v6Route)
@@ -452,8 +435,8 @@ func TestCreateRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		err = routeController.CreateRoute(ctx, "dummy", "dummy", badV6Route)
@@ -465,14 +448,14 @@ func TestCreateRoute(t *testing.T) {
 		{
 			Address:      &vpcIP,
 			AddressRange: nil,
-			VPCID:        services.VpcIDs["dummy"],
+			VPCID:        vpcID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
 		{
 			Address:      nil,
 			AddressRange: &addressRange1,
-			VPCID:        services.VpcIDs["dummy"],
+			VPCID:        vpcID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
@@ -482,8 +465,8 @@ func TestCreateRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -497,8 +480,8 @@ func TestCreateRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, nil)
@@ -510,9 +493,10 @@ func TestCreateRoute(t *testing.T) {
 }
 
 func TestDeleteRoute(t *testing.T) {
-	options.Options.VPCNames = []string{"dummy"}
-	services.VpcIDs["dummy"] = 1
-	options.Options.EnableRouteController = true
+	opts := &options.Config{VPCNames: []string{"dummy"}, EnableRouteController: true}
+	vpcID := 1
+	vpcs := services.NewVPCCache(opts)
+	vpcs.SetVPCID("dummy", vpcID)
 
 	ctx := t.Context()
 
@@ -541,8 +525,8 @@ func TestDeleteRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, nil)
@@ -557,14 +541,14 @@ func TestDeleteRoute(t *testing.T) {
 		{
 			Address:      &vpcIP,
 			AddressRange: nil,
-			VPCID:        services.VpcIDs["dummy"],
+			VPCID:        vpcID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
 	}
 
 	instanceConfigIntfWithVPCAndNoRoute := linodego.InstanceConfigInterface{
-		VPCID:    ptr.To(services.VpcIDs["dummy"]),
+		VPCID:    ptr.To(vpcID),
 		IPv4:     &linodego.VPCIPv4{VPC: vpcIP},
 		IPRanges: []string{},
 	}
@@ -573,8 +557,8 @@ func TestDeleteRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -586,7 +570,7 @@ func TestDeleteRoute(t *testing.T) {
 	})
 
 	interfaceWitVPCAndNoRoute := linodego.LinodeInterface{
-		ID:  services.VpcIDs["dummy"],
+		ID:  vpcID,
 		VPC: &linodego.VPCInterface{IPv4: linodego.VPCInterfaceIPv4{Ranges: nil}},
 	}
 
@@ -595,8 +579,8 @@ func TestDeleteRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -612,14 +596,14 @@ func TestDeleteRoute(t *testing.T) {
 		{
 			Address:      &vpcIP,
 			AddressRange: nil,
-			VPCID:        services.VpcIDs["dummy"],
+			VPCID:        vpcID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
 		{
 			Address:      nil,
 			AddressRange: &addressRange1,
-			VPCID:        services.VpcIDs["dummy"],
+			VPCID:        vpcID,
 			NAT1To1:      nil,
 			LinodeID:     nodeID,
 		},
@@ -629,8 +613,8 @@ func TestDeleteRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -646,8 +630,8 @@ func TestDeleteRoute(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		instanceCache := services.NewInstances(client)
-		routeController, err := newRoutes(client, instanceCache)
+		instanceCache := services.NewInstances(client, opts, vpcs)
+		routeController, err := newRoutes(client, instanceCache, opts, vpcs, newK8sNodeCache(opts))
 		require.NoError(t, err)
 
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{validInstance}, nil)
@@ -14,6 +14,8 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client/mocks"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
 )
 
 func createTestService() *v1.Service {
@@ -41,7 +43,8 @@ func Test_serviceController_Run(t *testing.T) {
 	informer := informers.NewSharedInformerFactory(kubeClient, 0).Core().V1().Services()
 	mockQueue := workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[any]{Name: "test"})
 
-	loadbalancers, assertion := newLoadbalancers(client, "us-east").(*loadbalancers)
+	opts := &options.Config{}
+	loadbalancers, assertion := newLoadbalancers(client, "us-east", opts, services.NewVPCCache(opts)).(*loadbalancers)
 	if !assertion {
 		t.Error("type assertion failed")
 	}
@@ -87,7 +90,7 @@ func Test_serviceController_processNextDeletion(t *testing.T) {
 				loadbalancers: nil,
 			},
 			Setup: func(f *fields) {
-				f.loadbalancers = &loadbalancers{client: f.Client, zone: "test"}
+				f.loadbalancers = &loadbalancers{client: f.Client, zone: "test", options: &options.Config{}}
 				f.queue = workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[any]{Name: "testQueue"})
 				f.queue.Add("test")
 			},
@@ -100,7 +103,7 @@ func Test_serviceController_processNextDeletion(t *testing.T) {
 				loadbalancers: nil,
 			},
 			Setup: func(f *fields) {
-				f.loadbalancers = &loadbalancers{client: f.Client, zone: "test"}
+				f.loadbalancers = &loadbalancers{client: f.Client, zone: "test", options: &options.Config{}}
 				f.queue = workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[any]{Name: "testQueue"})
 				svc := createTestService()
 				f.queue.Add(svc)
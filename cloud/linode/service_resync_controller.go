@@ -0,0 +1,425 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/appscode/go/wait"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	v1informers "k8s.io/client-go/informers/core/v1"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	servicecontroller "k8s.io/cloud-provider/controllers/service"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+)
+
+// serviceResyncController updates the NodeBalancers of provisioned LoadBalancer services when
+// something they depend on changes outside of the Service, which the cloud-provider service
+// controller does not react to. For example, cordoning a node, changing its backend weight or
+// endpoints of a service with endpoint aware backends moving to other nodes, the certificate
+// in a TLS secret referenced by an HTTPS port being rotated, or the LinodeLoadBalancerConfig
+// referenced by a service being edited.
+type serviceResyncController struct {
+	loadbalancers         *loadbalancers
+	serviceInformer       v1informers.ServiceInformer
+	nodeInformer          v1informers.NodeInformer
+	endpointSliceInformer discoveryinformers.EndpointSliceInformer
+	// tlsSecrets watches the TLS secrets referenced by provisioned services, which are looked up
+	// every tlsSecretWatchesSyncPeriod
+	tlsSecrets *tlsSecretWatches
+
+	queue workqueue.TypedRateLimitingInterface[string]
+	// tlsSecretQueue holds the keys of changed TLS secrets, whose services are looked up by a
+	// worker since that can take API calls for services with a LinodeLoadBalancerConfig
+	tlsSecretQueue workqueue.TypedRateLimitingInterface[string]
+}
+
+// tlsSecretWatchesSyncPeriod is how often the TLS secrets referenced by services are looked up
+// to start and stop watching them.
+const tlsSecretWatchesSyncPeriod = 30 * time.Second
+
+func newServiceResyncController(
+	loadbalancers *loadbalancers,
+	serviceInformer v1informers.ServiceInformer,
+	nodeInformer v1informers.NodeInformer,
+	endpointSliceInformer discoveryinformers.EndpointSliceInformer,
+	kubeClient kubernetes.Interface,
+) *serviceResyncController {
+	s := &serviceResyncController{
+		loadbalancers:         loadbalancers,
+		serviceInformer:       serviceInformer,
+		nodeInformer:          nodeInformer,
+		endpointSliceInformer: endpointSliceInformer,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "service-resync"},
+		),
+		tlsSecretQueue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "service-resync-tls-secrets"},
+		),
+	}
+	s.tlsSecrets = newTLSSecretWatches(kubeClient, s.tlsSecretQueue.Add)
+	return s
+}
+
+func (s *serviceResyncController) Run(stopCh <-chan struct{}) {
+	if _, err := s.nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, ok := oldObj.(*v1.Node)
+			if !ok {
+				return
+			}
+			newNode, ok := newObj.(*v1.Node)
+			if !ok {
+				return
+			}
+
+			if nodeBackendSettingsChanged(oldNode, newNode) {
+				klog.Infof("NodeBalancer backend settings of node %s changed, resyncing LoadBalancer services", newNode.Name)
+				s.enqueueAllServices()
+			} else if !labels.Equals(oldNode.Labels, newNode.Labels) {
+				s.enqueueBackendNodeSelectorServices(oldNode, newNode)
+			}
+		},
+	}); err != nil {
+		klog.Errorf("ServiceResyncController didn't successfully register it's Informer %s", err)
+	}
+
+	if _, err := s.endpointSliceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if slice, ok := obj.(*discoveryv1.EndpointSlice); ok {
+				s.enqueueEndpointSliceService(slice)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSlice, ok := oldObj.(*discoveryv1.EndpointSlice)
+			if !ok {
+				return
+			}
+			newSlice, ok := newObj.(*discoveryv1.EndpointSlice)
+			if !ok {
+				return
+			}
+			if !readyEndpointNodeNames(*oldSlice).Equal(readyEndpointNodeNames(*newSlice)) {
+				s.enqueueEndpointSliceService(newSlice)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if slice, ok := obj.(*discoveryv1.EndpointSlice); ok {
+				s.enqueueEndpointSliceService(slice)
+			}
+		},
+	}); err != nil {
+		klog.Errorf("ServiceResyncController didn't successfully register it's Informer %s", err)
+	}
+
+	go s.nodeInformer.Informer().Run(stopCh)
+	go s.serviceInformer.Informer().Run(stopCh)
+	go s.endpointSliceInformer.Informer().Run(stopCh)
+	synced := []cache.InformerSynced{
+		s.nodeInformer.Informer().HasSynced,
+		s.serviceInformer.Informer().HasSynced,
+		s.endpointSliceInformer.Informer().HasSynced,
+	}
+
+	// The informer is only set up when the LinodeLoadBalancerConfig CRD is installed
+	if configInformer := s.loadbalancers.loadBalancerConfigInformer; configInformer != nil {
+		if _, err := configInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: s.enqueueLoadBalancerConfigServices,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldConfig, ok := oldObj.(metav1.Object)
+				if !ok {
+					return
+				}
+				newConfig, ok := newObj.(metav1.Object)
+				if !ok {
+					return
+				}
+				// Status updates written by the CCM itself don't change the generation
+				if oldConfig.GetGeneration() != newConfig.GetGeneration() {
+					s.enqueueLoadBalancerConfigServices(newObj)
+				}
+			},
+			DeleteFunc: s.enqueueLoadBalancerConfigServices,
+		}); err != nil {
+			klog.Errorf("ServiceResyncController didn't successfully register it's Informer %s", err)
+		}
+		go configInformer.Informer().Run(stopCh)
+		synced = append(synced, configInformer.Informer().HasSynced)
+	}
+
+	if !cache.WaitForCacheSync(stopCh, synced...) {
+		klog.Error("ServiceResyncController failed to sync informer caches")
+		return
+	}
+
+	go wait.Until(s.worker, time.Second, stopCh)
+	go wait.Until(s.tlsSecretWorker, time.Second, stopCh)
+	go wait.Until(s.syncTLSSecretWatches, tlsSecretWatchesSyncPeriod, stopCh)
+	<-stopCh
+	s.tlsSecrets.stop()
+	s.queue.ShutDown()
+	s.tlsSecretQueue.ShutDown()
+}
+
+// enqueueAllServices queues every provisioned LoadBalancer service for a resync.
+func (s *serviceResyncController) enqueueAllServices() {
+	services, err := s.serviceInformer.Lister().List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services for resync: %s", err)
+		return
+	}
+	for _, service := range services {
+		s.enqueueService(service)
+	}
+}
+
+// enqueueBackendNodeSelectorServices queues the services whose backend node selector matches
+// either the old or the new labels of an updated node, but not both.
+func (s *serviceResyncController) enqueueBackendNodeSelectorServices(oldNode, newNode *v1.Node) {
+	services, err := s.serviceInformer.Lister().List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services for resync: %s", err)
+		return
+	}
+	for _, service := range services {
+		selector, err := getBackendNodeSelector(service)
+		if err != nil || selector == nil {
+			continue
+		}
+		if selector.Matches(labels.Set(oldNode.Labels)) != selector.Matches(labels.Set(newNode.Labels)) {
+			s.enqueueService(service)
+		}
+	}
+}
+
+// enqueueEndpointSliceService queues the service owning slice for a resync if it uses endpoint
+// aware backends.
+func (s *serviceResyncController) enqueueEndpointSliceService(slice *discoveryv1.EndpointSlice) {
+	serviceName, ok := slice.Labels[discoveryv1.LabelServiceName]
+	if !ok {
+		return
+	}
+	service, err := s.serviceInformer.Lister().Services(slice.Namespace).Get(serviceName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("failed to get service %s/%s for EndpointSlice %s: %s", slice.Namespace, serviceName, slice.Name, err)
+		}
+		return
+	}
+	if resolveEndpointAwareBackends(s.loadbalancers.options, service) {
+		s.enqueueService(service)
+	}
+}
+
+// syncTLSSecretWatches watches the TLS secrets referenced by provisioned services, either
+// directly or through their LinodeLoadBalancerConfig, and stops watching all others.
+func (s *serviceResyncController) syncTLSSecretWatches() {
+	services, err := s.serviceInformer.Lister().List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services for TLS secret watches: %s", err)
+		return
+	}
+	keys := sets.New[string]()
+	for _, service := range services {
+		if service.Spec.Type != v1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 {
+			continue
+		}
+		if _, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerConfig]; ok {
+			resolved, err := s.loadbalancers.resolveLoadBalancerConfig(context.Background(), service)
+			if err != nil {
+				klog.Warningf("failed to resolve LinodeLoadBalancerConfig of service (%s): %s", getServiceNn(service), err)
+				continue
+			}
+			service = resolved
+		}
+		keys = keys.Union(referencedTLSSecrets(service))
+	}
+	s.tlsSecrets.sync(keys)
+}
+
+// enqueueTLSSecretServices queues the services that reference the secret with key namespace/name
+// as the TLS secret of a port, either directly or through their LinodeLoadBalancerConfig.
+func (s *serviceResyncController) enqueueTLSSecretServices(ctx context.Context, secretKey string) {
+	services, err := s.serviceInformer.Lister().List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services for resync: %s", err)
+		return
+	}
+	for _, service := range services {
+		if service.Spec.Type != v1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 {
+			continue
+		}
+		if _, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerConfig]; ok {
+			resolved, err := s.loadbalancers.resolveLoadBalancerConfig(ctx, service)
+			if err != nil {
+				klog.Warningf("failed to resolve LinodeLoadBalancerConfig of service (%s): %s", getServiceNn(service), err)
+				continue
+			}
+			service = resolved
+		}
+		if referencedTLSSecrets(service).Has(secretKey) {
+			klog.Infof("TLS secret %s changed, resyncing service (%s)", secretKey, getServiceNn(service))
+			s.enqueueService(service)
+		}
+	}
+}
+
+// enqueueLoadBalancerConfigServices queues the services referencing a LinodeLoadBalancerConfig
+// that was created, changed or deleted.
+func (s *serviceResyncController) enqueueLoadBalancerConfigServices(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	lbConfig, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	services, err := s.serviceInformer.Lister().Services(lbConfig.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services for resync: %s", err)
+		return
+	}
+	for _, service := range services {
+		if service.GetAnnotations()[annotations.AnnLinodeLoadBalancerConfig] == lbConfig.GetName() {
+			klog.Infof("LinodeLoadBalancerConfig %s/%s changed, resyncing service (%s)", lbConfig.GetNamespace(), lbConfig.GetName(), getServiceNn(service))
+			s.enqueueService(service)
+		}
+	}
+}
+
+// enqueueService queues service for a resync if it is a provisioned LoadBalancer service.
+func (s *serviceResyncController) enqueueService(service *v1.Service) {
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(service)
+	if err != nil {
+		klog.Errorf("failed to get key for service (%s): %s", getServiceNn(service), err)
+		return
+	}
+	s.queue.Add(key)
+}
+
+// worker runs a worker thread that dequeues services and updates their NodeBalancers.
+func (s *serviceResyncController) worker() {
+	for s.processNextResync() {
+	}
+}
+
+func (s *serviceResyncController) processNextResync() bool {
+	key, quit := s.queue.Get()
+	if quit {
+		return false
+	}
+	defer s.queue.Done(key)
+
+	if err := s.resyncService(context.Background(), key); err != nil {
+		klog.Errorf("failed to resync service (%s); retrying: %s", key, err)
+		s.queue.AddRateLimited(key)
+		return true
+	}
+	s.queue.Forget(key)
+	return true
+}
+
+// tlsSecretWorker runs a worker thread that dequeues changed TLS secrets and queues the services
+// referencing them.
+func (s *serviceResyncController) tlsSecretWorker() {
+	for s.processNextTLSSecret() {
+	}
+}
+
+func (s *serviceResyncController) processNextTLSSecret() bool {
+	key, quit := s.tlsSecretQueue.Get()
+	if quit {
+		return false
+	}
+	defer s.tlsSecretQueue.Done(key)
+
+	s.enqueueTLSSecretServices(context.Background(), key)
+	return true
+}
+
+func (s *serviceResyncController) resyncService(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	service, err := s.serviceInformer.Lister().Services(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 {
+		return nil
+	}
+
+	nodes, err := s.nodeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	klog.V(3).Infof("ServiceResyncController updating NodeBalancer for service (%s)", key)
+	return s.loadbalancers.UpdateLoadBalancer(ctx, s.loadbalancers.options.ClusterName, service, loadBalancerBackendNodes(nodes))
+}
+
+// loadBalancerBackendNodes filters nodes the way the cloud-provider service controller does
+// before it calls UpdateLoadBalancer, so both controllers agree on the backend node set.
+func loadBalancerBackendNodes(nodes []*v1.Node) []*v1.Node {
+	filtered := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !node.DeletionTimestamp.IsZero() || node.Spec.ProviderID == "" {
+			continue
+		}
+		if exclude, ok := node.Labels[v1.LabelNodeExcludeBalancers]; ok {
+			if excluded, err := strconv.ParseBool(exclude); err != nil || excluded {
+				continue
+			}
+		}
+		tainted := false
+		for _, taint := range node.Spec.Taints {
+			if taint.Key == servicecontroller.ToBeDeletedTaint {
+				tainted = true
+				break
+			}
+		}
+		if !tainted {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// nodeBackendSettingsChanged reports whether a node update changes how the node is configured as
+// a NodeBalancer backend, without changing whether it is a backend at all.
+func nodeBackendSettingsChanged(oldNode, newNode *v1.Node) bool {
+	if oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable {
+		return true
+	}
+	for _, key := range []string{annotations.AnnLinodeNodeBalancerWeight, annotations.AnnLinodeNodeBalancerDrain} {
+		oldValue, oldOk := getNodeAnnotationOrLabel(oldNode, key)
+		newValue, newOk := getNodeAnnotationOrLabel(newNode, key)
+		if oldOk != newOk || oldValue != newValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package linode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	servicecontroller "k8s.io/cloud-provider/controllers/service"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_nodeBackendSettingsChanged(t *testing.T) {
+	testcases := []struct {
+		name     string
+		oldNode  *v1.Node
+		newNode  *v1.Node
+		expected bool
+	}{
+		{
+			name:     "unchanged",
+			oldNode:  &v1.Node{},
+			newNode:  &v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}}},
+			expected: false,
+		},
+		{
+			name:     "cordoned",
+			oldNode:  &v1.Node{},
+			newNode:  &v1.Node{Spec: v1.NodeSpec{Unschedulable: true}},
+			expected: true,
+		},
+		{
+			name:     "weight changed",
+			oldNode:  &v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotations.AnnLinodeNodeBalancerWeight: "10"}}},
+			newNode:  &v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotations.AnnLinodeNodeBalancerWeight: "20"}}},
+			expected: true,
+		},
+		{
+			name:     "drain label added",
+			oldNode:  &v1.Node{},
+			newNode:  &v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{annotations.AnnLinodeNodeBalancerDrain: "true"}}},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nodeBackendSettingsChanged(tc.oldNode, tc.newNode))
+		})
+	}
+}
+
+func Test_loadBalancerBackendNodes(t *testing.T) {
+	now := metav1.Now()
+	spec := v1.NodeSpec{ProviderID: "linode://1"}
+	cordoned, tainted := spec, spec
+	cordoned.Unschedulable = true
+	tainted.Taints = []v1.Taint{{Key: servicecontroller.ToBeDeletedTaint}}
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ready"}, Spec: spec},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}, Spec: cordoned},
+		{ObjectMeta: metav1.ObjectMeta{Name: "excluded", Labels: map[string]string{v1.LabelNodeExcludeBalancers: "true"}}, Spec: spec},
+		{ObjectMeta: metav1.ObjectMeta{Name: "deleted", DeletionTimestamp: &now}, Spec: spec},
+		{ObjectMeta: metav1.ObjectMeta{Name: "to-be-deleted"}, Spec: tainted},
+		{ObjectMeta: metav1.ObjectMeta{Name: "uninitialized"}},
+	}
+
+	names := []string{}
+	for _, node := range loadBalancerBackendNodes(nodes) {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"ready", "cordoned"}, names)
+}
+
+func Test_serviceResyncController_enqueueAllServices(t *testing.T) {
+	kubeClient := fake.NewClientset()
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+	nodeInformer := factory.Core().V1().Nodes()
+
+	provisioned := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "provisioned", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+			Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+		}},
+	}
+	pending := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+	}
+	clusterIP := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-ip", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP},
+	}
+	for _, svc := range []*v1.Service{provisioned, pending, clusterIP} {
+		assert.NoError(t, serviceInformer.Informer().GetStore().Add(svc))
+	}
+
+	controller := newServiceResyncController(&loadbalancers{options: &options.Config{}}, serviceInformer, nodeInformer, factory.Discovery().V1().EndpointSlices(), kubeClient)
+	defer controller.queue.ShutDown()
+	controller.enqueueAllServices()
+
+	assert.Equal(t, 1, controller.queue.Len())
+	key, _ := controller.queue.Get()
+	assert.Equal(t, "default/provisioned", key)
+}
+
+func Test_serviceResyncController_enqueueLoadBalancerConfigServices(t *testing.T) {
+	kubeClient := fake.NewClientset()
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+
+	newService := func(name, namespace, lbConfig string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: map[string]string{annotations.AnnLinodeLoadBalancerConfig: lbConfig},
+			},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			}},
+		}
+	}
+	for _, svc := range []*v1.Service{
+		newService("referencing", "default", "shared"),
+		newService("other-config", "default", "other"),
+		newService("other-namespace", "other", "shared"),
+	} {
+		assert.NoError(t, serviceInformer.Informer().GetStore().Add(svc))
+	}
+
+	controller := newServiceResyncController(&loadbalancers{options: &options.Config{}}, serviceInformer, factory.Core().V1().Nodes(), factory.Discovery().V1().EndpointSlices(), kubeClient)
+	defer controller.queue.ShutDown()
+
+	lbConfig := &unstructured.Unstructured{}
+	lbConfig.SetName("shared")
+	lbConfig.SetNamespace("default")
+	controller.enqueueLoadBalancerConfigServices(cache.DeletedFinalStateUnknown{Key: "default/shared", Obj: lbConfig})
+
+	assert.Equal(t, 1, controller.queue.Len())
+	key, _ := controller.queue.Get()
+	assert.Equal(t, "default/referencing", key)
+}
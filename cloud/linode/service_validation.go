@@ -0,0 +1,108 @@
+package linode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/linode/linodego/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
+)
+
+// validateService checks the Linode annotations of a LoadBalancer service without talking to
+// any API. The returned error joins every problem that would make reconciliation fail, while
+// warnings describe values the reconciler silently ignores or adjusts.
+//
+// Both the reconciler and the admission webhook use it, so they always agree on what is valid.
+func validateService(opts *options.Config, service *v1.Service) ([]string, error) {
+	var (
+		warnings []string
+		errs     []error
+	)
+
+	for _, port := range service.Spec.Ports {
+		_, portConfigResult, err := newNodeBalancerConfig(service, port)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if portConfigResult.Protocol == linodego.ProtocolHTTPS && portConfigResult.TLSSecretName == "" {
+			errs = append(errs, fmt.Errorf("TLS secret name for port %v is not specified", port.Port))
+		} else if portConfigResult.TLSSecretName != "" {
+			if err = validateTLSSecretReference(portConfigResult.TLSSecretName); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if _, warning := parseConnectionThrottle(service); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	if _, warning := parseNodeBalancerType(opts, service); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	_, hasFirewallACL := service.GetAnnotations()[annotations.AnnLinodeCloudFirewallACL]
+	if fwID, ok := service.GetAnnotations()[annotations.AnnLinodeCloudFirewallID]; ok {
+		if _, err := strconv.Atoi(fwID); err != nil {
+			errs = append(errs, fmt.Errorf("invalid firewall ID %q specified in annotation %q", fwID, annotations.AnnLinodeCloudFirewallID))
+		}
+		if hasFirewallACL {
+			warnings = append(warnings, fmt.Sprintf("annotation %q is ignored because %q is set", annotations.AnnLinodeCloudFirewallACL, annotations.AnnLinodeCloudFirewallID))
+		}
+	} else if hasFirewallACL {
+		if _, err := services.CreateFirewallOptsForSvc(opts.NodeBalancerPrefix, nil, service); err != nil {
+			errs = append(errs, fmt.Errorf("invalid firewall ACL specified in annotation %q: %w", annotations.AnnLinodeCloudFirewallACL, err))
+		}
+	}
+
+	if backendIPv4Range, ok := service.GetAnnotations()[annotations.NodeBalancerBackendIPv4Range]; ok {
+		if err := validateNodeBalancerBackendIPv4Range(opts, backendIPv4Range); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := validateNodeBalancerFrontendIPRange(service.GetAnnotations()[annotations.NodeBalancerFrontendIPv4Range], "IPv4"); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateNodeBalancerFrontendIPRange(service.GetAnnotations()[annotations.NodeBalancerFrontendIPv6Range], "IPv6"); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := getBackendNodeSelector(service); err != nil {
+		errs = append(errs, err)
+	}
+
+	if tagStr, ok := service.GetAnnotations()[annotations.AnnLinodeLoadBalancerTags]; ok {
+		for _, tag := range strings.Split(tagStr, ",") {
+			if isReservedTag(tag) {
+				errs = append(errs, fmt.Errorf("tag %q specified in annotation %q is reserved for the CCM", tag, annotations.AnnLinodeLoadBalancerTags))
+			}
+		}
+	}
+
+	for _, ann := range []string{annotations.NodeBalancerBackendSubnetID, annotations.NodeBalancerFrontendSubnetID} {
+		if subnetID, ok := service.GetAnnotations()[ann]; ok {
+			if _, err := strconv.Atoi(subnetID); err != nil {
+				errs = append(errs, fmt.Errorf("invalid subnet ID %q specified in annotation %q", subnetID, ann))
+			}
+		}
+	}
+
+	return warnings, errors.Join(errs...)
+}
+
+// ensureValidService runs validateService for the reconciler, logging any warnings.
+func ensureValidService(opts *options.Config, service *v1.Service) error {
+	warnings, err := validateService(opts, service)
+	for _, warning := range warnings {
+		klog.Warningf("service (%s): %s", getServiceNn(service), warning)
+	}
+	return err
+}
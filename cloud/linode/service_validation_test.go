@@ -0,0 +1,108 @@
+package linode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_validateService(t *testing.T) {
+	testcases := []struct {
+		name         string
+		annotations  map[string]string
+		wantErr      string
+		wantWarnings int
+	}{
+		{
+			name: "valid service",
+			annotations: map[string]string{
+				annotations.AnnLinodeDefaultProtocol:  "http",
+				annotations.AnnLinodeHealthCheckType:  "http",
+				annotations.AnnLinodeThrottle:         "10",
+				annotations.AnnLinodeNodeBalancerType: "premium",
+			},
+		},
+		{
+			name:        "invalid health check type",
+			annotations: map[string]string{annotations.AnnLinodeHealthCheckType: "bogus"},
+			wantErr:     `invalid health check type: "bogus"`,
+		},
+		{
+			name:        "invalid algorithm",
+			annotations: map[string]string{annotations.AnnLinodeDefaultAlgorithm: "bogus"},
+			wantErr:     "invalid algorithm",
+		},
+		{
+			name:        "invalid port config json",
+			annotations: map[string]string{annotations.AnnLinodePortConfigPrefix + "80": "{"},
+			wantErr:     "unexpected end of JSON input",
+		},
+		{
+			name:        "https without tls secret",
+			annotations: map[string]string{annotations.AnnLinodeDefaultProtocol: "https"},
+			wantErr:     "TLS secret name for port 80 is not specified",
+		},
+		{
+			name:        "invalid firewall acl",
+			annotations: map[string]string{annotations.AnnLinodeCloudFirewallACL: `{"allowList": {}, "denyList": {}}`},
+			wantErr:     "invalid firewall ACL",
+		},
+		{
+			name:        "invalid firewall id",
+			annotations: map[string]string{annotations.AnnLinodeCloudFirewallID: "abc"},
+			wantErr:     "invalid firewall ID",
+		},
+		{
+			name:        "ownership tag",
+			annotations: map[string]string{annotations.AnnLinodeLoadBalancerTags: "team-a,ccm-service:other"},
+			wantErr:     `tag "ccm-service:other" specified in annotation`,
+		},
+		{
+			name:        "invalid frontend range",
+			annotations: map[string]string{annotations.NodeBalancerFrontendIPv4Range: "10.0.0.0"},
+			wantErr:     "invalid frontend IPv4 range",
+		},
+		{
+			name:         "invalid throttle is a warning",
+			annotations:  map[string]string{annotations.AnnLinodeThrottle: "abc"},
+			wantWarnings: 1,
+		},
+		{
+			name:         "throttle out of range is a warning",
+			annotations:  map[string]string{annotations.AnnLinodeThrottle: "50"},
+			wantWarnings: 1,
+		},
+		{
+			name:         "invalid nodebalancer type is a warning",
+			annotations:  map[string]string{annotations.AnnLinodeNodeBalancerType: "huge"},
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: tc.annotations},
+				Spec: v1.ServiceSpec{
+					Type:  v1.ServiceTypeLoadBalancer,
+					Ports: []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080}},
+				},
+			}
+
+			warnings, err := validateService(&options.Config{}, svc)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, warnings, tc.wantWarnings)
+		})
+	}
+}
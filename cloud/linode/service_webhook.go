@@ -0,0 +1,179 @@
+package linode
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	serviceWebhookPath         = "/validate-service"
+	serviceWebhookMaxBodyBytes = 1 << 20
+)
+
+// serviceWebhookHandler answers AdmissionReview requests for Services with the result of validateService.
+type serviceWebhookHandler struct {
+	loadbalancers *loadbalancers
+}
+
+func (h serviceWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, serviceWebhookMaxBodyBytes))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	if err = json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, "request body is not an AdmissionReview", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = admitService(r.Context(), h.loadbalancers, review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("failed to write admission response: %s", err)
+	}
+}
+
+// admitService rejects LoadBalancer Services whose Linode annotations would fail reconciliation,
+// and returns warnings for values the reconciler would ignore or adjust. Like the reconciler, it
+// validates the Service with its LinodeLoadBalancerConfig merged in.
+func admitService(ctx context.Context, l *loadbalancers, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	service := &v1.Service{}
+	if err := json.Unmarshal(req.Object.Raw, service); err != nil {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusBadRequest,
+				Reason:  metav1.StatusReasonBadRequest,
+				Message: fmt.Sprintf("failed to decode service: %s", err),
+			},
+		}
+	}
+
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	resolved, err := l.resolveLoadBalancerConfig(ctx, service)
+	if err != nil {
+		// The reconciler retries until the config can be resolved, so a config created after the
+		// Service must not keep it from being admitted
+		return &admissionv1.AdmissionResponse{Allowed: true, Warnings: []string{err.Error()}}
+	}
+
+	warnings, err := validateService(l.options, resolved)
+	if err != nil {
+		klog.V(3).Infof("rejecting service (%s): %s", getServiceNn(service), err)
+		return &admissionv1.AdmissionResponse{
+			Allowed:  false,
+			Warnings: warnings,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusUnprocessableEntity,
+				Reason:  metav1.StatusReasonInvalid,
+				Message: fmt.Sprintf("invalid Linode load balancer annotations: %s", err),
+			},
+		}
+	}
+
+	return &admissionv1.AdmissionResponse{Allowed: true, Warnings: warnings}
+}
+
+// certificateReloader serves the webhook certificate from certDir, reloading it whenever
+// the files change so that rotated certificates are picked up without a restart.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func (c *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	info, err := os.Stat(c.certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cert != nil && info.ModTime().Equal(c.modTime) {
+		return c.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook certificate: %w", err)
+	}
+	c.cert = &cert
+	c.modTime = info.ModTime()
+	return c.cert, nil
+}
+
+// startServiceWebhook serves the Service admission webhook on the configured port using the
+// tls.crt and tls.key from the configured certificate directory. It is served by every replica,
+// independent of leader election.
+func startServiceWebhook(l *loadbalancers) error {
+	port, certDir := l.options.ServiceWebhookPort, l.options.ServiceWebhookCertDir
+	reloader := &certificateReloader{
+		certFile: filepath.Join(certDir, v1.TLSCertKey),
+		keyFile:  filepath.Join(certDir, v1.TLSPrivateKeyKey),
+	}
+	if _, err := reloader.GetCertificate(nil); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %d for service webhook: %w", port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(serviceWebhookPath, serviceWebhookHandler{loadbalancers: l})
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		},
+	}
+
+	go func() {
+		klog.Infof("serving service admission webhook on port %d", port)
+		if err := server.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("service admission webhook stopped: %s", err)
+		}
+	}()
+	return nil
+}
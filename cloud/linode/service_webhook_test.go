@@ -0,0 +1,141 @@
+package linode
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/apis/v1alpha1"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func newServiceAdmissionReview(t *testing.T, svc *v1.Service) admissionv1.AdmissionReview {
+	t.Helper()
+
+	raw, err := json.Marshal(svc)
+	require.NoError(t, err)
+	return admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "review-uid",
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func Test_serviceWebhookHandler(t *testing.T) {
+	testcases := []struct {
+		name         string
+		serviceType  v1.ServiceType
+		annotations  map[string]string
+		allowed      bool
+		wantWarnings int
+	}{
+		{
+			name:        "valid load balancer",
+			serviceType: v1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{annotations.AnnLinodeDefaultAlgorithm: "leastconn"},
+			allowed:     true,
+		},
+		{
+			name:        "invalid load balancer",
+			serviceType: v1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{annotations.AnnLinodeDefaultAlgorithm: "bogus"},
+			allowed:     false,
+		},
+		{
+			name:         "warning only",
+			serviceType:  v1.ServiceTypeLoadBalancer,
+			annotations:  map[string]string{annotations.AnnLinodeThrottle: "bogus"},
+			allowed:      true,
+			wantWarnings: 1,
+		},
+		{
+			name:        "non load balancer is ignored",
+			serviceType: v1.ServiceTypeClusterIP,
+			annotations: map[string]string{annotations.AnnLinodeDefaultAlgorithm: "bogus"},
+			allowed:     true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: tc.annotations},
+				Spec: v1.ServiceSpec{
+					Type:  tc.serviceType,
+					Ports: []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30080}},
+				},
+			}
+			body, err := json.Marshal(newServiceAdmissionReview(t, svc))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			serviceWebhookHandler{loadbalancers: &loadbalancers{options: &options.Config{}}}.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, serviceWebhookPath, bytes.NewReader(body)))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			review := admissionv1.AdmissionReview{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &review))
+			require.NotNil(t, review.Response)
+			assert.Equal(t, "review-uid", string(review.Response.UID))
+			assert.Equal(t, tc.allowed, review.Response.Allowed)
+			assert.Len(t, review.Response.Warnings, tc.wantWarnings)
+		})
+	}
+}
+
+func Test_serviceWebhookHandlerBadRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serviceWebhookHandler{loadbalancers: &loadbalancers{options: &options.Config{}}}.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, serviceWebhookPath, bytes.NewReader([]byte("{"))))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	serviceWebhookHandler{loadbalancers: &loadbalancers{options: &options.Config{}}}.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, serviceWebhookPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func Test_admitServiceResolvesLoadBalancerConfig(t *testing.T) {
+	lbConfig := &v1alpha1.LinodeLoadBalancerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "default"},
+		Spec: v1alpha1.LinodeLoadBalancerConfigSpec{
+			Ports: []v1alpha1.PortConfig{{Port: 443, TLSSecretName: "tls-cert"}},
+		},
+	}
+	lb := &loadbalancers{options: &options.Config{}, dynamicClient: newFakeLoadBalancerConfigClient(t, lbConfig)}
+
+	newService := func(configName string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", Annotations: map[string]string{
+				annotations.AnnLinodeDefaultProtocol:    "https",
+				annotations.AnnLinodeLoadBalancerConfig: configName,
+			}},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 443, NodePort: 30443}},
+			},
+		}
+	}
+
+	// The TLS secret of the https port only comes from the config
+	review := newServiceAdmissionReview(t, newService("tls"))
+	response := admitService(t.Context(), lb, review.Request)
+	assert.True(t, response.Allowed, response.Result)
+
+	// A missing config is reported but doesn't block the Service, the reconciler retries until it exists
+	review = newServiceAdmissionReview(t, newService("missing"))
+	response = admitService(t.Context(), lb, review.Request)
+	assert.True(t, response.Allowed)
+	require.Len(t, response.Warnings, 1)
+	assert.Contains(t, response.Warnings[0], "LinodeLoadBalancerConfig default/missing")
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"slices"
 	"strconv"
 	"strings"
@@ -23,6 +22,10 @@ import (
 	"github.com/linode/linode-cloud-controller-manager/sentry"
 )
 
+// DefaultInstanceCacheTTL is how long the Linode instances listed by Instances are cached unless
+// configured otherwise.
+const DefaultInstanceCacheTTL = 15 * time.Second
+
 type nodeIP struct {
 	ip     string
 	ipType v1.NodeAddressType
@@ -37,7 +40,8 @@ type nodeCache struct {
 	sync.RWMutex
 	nodes      map[int]linodeInstance
 	lastUpdate time.Time
-	ttl        time.Duration
+	options    *options.Config
+	vpcs       *VPCCache
 }
 
 // getInstanceAddresses returns all addresses configured on a linode.
@@ -62,7 +66,7 @@ func (nc *nodeCache) getInstanceAddresses(instance linodego.Instance, vpcips []s
 
 	for _, ip := range instance.IPv4 {
 		ipType := v1.NodeExternalIP
-		if ccmUtils.IsPrivate(ip, options.Options.LinodeExternalNetwork) {
+		if ccmUtils.IsPrivate(ip, nc.options.LinodeExternalNetwork) {
 			ipType = v1.NodeInternalIP
 		}
 		ips = append(ips, nodeIP{ip: ip.String(), ipType: ipType})
@@ -78,19 +82,28 @@ func (nc *nodeCache) getInstanceAddresses(instance linodego.Instance, vpcips []s
 	return ips
 }
 
+// ttl returns how long the cached instances are used, which can change when the configuration is
+// reloaded
+func (nc *nodeCache) ttl() time.Duration {
+	if ttl := nc.options.Current().InstanceCacheTTL; ttl > 0 {
+		return ttl
+	}
+	return DefaultInstanceCacheTTL
+}
+
 // refreshInstances conditionally loads all instances from the Linode API and caches them.
 // It does not refresh if the last update happened less than `nodeCache.ttl` ago.
 func (nc *nodeCache) refreshInstances(ctx context.Context, client linodeClient.Client) error {
 	nc.Lock()
 	defer nc.Unlock()
 
-	if time.Since(nc.lastUpdate) < nc.ttl {
+	if time.Since(nc.lastUpdate) < nc.ttl() {
 		return nil
 	}
 
 	filter := linodego.Filter{}
-	if options.Options.LinodeTagFilter != "" {
-		filter.AddField(linodego.Contains, "tags", options.Options.LinodeTagFilter)
+	if nc.options.LinodeTagFilter != "" {
+		filter.AddField(linodego.Contains, "tags", nc.options.LinodeTagFilter)
 	}
 	filterJSON, err := filter.MarshalJSON()
 	if err != nil {
@@ -105,12 +118,12 @@ func (nc *nodeCache) refreshInstances(ctx context.Context, client linodeClient.C
 	// If running within VPC, find instances and store their ips
 	vpcNodes := map[int][]string{}
 	vpcIPv6AddrTypes := map[string]v1.NodeAddressType{}
-	for _, name := range options.Options.VPCNames {
+	for _, name := range nc.options.VPCNames {
 		vpcName := strings.TrimSpace(name)
 		if vpcName == "" {
 			continue
 		}
-		resp, err := GetVPCIPAddresses(ctx, client, vpcName)
+		resp, err := nc.vpcs.GetVPCIPAddresses(ctx, client, vpcName)
 		if err != nil {
 			return fmt.Errorf("failed updating instances cache for VPC %s: %w", vpcName, err)
 		}
@@ -121,7 +134,7 @@ func (nc *nodeCache) refreshInstances(ctx context.Context, client linodeClient.C
 			vpcNodes[vpcip.LinodeID] = append(vpcNodes[vpcip.LinodeID], *vpcip.Address)
 		}
 
-		resp, err = GetVPCIPv6Addresses(ctx, client, vpcName)
+		resp, err = nc.vpcs.GetVPCIPv6Addresses(ctx, client, vpcName)
 		if err != nil {
 			return fmt.Errorf("failed updating instances cache for VPC %s: %w", vpcName, err)
 		}
@@ -143,7 +156,7 @@ func (nc *nodeCache) refreshInstances(ctx context.Context, client linodeClient.C
 	newNodes := make(map[int]linodeInstance, len(instances))
 	for index, instance := range instances {
 		// if running within VPC, only store instances in cache which are part of VPC
-		if len(options.Options.VPCNames) > 0 && len(vpcNodes[instance.ID]) == 0 {
+		if len(nc.options.VPCNames) > 0 && len(vpcNodes[instance.ID]) == 0 {
 			continue
 		}
 		node := linodeInstance{
@@ -159,24 +172,19 @@ func (nc *nodeCache) refreshInstances(ctx context.Context, client linodeClient.C
 }
 
 type Instances struct {
-	client linodeClient.Client
+	client  linodeClient.Client
+	options *options.Config
 
 	nodeCache *nodeCache
 }
 
 // NewInstances creates a new Instances cache with a specified TTL for the nodeCache.
-func NewInstances(client linodeClient.Client) *Instances {
-	timeout := 15
-	if raw, ok := os.LookupEnv("LINODE_INSTANCE_CACHE_TTL"); ok {
-		if t, err := strconv.Atoi(raw); t > 0 && err == nil {
-			timeout = t
-		}
-	}
-	klog.V(3).Infof("TTL for nodeCache set to %d", timeout)
-
-	return &Instances{client, &nodeCache{
-		nodes: make(map[int]linodeInstance, 0),
-		ttl:   time.Duration(timeout) * time.Second,
+// The VPC addresses of the instances are looked up through vpcs.
+func NewInstances(client linodeClient.Client, opts *options.Config, vpcs *VPCCache) *Instances {
+	return &Instances{client, opts, &nodeCache{
+		nodes:   make(map[int]linodeInstance, 0),
+		options: opts,
+		vpcs:    vpcs,
 	}}
 }
 
@@ -202,7 +210,7 @@ func (i *Instances) linodeByIP(kNode *v1.Node) (*linodego.Instance, error) {
 	}
 	for _, node := range i.nodeCache.nodes {
 		for _, nodeIP := range node.instance.IPv4 {
-			if !ccmUtils.IsPrivate(nodeIP, options.Options.LinodeExternalNetwork) && slices.Contains(kNodeAddresses, nodeIP.String()) {
+			if !ccmUtils.IsPrivate(nodeIP, i.options.LinodeExternalNetwork) && slices.Contains(kNodeAddresses, nodeIP.String()) {
 				return node.instance, nil
 			}
 		}
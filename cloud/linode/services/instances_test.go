@@ -46,7 +46,8 @@ func TestInstanceExists(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 
 	t.Run("should return false if linode does not exist (by providerID)", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + "123")
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, nil)
 
@@ -56,7 +57,8 @@ func TestInstanceExists(t *testing.T) {
 	})
 
 	t.Run("should return true if linode exists (by provider)", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + "123")
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{
 			{
@@ -73,7 +75,8 @@ func TestInstanceExists(t *testing.T) {
 	})
 
 	t.Run("should return true if linode exists (by name)", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		name := "some-name"
 		node := nodeWithName(name)
 
@@ -95,7 +98,8 @@ func TestMetadataRetrieval(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 
 	t.Run("uses name over IP for finding linode", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		publicIP := net.ParseIP("172.234.31.123")
 		privateIP := net.ParseIP("192.168.159.135")
 		expectedInstance := linodego.Instance{Label: "expected-instance", ID: 12345, IPv4: []net.IP{publicIP, privateIP}}
@@ -109,7 +113,8 @@ func TestMetadataRetrieval(t *testing.T) {
 	})
 
 	t.Run("fails when linode does not exist (by provider)", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		id := 456302
 		providerID := ccmUtils.ProviderIDPrefix + strconv.Itoa(id)
 		node := nodeWithProviderID(providerID)
@@ -121,7 +126,8 @@ func TestMetadataRetrieval(t *testing.T) {
 	})
 
 	t.Run("should return data when linode is found (by name)", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		id := 123
 		node := nodeWithName(instanceName)
 		publicIPv4 := net.ParseIP("45.76.101.25")
@@ -154,7 +160,9 @@ func TestMetadataRetrieval(t *testing.T) {
 	})
 
 	t.Run("should return data when linode is found (by name) and addresses must be in order", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		instances := NewInstances(client, opts, vpcs)
 		id := 123
 		node := nodeWithName(instanceName)
 		publicIPv4 := net.ParseIP("45.76.101.25")
@@ -162,10 +170,10 @@ func TestMetadataRetrieval(t *testing.T) {
 		ipv6Addr := "2001::8a2e:370:7348"
 		linodeType := typeG6
 
-		options.Options.VPCNames = []string{"test"}
-		options.Options.LinodeTagFilter = "test-cluster"
-		VpcIDs["test"] = 1
-		options.Options.EnableRouteController = true
+		opts.VPCNames = []string{"test"}
+		opts.LinodeTagFilter = "test-cluster"
+		vpcs.SetVPCID("test", 1)
+		opts.EnableRouteController = true
 
 		instance := linodego.Instance{
 			ID:     id,
@@ -182,21 +190,21 @@ func TestMetadataRetrieval(t *testing.T) {
 			{
 				Address:      &vpcIP,
 				AddressRange: nil,
-				VPCID:        VpcIDs["test"],
+				VPCID:        vpcs.vpcIDs["test"],
 				NAT1To1:      nil,
 				LinodeID:     id,
 			},
 			{
 				Address:      nil,
 				AddressRange: &addressRange1,
-				VPCID:        VpcIDs["test"],
+				VPCID:        vpcs.vpcIDs["test"],
 				NAT1To1:      nil,
 				LinodeID:     id,
 			},
 			{
 				Address:      nil,
 				AddressRange: &addressRange2,
-				VPCID:        VpcIDs["test"],
+				VPCID:        vpcs.vpcIDs["test"],
 				NAT1To1:      nil,
 				LinodeID:     id,
 			},
@@ -209,8 +217,8 @@ func TestMetadataRetrieval(t *testing.T) {
 			t.Fatalf("failed to marshal filter: %v", err)
 		}
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: string(filterJSON)}).Times(1).Return([]linodego.Instance{instance}, nil)
-		client.EXPECT().ListVPCIPAddresses(gomock.Any(), VpcIDs["test"], gomock.Any()).Return(routesInVPC, nil)
-		client.EXPECT().ListVPCIPv6Addresses(gomock.Any(), VpcIDs["test"], gomock.Any()).Return([]linodego.VPCIP{}, nil)
+		client.EXPECT().ListVPCIPAddresses(gomock.Any(), vpcs.vpcIDs["test"], gomock.Any()).Return(routesInVPC, nil)
+		client.EXPECT().ListVPCIPv6Addresses(gomock.Any(), vpcs.vpcIDs["test"], gomock.Any()).Return([]linodego.VPCIP{}, nil)
 
 		meta, err := instances.InstanceMetadata(ctx, node)
 		require.NoError(t, err)
@@ -239,9 +247,6 @@ func TestMetadataRetrieval(t *testing.T) {
 				Address: ipv6Addr,
 			},
 		}, meta.NodeAddresses)
-
-		options.Options.VPCNames = []string{}
-		options.Options.LinodeTagFilter = ""
 	})
 
 	ipTests := []struct {
@@ -338,15 +343,16 @@ func TestMetadataRetrieval(t *testing.T) {
 
 	for _, test := range ipTests {
 		t.Run(fmt.Sprintf("addresses are retrieved - %s", test.name), func(t *testing.T) {
-			instances := NewInstances(client)
+			opts := &options.Config{}
+			instances := NewInstances(client, opts, NewVPCCache(opts))
 			id := 192910
 			name := "my-instance"
 			providerID := ccmUtils.ProviderIDPrefix + strconv.Itoa(id)
 			node := nodeWithProviderID(providerID)
 			if test.externalNetwork == "" {
-				options.Options.LinodeExternalNetwork = nil
+				opts.LinodeExternalNetwork = nil
 			} else {
-				_, options.Options.LinodeExternalNetwork, _ = net.ParseCIDR(test.externalNetwork)
+				_, opts.LinodeExternalNetwork, _ = net.ParseCIDR(test.externalNetwork)
 			}
 			if test.existingAddresses != nil {
 				node.Status.Addresses = append(node.Status.Addresses, test.existingAddresses...)
@@ -430,7 +436,8 @@ func TestMetadataRetrieval(t *testing.T) {
 
 		for _, test := range getByIPTests {
 			t.Run(fmt.Sprintf("gets linode by IP - %s", test.name), func(t *testing.T) {
-				instances := NewInstances(client)
+				opts := &options.Config{}
+				instances := NewInstances(client, opts, NewVPCCache(opts))
 				client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{{ID: 3456, IPv4: []net.IP{wrongIP}}, expectedInstance}, nil)
 				node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"}, Status: v1.NodeStatus{Addresses: test.nodeAddresses}}
 				meta, err := instances.InstanceMetadata(ctx, &node)
@@ -454,7 +461,8 @@ func TestMalformedProviders(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 
 	t.Run("fails on non-numeric providerID", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		providerID := ccmUtils.ProviderIDPrefix + "abc"
 		node := nodeWithProviderID(providerID)
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, nil)
@@ -473,15 +481,13 @@ func TestRefreshInstancesVPCErrors(t *testing.T) {
 	c := mocks.NewMockClient(ctrl)
 
 	t.Run("returns error when GetVPCIPAddresses fails", func(t *testing.T) {
-		instances := NewInstances(c)
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		instances := NewInstances(c, opts, vpcs)
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + "123")
 
-		options.Options.VPCNames = []string{"test"}
-		VpcIDs["test"] = 1
-		defer func() {
-			options.Options.VPCNames = []string{}
-			delete(VpcIDs, "test")
-		}()
+		opts.VPCNames = []string{"test"}
+		vpcs.SetVPCID("test", 1)
 
 		apiErr := fmt.Errorf("VPC API error")
 		c.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{
@@ -495,15 +501,13 @@ func TestRefreshInstancesVPCErrors(t *testing.T) {
 	})
 
 	t.Run("returns error when GetVPCIPv6Addresses fails", func(t *testing.T) {
-		instances := NewInstances(c)
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		instances := NewInstances(c, opts, vpcs)
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + "123")
 
-		options.Options.VPCNames = []string{"test"}
-		VpcIDs["test"] = 1
-		defer func() {
-			options.Options.VPCNames = []string{}
-			delete(VpcIDs, "test")
-		}()
+		opts.VPCNames = []string{"test"}
+		vpcs.SetVPCID("test", 1)
 
 		vpcIP := "10.0.0.2"
 		apiErr := fmt.Errorf("VPC IPv6 API error")
@@ -521,15 +525,13 @@ func TestRefreshInstancesVPCErrors(t *testing.T) {
 	})
 
 	t.Run("does not update cache when GetVPCIPAddresses fails", func(t *testing.T) {
-		instances := NewInstances(c)
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		instances := NewInstances(c, opts, vpcs)
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + "123")
 
-		options.Options.VPCNames = []string{"test"}
-		VpcIDs["test"] = 1
-		defer func() {
-			options.Options.VPCNames = []string{}
-			delete(VpcIDs, "test")
-		}()
+		opts.VPCNames = []string{"test"}
+		vpcs.SetVPCID("test", 1)
 
 		apiErr := fmt.Errorf("VPC API error")
 		c.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{
@@ -546,15 +548,13 @@ func TestRefreshInstancesVPCErrors(t *testing.T) {
 	})
 
 	t.Run("does not update cache when GetVPCIPv6Addresses fails", func(t *testing.T) {
-		instances := NewInstances(c)
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		instances := NewInstances(c, opts, vpcs)
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + "123")
 
-		options.Options.VPCNames = []string{"test"}
-		VpcIDs["test"] = 1
-		defer func() {
-			options.Options.VPCNames = []string{}
-			delete(VpcIDs, "test")
-		}()
+		opts.VPCNames = []string{"test"}
+		vpcs.SetVPCID("test", 1)
 
 		vpcIP := "10.0.0.2"
 		apiErr := fmt.Errorf("VPC IPv6 API error")
@@ -583,7 +583,8 @@ func TestInstanceShutdown(t *testing.T) {
 	client := mocks.NewMockClient(ctrl)
 
 	t.Run("fails when instance not found (by provider)", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		id := 12345
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + strconv.Itoa(id))
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, nil)
@@ -594,7 +595,8 @@ func TestInstanceShutdown(t *testing.T) {
 	})
 
 	t.Run("fails when instance not found (by name)", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		name := "some-name"
 		node := nodeWithName(name)
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{}, nil)
@@ -605,7 +607,8 @@ func TestInstanceShutdown(t *testing.T) {
 	})
 
 	t.Run("returns true when instance is shut down", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		id := 12345
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + strconv.Itoa(id))
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{
@@ -618,7 +621,8 @@ func TestInstanceShutdown(t *testing.T) {
 	})
 
 	t.Run("returns true when instance is shutting down", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		id := 12345
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + strconv.Itoa(id))
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{
@@ -631,7 +635,8 @@ func TestInstanceShutdown(t *testing.T) {
 	})
 
 	t.Run("returns false when instance is running", func(t *testing.T) {
-		instances := NewInstances(client)
+		opts := &options.Config{}
+		instances := NewInstances(client, opts, NewVPCCache(opts))
 		id := 12345
 		node := nodeWithProviderID(ccmUtils.ProviderIDPrefix + strconv.Itoa(id))
 		client.EXPECT().ListInstances(gomock.Any(), &linodego.ListOptions{PageSize: linodeClient.MaxPageSize, Filter: "{}"}).Times(1).Return([]linodego.Instance{
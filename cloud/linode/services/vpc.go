@@ -16,13 +16,25 @@ import (
 	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
 )
 
-var (
-	Mu sync.RWMutex
-	// VpcIDs map stores vpc id's for given vpc labels
-	VpcIDs = make(map[string]int, 0)
-	// SubnetIDs map stores subnet id's for given subnet labels
-	SubnetIDs = make(map[string]int, 0)
-)
+// VPCCache resolves the VPC and subnet labels configured for the cloud provider to their ids
+// and caches the results.
+type VPCCache struct {
+	mu sync.RWMutex
+	// vpcIDs map stores vpc id's for given vpc labels
+	vpcIDs map[string]int
+	// subnetIDs map stores subnet id's for given subnet labels
+	subnetIDs map[string]int
+	options   *options.Config
+}
+
+// NewVPCCache returns an empty VPCCache for the VPCs and subnets of the given configuration.
+func NewVPCCache(opts *options.Config) *VPCCache {
+	return &VPCCache{
+		vpcIDs:    make(map[string]int, 0),
+		subnetIDs: make(map[string]int, 0),
+		options:   opts,
+	}
+}
 
 type vpcLookupError struct {
 	value string
@@ -44,24 +56,38 @@ func (e subnetLookupError) Error() string {
 	return fmt.Sprintf("failed to find subnet: %q", e.value)
 }
 
+// SetVPCID stores the id of the given VPC label
+func (c *VPCCache) SetVPCID(vpcName string, vpcID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.vpcIDs[vpcName] = vpcID
+}
+
+// SetSubnetID stores the id of the given subnet label
+func (c *VPCCache) SetSubnetID(subnetName string, subnetID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subnetIDs[subnetName] = subnetID
+}
+
 // GetAllVPCIDs returns vpc ids stored in map
-func GetAllVPCIDs() []int {
-	Mu.Lock()
-	defer Mu.Unlock()
-	values := make([]int, 0, len(VpcIDs))
-	for _, v := range VpcIDs {
+func (c *VPCCache) GetAllVPCIDs() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([]int, 0, len(c.vpcIDs))
+	for _, v := range c.vpcIDs {
 		values = append(values, v)
 	}
 	return values
 }
 
 // GetVPCID returns the VPC id of given VPC label
-func GetVPCID(ctx context.Context, client client.Client, vpcName string) (int, error) {
-	Mu.Lock()
-	defer Mu.Unlock()
+func (c *VPCCache) GetVPCID(ctx context.Context, client client.Client, vpcName string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	// check if map contains vpc id for given label
-	if vpcid, ok := VpcIDs[vpcName]; ok {
+	if vpcid, ok := c.vpcIDs[vpcName]; ok {
 		return vpcid, nil
 	}
 	vpcs, err := client.ListVPCs(ctx, &linodego.ListOptions{})
@@ -70,7 +96,7 @@ func GetVPCID(ctx context.Context, client client.Client, vpcName string) (int, e
 	}
 	for _, vpc := range vpcs {
 		if vpc.Label == vpcName {
-			VpcIDs[vpcName] = vpc.ID
+			c.vpcIDs[vpcName] = vpc.ID
 			return vpc.ID, nil
 		}
 	}
@@ -78,12 +104,12 @@ func GetVPCID(ctx context.Context, client client.Client, vpcName string) (int, e
 }
 
 // GetSubnetID returns the subnet ID of given subnet label
-func GetSubnetID(ctx context.Context, client client.Client, vpcID int, subnetName string) (int, error) {
-	Mu.Lock()
-	defer Mu.Unlock()
+func (c *VPCCache) GetSubnetID(ctx context.Context, client client.Client, vpcID int, subnetName string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	// Check if map contains the id for the given label
-	if subnetid, ok := SubnetIDs[subnetName]; ok {
+	if subnetid, ok := c.subnetIDs[subnetName]; ok {
 		return subnetid, nil
 	}
 	// Otherwise, get it from linodego.ListVPCSubnets()
@@ -93,7 +119,7 @@ func GetSubnetID(ctx context.Context, client client.Client, vpcID int, subnetNam
 	}
 	for _, subnet := range subnets {
 		if subnet.Label == subnetName {
-			SubnetIDs[subnetName] = subnet.ID
+			c.subnetIDs[subnetName] = subnet.ID
 			return subnet.ID, nil
 		}
 	}
@@ -102,8 +128,8 @@ func GetSubnetID(ctx context.Context, client client.Client, vpcID int, subnetNam
 }
 
 // getVPCIDAndFilter returns the VPC ID and a resultFilter for subnet names (if any)
-func getVPCIDAndFilter(ctx context.Context, client client.Client, vpcName string) (int, string, error) {
-	vpcID, err := GetVPCID(ctx, client, strings.TrimSpace(vpcName))
+func (c *VPCCache) getVPCIDAndFilter(ctx context.Context, client client.Client, vpcName string) (int, string, error) {
+	vpcID, err := c.GetVPCID(ctx, client, strings.TrimSpace(vpcName))
 	if err != nil {
 		return 0, "", err
 	}
@@ -111,14 +137,14 @@ func getVPCIDAndFilter(ctx context.Context, client client.Client, vpcName string
 	resultFilter := ""
 
 	// Get subnet ID(s) from name(s) if subnet-names is specified
-	if len(options.Options.SubnetNames) > 0 {
+	if len(c.options.SubnetNames) > 0 {
 		// subnetIDList is a slice of strings for ease of use with resultFilter
 		subnetIDList := []string{}
 
-		for _, name := range options.Options.SubnetNames {
+		for _, name := range c.options.SubnetNames {
 			// For caching
 			var subnetID int
-			subnetID, err = GetSubnetID(ctx, client, vpcID, name)
+			subnetID, err = c.GetSubnetID(ctx, client, vpcID, name)
 			// Don't filter subnets we can't find
 			if err != nil {
 				klog.Errorf("subnet %s not found due to error: %v. Skipping.", name, err)
@@ -143,83 +169,83 @@ func getVPCIDAndFilter(ctx context.Context, client client.Client, vpcName string
 }
 
 // handleNotFoundError checks if the error is a '404 Not Found error' and deletes the entry from the cache.
-func handleNotFoundError(err error, vpcName string) error {
+func (c *VPCCache) handleNotFoundError(err error, vpcName string) error {
 	if linodego.ErrHasStatus(err, http.StatusNotFound) {
-		Mu.Lock()
-		defer Mu.Unlock()
+		c.mu.Lock()
+		defer c.mu.Unlock()
 		klog.Errorf("vpc %s not found. Deleting entry from cache", vpcName)
-		delete(VpcIDs, vpcName)
+		delete(c.vpcIDs, vpcName)
 	}
 	return err
 }
 
 // GetVPCIPAddresses returns vpc ip's for given VPC label
-func GetVPCIPAddresses(ctx context.Context, client client.Client, vpcName string) ([]linodego.VPCIP, error) {
-	vpcID, resultFilter, err := getVPCIDAndFilter(ctx, client, vpcName)
+func (c *VPCCache) GetVPCIPAddresses(ctx context.Context, client client.Client, vpcName string) ([]linodego.VPCIP, error) {
+	vpcID, resultFilter, err := c.getVPCIDAndFilter(ctx, client, vpcName)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := client.ListVPCIPAddresses(ctx, vpcID, linodego.NewListOptions(0, resultFilter))
 	if err != nil {
-		return nil, handleNotFoundError(err, vpcName)
+		return nil, c.handleNotFoundError(err, vpcName)
 	}
 	return resp, nil
 }
 
-func GetVPCIPv6Addresses(ctx context.Context, client client.Client, vpcName string) ([]linodego.VPCIP, error) {
-	vpcID, resultFilter, err := getVPCIDAndFilter(ctx, client, vpcName)
+func (c *VPCCache) GetVPCIPv6Addresses(ctx context.Context, client client.Client, vpcName string) ([]linodego.VPCIP, error) {
+	vpcID, resultFilter, err := c.getVPCIDAndFilter(ctx, client, vpcName)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := client.ListVPCIPv6Addresses(ctx, vpcID, linodego.NewListOptions(0, resultFilter))
 	if err != nil {
-		return nil, handleNotFoundError(err, vpcName)
+		return nil, c.handleNotFoundError(err, vpcName)
 	}
 	return resp, nil
 }
 
 // GetNodeBalancerBackendIPv4SubnetID returns the subnet ID for the NodeBalancer backend IPv4 subnet.
-// It uses the first VPC name from Options.VPCNames to find the VPC ID and then retrieves the subnet ID
-// for the NodeBalancer backend IPv4 subnet name specified in Options.NodeBalancerBackendIPv4SubnetName.
-func GetNodeBalancerBackendIPv4SubnetID(client client.Client) (int, error) {
+// It uses the first configured VPC name to find the VPC ID and then retrieves the subnet ID
+// for the configured NodeBalancer backend IPv4 subnet name.
+func (c *VPCCache) GetNodeBalancerBackendIPv4SubnetID(client client.Client) (int, error) {
 	// Get the VPC ID from the name
-	vpcID, err := GetVPCID(context.TODO(), client, options.Options.VPCNames[0])
+	vpcID, err := c.GetVPCID(context.TODO(), client, c.options.VPCNames[0])
 	if err != nil {
-		return 0, fmt.Errorf("failed to get vpc id for %s: %w", options.Options.VPCNames[0], err)
+		return 0, fmt.Errorf("failed to get vpc id for %s: %w", c.options.VPCNames[0], err)
 	}
 	// Get the subnet ID from the name
-	subnetID, err := GetSubnetID(context.TODO(), client, vpcID, options.Options.NodeBalancerBackendIPv4SubnetName)
+	subnetID, err := c.GetSubnetID(context.TODO(), client, vpcID, c.options.NodeBalancerBackendIPv4SubnetName)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get subnet id for %s: %w", options.Options.NodeBalancerBackendIPv4SubnetName, err)
+		return 0, fmt.Errorf("failed to get subnet id for %s: %w", c.options.NodeBalancerBackendIPv4SubnetName, err)
 	}
 
 	return subnetID, nil
 }
 
 // validateBothNameAndIDNotSet checks if both VPC IDs and names are set.
-func validateBothNameAndIDNotSet() error {
-	if len(options.Options.VPCIDs) != 0 && len(options.Options.VPCNames) != 0 {
+func validateBothNameAndIDNotSet(opts *options.Config) error {
+	if len(opts.VPCIDs) != 0 && len(opts.VPCNames) != 0 {
 		return fmt.Errorf("cannot have both vpc-ids and vpc-names set")
 	}
 	return nil
 }
 
 // validateVPCAndSubnetPairing checks if both VPC and subnet IDs or names are set correctly.
-func validateVPCAndSubnetPairing() error {
-	if len(options.Options.VPCIDs) != 0 && len(options.Options.SubnetIDs) == 0 {
+func validateVPCAndSubnetPairing(opts *options.Config) error {
+	if len(opts.VPCIDs) != 0 && len(opts.SubnetIDs) == 0 {
 		return fmt.Errorf("vpc-ids cannot be set without subnet-ids")
 	}
-	if len(options.Options.SubnetIDs) != 0 && len(options.Options.VPCIDs) == 0 {
+	if len(opts.SubnetIDs) != 0 && len(opts.VPCIDs) == 0 {
 		return fmt.Errorf("subnet-ids cannot be set without vpc-ids")
 	}
 	return nil
 }
 
 // validateSubnetNamesWithRouteController checks if subnet-names are set without vpc-names
-func validateSubnetNamesWithRouteController() error {
-	if len(options.Options.SubnetNames) != 0 && len(options.Options.VPCNames) == 0 && options.Options.EnableRouteController {
+func validateSubnetNamesWithRouteController(opts *options.Config) error {
+	if len(opts.SubnetNames) != 0 && len(opts.VPCNames) == 0 && opts.EnableRouteController {
 		return fmt.Errorf("subnet-names cannot be set without vpc-names")
 	}
 	return nil
@@ -229,29 +255,29 @@ func validateSubnetNamesWithRouteController() error {
 // It checks that both VPC and subnet IDs or names are set correctly, and that they are paired correctly.
 // It also checks that if subnet names are set, VPC names must also be set,
 // and that if subnet IDs are set, VPC IDs must also be set.
-func validateVPCSubnetFlags() error {
-	if err := validateBothNameAndIDNotSet(); err != nil {
+func validateVPCSubnetFlags(opts *options.Config) error {
+	if err := validateBothNameAndIDNotSet(opts); err != nil {
 		return err
 	}
-	if err := validateVPCAndSubnetPairing(); err != nil {
+	if err := validateVPCAndSubnetPairing(opts); err != nil {
 		return err
 	}
-	if err := validateSubnetNamesWithRouteController(); err != nil {
+	if err := validateSubnetNamesWithRouteController(opts); err != nil {
 		return err
 	}
 	return nil
 }
 
 // resolveSubnetNames resolves subnet ids to names for the given VPC ID.
-func resolveSubnetNames(client client.Client, vpcID int) ([]string, error) {
+func (c *VPCCache) resolveSubnetNames(client client.Client, vpcID int) ([]string, error) {
 	subnetNames := []string{}
-	for _, subnetID := range options.Options.SubnetIDs {
+	for _, subnetID := range c.options.SubnetIDs {
 		subnet, err := client.GetVPCSubnet(context.TODO(), vpcID, subnetID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get subnet %d for VPC %d: %w", subnetID, vpcID, err)
 		}
 
-		SubnetIDs[subnet.Label] = subnet.ID
+		c.subnetIDs[subnet.Label] = subnet.ID
 		subnetNames = append(subnetNames, subnet.Label)
 	}
 
@@ -261,43 +287,43 @@ func resolveSubnetNames(client client.Client, vpcID int) ([]string, error) {
 // ValidateAndSetVPCSubnetFlags validates the VPC and subnet flags and sets the vpcNames and subnetNames options.
 // It retrieves the VPC names and subnet names from the Linode API based on the provided flags.
 // If subnet IDs are provided, it resolves the subnet names based on the first VPC ID.
-func ValidateAndSetVPCSubnetFlags(client client.Client) error {
+func (c *VPCCache) ValidateAndSetVPCSubnetFlags(client client.Client) error {
 	// ignore default subnet-names if subnet-ids are set
-	if len(options.Options.SubnetIDs) > 0 {
-		options.Options.SubnetNames = []string{}
+	if len(c.options.SubnetIDs) > 0 {
+		c.options.SubnetNames = []string{}
 	}
 
-	if err := validateVPCSubnetFlags(); err != nil {
+	if err := validateVPCSubnetFlags(c.options); err != nil {
 		return err
 	}
 
-	Mu.Lock()
-	defer Mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	vpcNames := []string{}
-	for idx, vpcID := range options.Options.VPCIDs {
+	for idx, vpcID := range c.options.VPCIDs {
 		vpc, err := client.GetVPC(context.TODO(), vpcID)
 		if err != nil {
 			return fmt.Errorf("failed to get VPC %d: %w", vpcID, err)
 		}
 
-		VpcIDs[vpc.Label] = vpcID
+		c.vpcIDs[vpc.Label] = vpcID
 		vpcNames = append(vpcNames, vpc.Label)
 
 		// we resolve subnet names only for the first VPC ID
 		// as there is no vpc to subnet mapping in input flags
 		// and we assume all subnets are in the same VPC
-		if idx == 0 && len(options.Options.SubnetIDs) > 0 {
-			subnetNames, err := resolveSubnetNames(client, vpcID)
+		if idx == 0 && len(c.options.SubnetIDs) > 0 {
+			subnetNames, err := c.resolveSubnetNames(client, vpcID)
 			if err != nil {
 				return err
 			}
-			options.Options.SubnetNames = append(options.Options.SubnetNames, subnetNames...)
+			c.options.SubnetNames = append(c.options.SubnetNames, subnetNames...)
 		}
 	}
 
-	options.Options.VPCNames = append(options.Options.VPCNames, vpcNames...)
+	c.options.VPCNames = append(c.options.VPCNames, vpcNames...)
 	klog.V(3).Infof("VPC IDs: %v, VPC Names: %v, Subnet IDs: %v, Subnet Names: %v",
-		options.Options.VPCIDs, options.Options.VPCNames, options.Options.SubnetIDs, options.Options.SubnetNames)
+		c.options.VPCIDs, c.options.VPCNames, c.options.SubnetIDs, c.options.SubnetNames)
 	return nil
 }
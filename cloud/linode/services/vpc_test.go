@@ -35,8 +35,9 @@ func TestGetAllVPCIDs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			VpcIDs = tt.vpcIDs
-			got := GetAllVPCIDs()
+			vpcs := NewVPCCache(&options.Config{})
+			vpcs.vpcIDs = tt.vpcIDs
+			got := vpcs.GetAllVPCIDs()
 			sort.Ints(got)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetAllVPCIDs() = %v, want %v", got, tt.want)
@@ -50,14 +51,15 @@ func TestGetVPCID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
-		got, err := GetVPCID(t.Context(), client, "test3")
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		got, err := vpcs.GetVPCID(t.Context(), client, "test3")
 		if err != nil {
 			t.Errorf("GetVPCID() error = %v", err)
 			return
 		}
-		if got != VpcIDs["test3"] {
-			t.Errorf("GetVPCID() = %v, want %v", got, VpcIDs["test3"])
+		if got != vpcs.vpcIDs["test3"] {
+			t.Errorf("GetVPCID() = %v, want %v", got, vpcs.vpcIDs["test3"])
 		}
 	})
 
@@ -65,9 +67,10 @@ func TestGetVPCID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCs(gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPC{}, errors.New("error"))
-		got, err := GetVPCID(t.Context(), client, "test4")
+		got, err := vpcs.GetVPCID(t.Context(), client, "test4")
 		require.Error(t, err)
 		if got != 0 {
 			t.Errorf("GetVPCID() = %v, want %v", got, 0)
@@ -78,9 +81,10 @@ func TestGetVPCID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCs(gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPC{}, nil)
-		got, err := GetVPCID(t.Context(), client, "test4")
+		got, err := vpcs.GetVPCID(t.Context(), client, "test4")
 		require.ErrorIs(t, err, vpcLookupError{"test4"})
 		if got != 0 {
 			t.Errorf("GetVPCID() = %v, want %v", got, 0)
@@ -91,9 +95,10 @@ func TestGetVPCID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCs(gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPC{{ID: 4, Label: "test4"}}, nil)
-		got, err := GetVPCID(t.Context(), client, "test4")
+		got, err := vpcs.GetVPCID(t.Context(), client, "test4")
 		require.NoError(t, err)
 		if got != 4 {
 			t.Errorf("GetVPCID() = %v, want %v", got, 4)
@@ -106,9 +111,10 @@ func TestGetVPCIPAddresses(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCs(gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPC{}, nil)
-		_, err := GetVPCIPAddresses(t.Context(), client, "test4")
+		_, err := vpcs.GetVPCIPAddresses(t.Context(), client, "test4")
 		require.Error(t, err)
 	})
 
@@ -116,11 +122,12 @@ func TestGetVPCIPAddresses(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCIPAddresses(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCIP{}, &linodego.Error{Code: http.StatusNotFound, Message: "[404] [label] VPC not found"})
-		_, err := GetVPCIPAddresses(t.Context(), client, "test3")
+		_, err := vpcs.GetVPCIPAddresses(t.Context(), client, "test3")
 		require.Error(t, err)
-		_, exists := VpcIDs["test3"]
+		_, exists := vpcs.vpcIDs["test3"]
 		assert.False(t, exists, "test3 key should get deleted from vpcIDs map")
 	})
 
@@ -128,11 +135,12 @@ func TestGetVPCIPAddresses(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCIPAddresses(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCIP{}, &linodego.Error{Code: http.StatusInternalServerError, Message: "[500] [label] Internal Server Error"})
-		_, err := GetVPCIPAddresses(t.Context(), client, "test1")
+		_, err := vpcs.GetVPCIPAddresses(t.Context(), client, "test1")
 		require.Error(t, err)
-		_, exists := VpcIDs["test1"]
+		_, exists := vpcs.vpcIDs["test1"]
 		assert.True(t, exists, "test1 key should not get deleted from vpcIDs map")
 	})
 
@@ -140,12 +148,13 @@ func TestGetVPCIPAddresses(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		VpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.vpcIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCs(gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPC{{ID: 10, Label: "test10"}}, nil)
 		client.EXPECT().ListVPCIPAddresses(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCIP{}, nil)
-		_, err := GetVPCIPAddresses(t.Context(), client, "test10")
+		_, err := vpcs.GetVPCIPAddresses(t.Context(), client, "test10")
 		require.NoError(t, err)
-		_, exists := VpcIDs["test10"]
+		_, exists := vpcs.vpcIDs["test10"]
 		assert.True(t, exists, "test10 key should be present in vpcIDs map")
 	})
 
@@ -153,17 +162,17 @@ func TestGetVPCIPAddresses(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		sn := options.Options.SubnetNames
-		defer func() { options.Options.SubnetNames = sn }()
-		options.Options.SubnetNames = []string{"subnet4"}
-		VpcIDs = map[string]int{"test1": 1}
-		SubnetIDs = map[string]int{"subnet1": 1}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.SubnetNames = []string{"subnet4"}
+		vpcs.vpcIDs = map[string]int{"test1": 1}
+		vpcs.subnetIDs = map[string]int{"subnet1": 1}
 		client.EXPECT().ListVPCs(gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPC{{ID: 10, Label: "test10"}}, nil)
 		client.EXPECT().ListVPCSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCSubnet{{ID: 4, Label: "subnet4"}}, nil)
 		client.EXPECT().ListVPCIPAddresses(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCIP{}, nil)
-		_, err := GetVPCIPAddresses(t.Context(), client, "test10")
+		_, err := vpcs.GetVPCIPAddresses(t.Context(), client, "test10")
 		require.NoError(t, err)
-		_, exists := SubnetIDs["subnet4"]
+		_, exists := vpcs.subnetIDs["subnet4"]
 		assert.True(t, exists, "subnet4 should be present in subnetIDs map")
 	})
 }
@@ -173,14 +182,15 @@ func TestGetSubnetID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		SubnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
-		got, err := GetSubnetID(t.Context(), client, 0, "test3")
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.subnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		got, err := vpcs.GetSubnetID(t.Context(), client, 0, "test3")
 		if err != nil {
 			t.Errorf("GetSubnetID() error = %v", err)
 			return
 		}
-		if got != SubnetIDs["test3"] {
-			t.Errorf("GetSubnetID() = %v, want %v", got, SubnetIDs["test3"])
+		if got != vpcs.subnetIDs["test3"] {
+			t.Errorf("GetSubnetID() = %v, want %v", got, vpcs.subnetIDs["test3"])
 		}
 	})
 
@@ -188,14 +198,15 @@ func TestGetSubnetID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		SubnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.subnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCSubnet{}, errors.New("error"))
-		got, err := GetSubnetID(t.Context(), client, 0, "test4")
+		got, err := vpcs.GetSubnetID(t.Context(), client, 0, "test4")
 		require.Error(t, err)
 		if got != 0 {
 			t.Errorf("GetSubnetID() = %v, want %v", got, 0)
 		}
-		_, exists := SubnetIDs["test4"]
+		_, exists := vpcs.subnetIDs["test4"]
 		assert.False(t, exists, "subnet4 should not be present in subnetIDs")
 	})
 
@@ -203,9 +214,10 @@ func TestGetSubnetID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		SubnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.subnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCSubnet{}, nil)
-		got, err := GetSubnetID(t.Context(), client, 0, "test4")
+		got, err := vpcs.GetSubnetID(t.Context(), client, 0, "test4")
 		require.ErrorIs(t, err, subnetLookupError{"test4"})
 		if got != 0 {
 			t.Errorf("GetSubnetID() = %v, want %v", got, 0)
@@ -216,9 +228,10 @@ func TestGetSubnetID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		SubnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		vpcs := NewVPCCache(&options.Config{})
+		vpcs.subnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCSubnet{{ID: 4, Label: "test4"}}, nil)
-		got, err := GetSubnetID(t.Context(), client, 0, "test4")
+		got, err := vpcs.GetSubnetID(t.Context(), client, 0, "test4")
 		require.NoError(t, err)
 		if got != 4 {
 			t.Errorf("GetSubnetID() = %v, want %v", got, 4)
@@ -231,19 +244,13 @@ func TestGetNodeBalancerBackendIPv4SubnetID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		currVPCNames := options.Options.VPCNames
-		currVPCIDs := VpcIDs
-		currSubnetIDs := SubnetIDs
-		defer func() {
-			options.Options.VPCNames = currVPCNames
-			VpcIDs = currVPCIDs
-			SubnetIDs = currSubnetIDs
-		}()
-		options.Options.VPCNames = []string{"vpc-test1", "vpc-test2", "vpc-test3"}
-		VpcIDs = map[string]int{"vpc-test2": 2, "vpc-test3": 3}
-		SubnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.VPCNames = []string{"vpc-test1", "vpc-test2", "vpc-test3"}
+		vpcs.vpcIDs = map[string]int{"vpc-test2": 2, "vpc-test3": 3}
+		vpcs.subnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCs(gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPC{}, errors.New("error"))
-		subnetID, err := GetNodeBalancerBackendIPv4SubnetID(client)
+		subnetID, err := vpcs.GetNodeBalancerBackendIPv4SubnetID(client)
 		require.Error(t, err)
 		if subnetID != 0 {
 			t.Errorf("getNodeBalancerBackendIPv4SubnetID() = %v, want %v", subnetID, 0)
@@ -254,13 +261,13 @@ func TestGetNodeBalancerBackendIPv4SubnetID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		currVPCNames := options.Options.VPCNames
-		defer func() { options.Options.VPCNames = currVPCNames }()
-		options.Options.VPCNames = []string{"vpc-test1", "vpc-test2", "vpc-test3"}
-		VpcIDs = map[string]int{"vpc-test1": 1, "vpc-test2": 2, "vpc-test3": 3}
-		SubnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.VPCNames = []string{"vpc-test1", "vpc-test2", "vpc-test3"}
+		vpcs.vpcIDs = map[string]int{"vpc-test1": 1, "vpc-test2": 2, "vpc-test3": 3}
+		vpcs.subnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCSubnet{}, errors.New("error"))
-		subnetID, err := GetNodeBalancerBackendIPv4SubnetID(client)
+		subnetID, err := vpcs.GetNodeBalancerBackendIPv4SubnetID(client)
 		require.Error(t, err)
 		if subnetID != 0 {
 			t.Errorf("getNodeBalancerBackendIPv4SubnetID() = %v, want %v", subnetID, 0)
@@ -271,18 +278,14 @@ func TestGetNodeBalancerBackendIPv4SubnetID(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		currVPCNames := options.Options.VPCNames
-		currNodeBalancerBackendIPv4SubnetName := options.Options.NodeBalancerBackendIPv4SubnetName
-		defer func() {
-			options.Options.VPCNames = currVPCNames
-			options.Options.NodeBalancerBackendIPv4SubnetName = currNodeBalancerBackendIPv4SubnetName
-		}()
-		options.Options.VPCNames = []string{"vpc-test1", "vpc-test2", "vpc-test3"}
-		options.Options.NodeBalancerBackendIPv4SubnetName = "test4"
-		VpcIDs = map[string]int{"vpc-test1": 1, "vpc-test2": 2, "vpc-test3": 3}
-		SubnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.VPCNames = []string{"vpc-test1", "vpc-test2", "vpc-test3"}
+		opts.NodeBalancerBackendIPv4SubnetName = "test4"
+		vpcs.vpcIDs = map[string]int{"vpc-test1": 1, "vpc-test2": 2, "vpc-test3": 3}
+		vpcs.subnetIDs = map[string]int{"test1": 1, "test2": 2, "test3": 3}
 		client.EXPECT().ListVPCSubnets(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]linodego.VPCSubnet{{ID: 4, Label: "test4"}}, nil)
-		subnetID, err := GetNodeBalancerBackendIPv4SubnetID(client)
+		subnetID, err := vpcs.GetNodeBalancerBackendIPv4SubnetID(client)
 		require.NoError(t, err)
 		if subnetID != 4 {
 			t.Errorf("getNodeBalancerBackendIPv4SubnetID() = %v, want %v", subnetID, 4)
@@ -350,11 +353,14 @@ func Test_validateVPCSubnetFlags(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			options.Options.VPCIDs = tt.vpcIDs
-			options.Options.VPCNames = tt.vpcNames
-			options.Options.SubnetIDs = tt.subnetIDs
-			options.Options.SubnetNames = tt.subnetNames
-			if err := validateVPCSubnetFlags(); (err != nil) != tt.wantErr {
+			opts := &options.Config{
+				EnableRouteController: true,
+				VPCIDs:                tt.vpcIDs,
+				VPCNames:              tt.vpcNames,
+				SubnetIDs:             tt.subnetIDs,
+				SubnetNames:           tt.subnetNames,
+			}
+			if err := validateVPCSubnetFlags(opts); (err != nil) != tt.wantErr {
 				t.Errorf("validateVPCSubnetFlags() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -366,15 +372,11 @@ func Test_resolveSubnetNames(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		optionsSubnetIDs := options.Options.SubnetIDs
-		currSubnetIDs := SubnetIDs
-		defer func() {
-			options.Options.SubnetIDs = optionsSubnetIDs
-			SubnetIDs = currSubnetIDs
-		}()
-		options.Options.SubnetIDs = []int{}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.SubnetIDs = []int{}
 		// client.EXPECT().GetVPCSubnet(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(&linodego.VPCSubnet{}, errors.New("error"))
-		subnetNames, err := resolveSubnetNames(client, 10)
+		subnetNames, err := vpcs.resolveSubnetNames(client, 10)
 		if err != nil {
 			t.Errorf("resolveSubnetNames() error = %v", err)
 			return
@@ -388,15 +390,11 @@ func Test_resolveSubnetNames(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		optionsSubnetIDs := options.Options.SubnetIDs
-		currSubnetIDs := SubnetIDs
-		defer func() {
-			options.Options.SubnetIDs = optionsSubnetIDs
-			SubnetIDs = currSubnetIDs
-		}()
-		options.Options.SubnetIDs = []int{1}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.SubnetIDs = []int{1}
 		client.EXPECT().GetVPCSubnet(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(&linodego.VPCSubnet{}, errors.New("error"))
-		_, err := resolveSubnetNames(client, 10)
+		_, err := vpcs.resolveSubnetNames(client, 10)
 		require.Error(t, err)
 	})
 
@@ -404,15 +402,11 @@ func Test_resolveSubnetNames(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		optionsSubnetIDs := options.Options.SubnetIDs
-		currSubnetIDs := SubnetIDs
-		defer func() {
-			options.Options.SubnetIDs = optionsSubnetIDs
-			SubnetIDs = currSubnetIDs
-		}()
-		options.Options.SubnetIDs = []int{1}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.SubnetIDs = []int{1}
 		client.EXPECT().GetVPCSubnet(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(&linodego.VPCSubnet{ID: 1, Label: "subnet1"}, nil)
-		subnet, err := resolveSubnetNames(client, 10)
+		subnet, err := vpcs.resolveSubnetNames(client, 10)
 		require.NoError(t, err)
 		require.Equal(t, []string{"subnet1"}, subnet, "Expected subnet names to match")
 	})
@@ -423,9 +417,11 @@ func Test_validateAndSetVPCSubnetFlags(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		options.Options.VPCIDs = []int{1, 2}
-		options.Options.SubnetIDs = []int{}
-		err := ValidateAndSetVPCSubnetFlags(client)
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.VPCIDs = []int{1, 2}
+		opts.SubnetIDs = []int{}
+		err := vpcs.ValidateAndSetVPCSubnetFlags(client)
 		require.Error(t, err)
 	})
 
@@ -433,19 +429,13 @@ func Test_validateAndSetVPCSubnetFlags(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		currVPCIDs := options.Options.VPCIDs
-		currSubnetIDs := options.Options.SubnetIDs
-		defer func() {
-			options.Options.VPCIDs = currVPCIDs
-			options.Options.SubnetIDs = currSubnetIDs
-			VpcIDs = map[string]int{}
-			SubnetIDs = map[string]int{}
-		}()
-		options.Options.VPCIDs = []int{1}
-		options.Options.SubnetIDs = []int{1, 2}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.VPCIDs = []int{1}
+		opts.SubnetIDs = []int{1, 2}
 		client.EXPECT().GetVPC(gomock.Any(), gomock.Any()).Times(1).Return(&linodego.VPC{ID: 1, Label: "test"}, nil)
 		client.EXPECT().GetVPCSubnet(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(&linodego.VPCSubnet{ID: 1, Label: "subnet1"}, nil)
-		err := ValidateAndSetVPCSubnetFlags(client)
+		err := vpcs.ValidateAndSetVPCSubnetFlags(client)
 		require.NoError(t, err)
 	})
 
@@ -453,20 +443,14 @@ func Test_validateAndSetVPCSubnetFlags(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		currVPCIDs := options.Options.VPCIDs
-		currSubnetIDs := options.Options.SubnetIDs
-		defer func() {
-			options.Options.VPCIDs = currVPCIDs
-			options.Options.SubnetIDs = currSubnetIDs
-			VpcIDs = map[string]int{}
-			SubnetIDs = map[string]int{}
-		}()
-		options.Options.VPCIDs = []int{1}
-		options.Options.SubnetIDs = []int{1, 2}
-		options.Options.VPCNames = []string{}
-		options.Options.SubnetNames = []string{}
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.VPCIDs = []int{1}
+		opts.SubnetIDs = []int{1, 2}
+		opts.VPCNames = []string{}
+		opts.SubnetNames = []string{}
 		client.EXPECT().GetVPC(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("error"))
-		err := ValidateAndSetVPCSubnetFlags(client)
+		err := vpcs.ValidateAndSetVPCSubnetFlags(client)
 		require.Error(t, err)
 	})
 
@@ -474,19 +458,13 @@ func Test_validateAndSetVPCSubnetFlags(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		client := mocks.NewMockClient(ctrl)
-		currVPCNames := options.Options.VPCNames
-		currSubnetNames := options.Options.SubnetNames
-		defer func() {
-			options.Options.VPCNames = currVPCNames
-			options.Options.SubnetNames = currSubnetNames
-			VpcIDs = map[string]int{}
-			SubnetIDs = map[string]int{}
-		}()
-		options.Options.VPCNames = []string{"vpc1"}
-		options.Options.SubnetNames = []string{"subnet1", "subnet2"}
-		options.Options.VPCIDs = []int{}
-		options.Options.SubnetIDs = []int{}
-		err := ValidateAndSetVPCSubnetFlags(client)
+		opts := &options.Config{}
+		vpcs := NewVPCCache(opts)
+		opts.VPCNames = []string{"vpc1"}
+		opts.SubnetNames = []string{"subnet1", "subnet2"}
+		opts.VPCIDs = []int{}
+		opts.SubnetIDs = []int{}
+		err := vpcs.ValidateAndSetVPCSubnetFlags(client)
 		require.NoError(t, err)
 	})
 }
@@ -0,0 +1,213 @@
+package linode
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // the API may report SHA-1 fingerprints, they are only compared
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	v1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+)
+
+const eventCertificateRotatedName = "nodebalancer-certificate-rotated"
+
+// parseLeafCertificate returns the first certificate of a PEM encoded chain.
+func parseLeafCertificate(certPEM string) (*x509.Certificate, error) {
+	rest := []byte(certPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, errors.New("no PEM encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// certificateMatchesFingerprint reports whether fingerprint, as reported by the Linode API for a
+// NodeBalancer config, belongs to the leaf certificate of certPEM. SHA-256 and SHA-1 fingerprints
+// are accepted, in hex with or without colons. It returns false if either can't be parsed.
+func certificateMatchesFingerprint(certPEM, fingerprint string) bool {
+	deployed, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+	if err != nil {
+		return false
+	}
+
+	cert, err := parseLeafCertificate(certPEM)
+	if err != nil {
+		return false
+	}
+
+	switch len(deployed) {
+	case sha256.Size:
+		sum := sha256.Sum256(cert.Raw)
+		return string(sum[:]) == string(deployed)
+	case sha1.Size:
+		sum := sha1.Sum(cert.Raw) //nolint:gosec // only compared against the API fingerprint
+		return string(sum[:]) == string(deployed)
+	default:
+		return false
+	}
+}
+
+// certificateRotated reports whether certPEM replaces the certificate with the given fingerprint.
+// Unlike certificateMatchesFingerprint, it returns false if the fingerprint is unknown, so that
+// a missing or unexpected fingerprint is not reported as a rotation.
+func certificateRotated(certPEM, fingerprint string) bool {
+	deployed, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+	if err != nil || (len(deployed) != sha256.Size && len(deployed) != sha1.Size) {
+		return false
+	}
+	return !certificateMatchesFingerprint(certPEM, fingerprint)
+}
+
+// referencedTLSSecrets returns the namespace/name keys of the TLS secrets referenced by the port
+// config annotations of service.
+func referencedTLSSecrets(service *v1.Service) sets.Set[string] {
+	names := sets.New[string]()
+	for key, value := range service.GetAnnotations() {
+		if !strings.HasPrefix(key, annotations.AnnLinodePortConfigPrefix) {
+			continue
+		}
+		portConfig := portConfigAnnotation{}
+		if err := json.Unmarshal([]byte(value), &portConfig); err != nil {
+			continue
+		}
+		if portConfig.TLSSecretName != "" {
+			namespace, name := parseTLSSecretReference(service.Namespace, portConfig.TLSSecretName)
+			names.Insert(namespace + "/" + name)
+		}
+	}
+	return names
+}
+
+// recordCertificateExpiry exports the expiry of the certificate deployed on an HTTPS port of
+// the NodeBalancer of service.
+func recordCertificateExpiry(service *v1.Service, nbConfig *linodego.NodeBalancerConfig) {
+	cert, err := parseLeafCertificate(nbConfig.SSLCert)
+	if err != nil {
+		klog.Warningf("failed to parse TLS certificate of port %d for service (%s): %s", nbConfig.Port, getServiceNn(service), err)
+		return
+	}
+	nodeBalancerCertificateExpiryGaugeVec.
+		WithLabelValues(service.Namespace, service.Name, strconv.Itoa(nbConfig.Port)).
+		Set(float64(cert.NotAfter.Unix()))
+}
+
+// forgetCertificateExpiry stops exporting the certificate expiry of a port of service.
+func forgetCertificateExpiry(service *v1.Service, port int) {
+	nodeBalancerCertificateExpiryGaugeVec.DeleteLabelValues(service.Namespace, service.Name, strconv.Itoa(port))
+}
+
+func (l *loadbalancers) createCertificateRotatedEvent(ctx context.Context, service *v1.Service, nb *linodego.NodeBalancer, nbConfig *linodego.NodeBalancerConfig) {
+	message := fmt.Sprintf("TLS certificate of port %d on NodeBalancer (%d) was rotated", nbConfig.Port, nb.ID)
+	if cert, err := parseLeafCertificate(nbConfig.SSLCert); err == nil {
+		message = fmt.Sprintf("%s, it expires at %s", message, cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	l.createServiceEvent(ctx, service, eventCertificateRotatedName, "Normal", "NodeBalancerCertificateRotated", message)
+}
+
+// tlsSecretWatches watches the TLS secrets referenced by provisioned services, one secret per
+// watch, so that the CCM neither caches nor needs to receive any other secret. onChange is
+// called with the namespace/name key of a watched secret whose certificate or key changed.
+type tlsSecretWatches struct {
+	kubeClient kubernetes.Interface
+	onChange   func(key string)
+
+	mu      sync.Mutex
+	watches map[string]chan struct{}
+}
+
+func newTLSSecretWatches(kubeClient kubernetes.Interface, onChange func(key string)) *tlsSecretWatches {
+	return &tlsSecretWatches{
+		kubeClient: kubeClient,
+		onChange:   onChange,
+		watches:    map[string]chan struct{}{},
+	}
+}
+
+// sync starts watching the secrets in keys that aren't watched yet and stops watching the ones
+// that are no longer referenced.
+func (w *tlsSecretWatches) sync(keys sets.Set[string]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for key, stop := range w.watches {
+		if !keys.Has(key) {
+			klog.V(3).Infof("no longer watching TLS secret %s", key)
+			close(stop)
+			delete(w.watches, key)
+		}
+	}
+	for key := range keys {
+		if _, ok := w.watches[key]; ok {
+			continue
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			klog.Errorf("invalid TLS secret reference %s: %s", key, err)
+			continue
+		}
+		informer := v1informers.NewFilteredSecretInformer(w.kubeClient, namespace, 0, cache.Indexers{}, func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		})
+		if _, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldSecret, ok := oldObj.(*v1.Secret)
+				if !ok {
+					return
+				}
+				newSecret, ok := newObj.(*v1.Secret)
+				if !ok {
+					return
+				}
+				// Ports can read the certificate and key from any key of the secret
+				if !reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+					w.onChange(key)
+				}
+			},
+		}); err != nil {
+			klog.Errorf("failed to watch TLS secret %s: %s", key, err)
+			continue
+		}
+		klog.V(3).Infof("watching TLS secret %s", key)
+		stop := make(chan struct{})
+		w.watches[key] = stop
+		go informer.Run(stop)
+	}
+}
+
+// stop stops all watches.
+func (w *tlsSecretWatches) stop() {
+	w.sync(sets.New[string]())
+}
+
+// watched returns the keys of the watched secrets.
+func (w *tlsSecretWatches) watched() sets.Set[string] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return sets.KeySet(w.watches)
+}
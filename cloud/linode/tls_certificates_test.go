@@ -0,0 +1,228 @@
+package linode
+
+import (
+	"crypto/sha1" //nolint:gosec // the API may report SHA-1 fingerprints
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linode/linodego/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/annotations"
+	"github.com/linode/linode-cloud-controller-manager/cloud/apis/v1alpha1"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func Test_certificateMatchesFingerprint(t *testing.T) {
+	cert, err := parseLeafCertificate(testCert)
+	require.NoError(t, err)
+	sha256Sum := sha256.Sum256(cert.Raw)
+	sha1Sum := sha1.Sum(cert.Raw) //nolint:gosec // the API may report SHA-1 fingerprints
+
+	colonSeparated := make([]string, 0, len(sha256Sum))
+	for _, b := range sha256Sum {
+		colonSeparated = append(colonSeparated, strings.ToUpper(hex.EncodeToString([]byte{b})))
+	}
+
+	testcases := []struct {
+		name        string
+		certPEM     string
+		fingerprint string
+		expected    bool
+	}{
+		{
+			name:        "sha256",
+			certPEM:     testCert,
+			fingerprint: hex.EncodeToString(sha256Sum[:]),
+			expected:    true,
+		},
+		{
+			name:        "sha256 colon separated",
+			certPEM:     testCert,
+			fingerprint: strings.Join(colonSeparated, ":"),
+			expected:    true,
+		},
+		{
+			name:        "sha1",
+			certPEM:     testCert,
+			fingerprint: hex.EncodeToString(sha1Sum[:]),
+			expected:    true,
+		},
+		{
+			name:        "different certificate",
+			certPEM:     testCert,
+			fingerprint: strings.Repeat("ab", sha256.Size),
+			expected:    false,
+		},
+		{
+			name:        "fingerprint not hex",
+			certPEM:     testCert,
+			fingerprint: "sslfingerprint",
+			expected:    false,
+		},
+		{
+			name:        "certificate not PEM",
+			certPEM:     "not a certificate",
+			fingerprint: hex.EncodeToString(sha256Sum[:]),
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, certificateMatchesFingerprint(tc.certPEM, tc.fingerprint))
+		})
+	}
+}
+
+func Test_certificateRotated(t *testing.T) {
+	cert, err := parseLeafCertificate(testCert)
+	require.NoError(t, err)
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	assert.False(t, certificateRotated(testCert, hex.EncodeToString(fingerprint[:])), "same certificate")
+	assert.True(t, certificateRotated(testCert, strings.Repeat("ab", sha256.Size)), "different certificate")
+	assert.False(t, certificateRotated(testCert, ""), "unknown fingerprint")
+	assert.False(t, certificateRotated(testCert, "sslfingerprint"), "unexpected fingerprint")
+}
+
+func Test_referencedTLSSecrets(t *testing.T) {
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Annotations: map[string]string{
+			annotations.AnnLinodePortConfigPrefix + "443":  `{"protocol": "https", "tls-secret-name": "web-tls"}`,
+			annotations.AnnLinodePortConfigPrefix + "8443": `{"protocol": "https", "tls-secret-name": "cert-store/wildcard-tls"}`,
+			annotations.AnnLinodePortConfigPrefix + "80":   `{"protocol": "http"}`,
+			annotations.AnnLinodePortConfigPrefix + "9443": `not json`,
+		},
+	}}
+
+	assert.ElementsMatch(t, []string{"default/web-tls", "cert-store/wildcard-tls"}, referencedTLSSecrets(service).UnsortedList())
+}
+
+func Test_recordCertificateExpiry(t *testing.T) {
+	cert, err := parseLeafCertificate(testCert)
+	require.NoError(t, err)
+
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "expiry"}}
+	recordCertificateExpiry(service, &linodego.NodeBalancerConfig{Port: 443, SSLCert: testCert})
+	assert.InDelta(t, float64(cert.NotAfter.Unix()),
+		testutil.ToFloat64(nodeBalancerCertificateExpiryGaugeVec.WithLabelValues("expiry", "web", "443")), 0)
+
+	forgetCertificateExpiry(service, 443)
+	assert.False(t, nodeBalancerCertificateExpiryGaugeVec.DeleteLabelValues("expiry", "web", "443"))
+}
+
+func Test_serviceResyncController_enqueueTLSSecretServices(t *testing.T) {
+	kubeClient := fake.NewClientset()
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+	for name, serviceAnnotations := range map[string]map[string]string{
+		"web":   {annotations.AnnLinodePortConfigPrefix + "443": `{"protocol": "https", "tls-secret-name": "web-tls"}`},
+		"admin": {annotations.AnnLinodePortConfigPrefix + "443": `{"protocol": "https", "tls-secret-name": "cert-store/wildcard-tls"}`},
+		"api":   {annotations.AnnLinodeLoadBalancerConfig: "api-config"},
+	} {
+		require.NoError(t, serviceInformer.Informer().GetStore().Add(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: serviceAnnotations},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			}},
+		}))
+	}
+	lbConfig := &v1alpha1.LinodeLoadBalancerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-config", Namespace: "default"},
+		Spec: v1alpha1.LinodeLoadBalancerConfigSpec{
+			DefaultProtocol: "https",
+			Ports:           []v1alpha1.PortConfig{{Port: 443, TLSSecretName: "api-tls"}},
+		},
+	}
+	lb := &loadbalancers{options: &options.Config{}, dynamicClient: newFakeLoadBalancerConfigClient(t, lbConfig)}
+
+	controller := newServiceResyncController(lb, serviceInformer, factory.Core().V1().Nodes(), factory.Discovery().V1().EndpointSlices(), kubeClient)
+	defer controller.queue.ShutDown()
+	defer controller.tlsSecretQueue.ShutDown()
+
+	controller.enqueueTLSSecretServices(t.Context(), "other/web-tls")
+	assert.Equal(t, 0, controller.queue.Len())
+
+	for secret, service := range map[string]string{
+		"default/web-tls":         "default/web",
+		"cert-store/wildcard-tls": "default/admin",
+		"default/api-tls":         "default/api",
+	} {
+		controller.enqueueTLSSecretServices(t.Context(), secret)
+		require.Equal(t, 1, controller.queue.Len())
+		key, _ := controller.queue.Get()
+		assert.Equal(t, service, key)
+		controller.queue.Done(key)
+	}
+
+	// Secrets are only queued by the informer, the services referencing them are looked up by a worker
+	controller.tlsSecretQueue.Add("default/api-tls")
+	require.True(t, controller.processNextTLSSecret())
+	assert.Equal(t, 1, controller.queue.Len())
+}
+
+func Test_serviceResyncController_syncTLSSecretWatches(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-tls", Namespace: "default"},
+		Type:       v1.SecretTypeTLS,
+		Data:       map[string][]byte{v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key")},
+	}
+	kubeClient := fake.NewClientset(secret)
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	serviceInformer := factory.Core().V1().Services()
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+			Annotations: map[string]string{
+				annotations.AnnLinodePortConfigPrefix + "443": `{"protocol": "https", "tls-secret-name": "web-tls"}`,
+			},
+		},
+		Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+			Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+		}},
+	}
+	pending := service.DeepCopy()
+	pending.Name = "pending"
+	pending.Annotations[annotations.AnnLinodePortConfigPrefix+"443"] = `{"protocol": "https", "tls-secret-name": "pending-tls"}`
+	pending.Status = v1.ServiceStatus{}
+	require.NoError(t, serviceInformer.Informer().GetStore().Add(service))
+	require.NoError(t, serviceInformer.Informer().GetStore().Add(pending))
+
+	controller := newServiceResyncController(&loadbalancers{options: &options.Config{}}, serviceInformer, factory.Core().V1().Nodes(), factory.Discovery().V1().EndpointSlices(), kubeClient)
+	defer controller.queue.ShutDown()
+	defer controller.tlsSecretQueue.ShutDown()
+	defer controller.tlsSecrets.stop()
+
+	// Only secrets referenced by provisioned services are watched
+	controller.syncTLSSecretWatches()
+	assert.Equal(t, []string{"default/web-tls"}, controller.tlsSecrets.watched().UnsortedList())
+
+	// Rotating the certificate queues the secret
+	assert.Eventually(t, func() bool {
+		rotated := secret.DeepCopy()
+		rotated.Data[v1.TLSCertKey] = []byte(time.Now().String())
+		_, err := kubeClient.CoreV1().Secrets("default").Update(t.Context(), rotated, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		return controller.tlsSecretQueue.Len() > 0
+	}, 5*time.Second, 50*time.Millisecond)
+	key, _ := controller.tlsSecretQueue.Get()
+	assert.Equal(t, "default/web-tls", key)
+
+	require.NoError(t, serviceInformer.Informer().GetStore().Delete(service))
+	controller.syncTLSSecretWatches()
+	assert.Empty(t, controller.tlsSecrets.watched())
+}
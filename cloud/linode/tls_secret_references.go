@@ -0,0 +1,191 @@
+package linode
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+)
+
+// referenceGrantGVR identifies the Gateway API ReferenceGrant resource, which can permit
+// Services in one namespace to use TLS secrets in another.
+var referenceGrantGVR = schema.GroupVersionResource{
+	Group:    "gateway.networking.k8s.io",
+	Version:  "v1beta1",
+	Resource: "referencegrants",
+}
+
+// parseTLSSecretReference splits a tls-secret-name of the form "name" or "namespace/name" into
+// the namespace and name of the secret. Names without a namespace refer to serviceNamespace.
+func parseTLSSecretReference(serviceNamespace, reference string) (string, string) {
+	if namespace, name, ok := strings.Cut(reference, "/"); ok {
+		return namespace, name
+	}
+	return serviceNamespace, reference
+}
+
+// validateTLSSecretReference checks that a tls-secret-name is a valid secret name, optionally
+// prefixed with a namespace.
+func validateTLSSecretReference(reference string) error {
+	namespace, name := parseTLSSecretReference("", reference)
+	if strings.Contains(reference, "/") {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace in TLS secret reference %q: %s", reference, strings.Join(errs, ", "))
+		}
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid name in TLS secret reference %q: %s", reference, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// checkTLSSecretReference returns an error unless service may use the TLS secret in
+// secretNamespace. Secrets in the namespace of the service are always permitted. Secrets in other
+// namespaces are permitted when their namespace is in --tls-secret-namespace-allowlist, or when a
+// ReferenceGrant in their namespace allows Services from the namespace of service to use them.
+func (l *loadbalancers) checkTLSSecretReference(ctx context.Context, service *v1.Service, secretNamespace, secretName string) error {
+	if secretNamespace == service.Namespace || slices.Contains(l.options.TLSSecretNamespaceAllowlist, secretNamespace) {
+		return nil
+	}
+
+	granted, err := l.referenceGrantPermits(ctx, service.Namespace, secretNamespace, secretName)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return fmt.Errorf("service (%s) is not permitted to use TLS secret %s/%s: add namespace %s to --tls-secret-namespace-allowlist or create a ReferenceGrant in it",
+			getServiceNn(service), secretNamespace, secretName, secretNamespace)
+	}
+	return nil
+}
+
+// referenceGrantPermits reports whether a ReferenceGrant in secretNamespace allows Services in
+// serviceNamespace to reference the secret. It returns false if ReferenceGrants are not
+// installed in the cluster.
+func (l *loadbalancers) referenceGrantPermits(ctx context.Context, serviceNamespace, secretNamespace, secretName string) (bool, error) {
+	if err := l.retrieveDynamicClient(); err != nil {
+		return false, err
+	}
+
+	grants, err := l.dynamicClient.Resource(referenceGrantGVR).Namespace(secretNamespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.V(3).Infof("ReferenceGrants are not available, not permitting references to secrets in namespace %s", secretNamespace)
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to list ReferenceGrants in namespace %s: %w", secretNamespace, err)
+	}
+
+	for _, grant := range grants.Items {
+		if referenceGrantAllows(grant, serviceNamespace, secretName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// referenceGrantAllows reports whether grant allows Services in serviceNamespace to reference the
+// secret secretName in the namespace of the grant.
+func referenceGrantAllows(grant unstructured.Unstructured, serviceNamespace, secretName string) bool {
+	from, _, _ := unstructured.NestedSlice(grant.Object, "spec", "from")
+	fromAllowed := slices.ContainsFunc(from, func(entry interface{}) bool {
+		fields, ok := entry.(map[string]interface{})
+		return ok && fields["group"] == "" && fields["kind"] == "Service" && fields["namespace"] == serviceNamespace
+	})
+	if !fromAllowed {
+		return false
+	}
+
+	to, _, _ := unstructured.NestedSlice(grant.Object, "spec", "to")
+	return slices.ContainsFunc(to, func(entry interface{}) bool {
+		fields, ok := entry.(map[string]interface{})
+		if !ok || fields["group"] != "" || fields["kind"] != "Secret" {
+			return false
+		}
+		name, hasName := fields["name"]
+		return !hasName || name == "" || name == secretName
+	})
+}
+
+// validateTLSCertificate checks that cert, read from certField of the secret, is a PEM encoded
+// certificate chain and that key, read from keyField, is the private key of its leaf, so that
+// problems are reported in terms of the secret rather than as an opaque Linode API error. It
+// returns cert with the chain ordered from the leaf to its issuers, as NodeBalancers expect, since
+// bundles are often assembled in another order. Certificates of the bundle that aren't issuers of
+// the leaf are dropped.
+func validateTLSCertificate(cert, key, certField, keyField string) (string, error) {
+	var chain []*x509.Certificate
+	rest := []byte(cert)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		parsed, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("certificate %d of the chain can't be parsed: %w", len(chain)+1, err)
+		}
+		chain = append(chain, parsed)
+	}
+	if len(chain) == 0 {
+		return "", fmt.Errorf("%s does not contain a PEM encoded certificate", certField)
+	}
+
+	ordered := orderCertificateChain(chain)
+	if !slices.Equal(chain, ordered) {
+		var orderedPEM strings.Builder
+		for _, c := range ordered {
+			orderedPEM.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}))
+		}
+		cert = strings.TrimSpace(orderedPEM.String())
+	}
+
+	if block, _ := pem.Decode([]byte(key)); block == nil {
+		return "", fmt.Errorf("%s does not contain a PEM encoded private key", keyField)
+	}
+	if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+		return "", fmt.Errorf("%s is not the private key of the certificate in %s (%s): %w", keyField, certField, ordered[0].Subject.CommonName, err)
+	}
+	return cert, nil
+}
+
+// orderCertificateChain orders chain from the leaf, the certificate that issued none of the
+// others, to its issuers. Certificates that aren't issuers of the leaf, such as unrelated CAs
+// added to a bundle, are dropped. chain is returned as is if it has no leaf.
+func orderCertificateChain(chain []*x509.Certificate) []*x509.Certificate {
+	issued := func(issuer, subject *x509.Certificate) bool {
+		return issuer != subject && subject.CheckSignatureFrom(issuer) == nil
+	}
+
+	leaf := slices.IndexFunc(chain, func(c *x509.Certificate) bool {
+		return !slices.ContainsFunc(chain, func(other *x509.Certificate) bool { return issued(c, other) })
+	})
+	if leaf == -1 {
+		return chain
+	}
+
+	ordered := []*x509.Certificate{chain[leaf]}
+	remaining := slices.Delete(slices.Clone(chain), leaf, leaf+1)
+	for {
+		next := slices.IndexFunc(remaining, func(c *x509.Certificate) bool { return issued(c, ordered[len(ordered)-1]) })
+		if next == -1 {
+			return ordered
+		}
+		ordered = append(ordered, remaining[next])
+		remaining = slices.Delete(remaining, next, next+1)
+	}
+}
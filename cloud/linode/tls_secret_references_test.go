@@ -0,0 +1,333 @@
+package linode
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+)
+
+func newReferenceGrant(namespace, fromNamespace, secretName string) *unstructured.Unstructured {
+	to := map[string]interface{}{"group": "", "kind": "Secret"}
+	if secretName != "" {
+		to["name"] = secretName
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "ReferenceGrant",
+		"metadata":   map[string]interface{}{"name": "allow-" + fromNamespace, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"group": "", "kind": "Service", "namespace": fromNamespace}},
+			"to":   []interface{}{to},
+		},
+	}}
+}
+
+func Test_validateTLSSecretReference(t *testing.T) {
+	testcases := []struct {
+		reference string
+		valid     bool
+	}{
+		{reference: "web-tls", valid: true},
+		{reference: "cert-store/wildcard-tls", valid: true},
+		{reference: "cert-store/", valid: false},
+		{reference: "Cert_Store/wildcard-tls", valid: false},
+		{reference: "cert-store/wildcard-tls/extra", valid: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.reference, func(t *testing.T) {
+			err := validateTLSSecretReference(tc.reference)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func Test_checkTLSSecretReference(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{referenceGrantGVR: "ReferenceGrantList"},
+		newReferenceGrant("shared-certs", "granted", "wildcard-tls"),
+	)
+	lb := &loadbalancers{dynamicClient: dynamicClient, options: &options.Config{TLSSecretNamespaceAllowlist: []string{"cert-store"}}}
+
+	testcases := []struct {
+		name             string
+		serviceNamespace string
+		secretNamespace  string
+		secretName       string
+		permitted        bool
+	}{
+		{
+			name:             "same namespace",
+			serviceNamespace: "app",
+			secretNamespace:  "app",
+			secretName:       "web-tls",
+			permitted:        true,
+		},
+		{
+			name:             "allowlisted namespace",
+			serviceNamespace: "app",
+			secretNamespace:  "cert-store",
+			secretName:       "wildcard-tls",
+			permitted:        true,
+		},
+		{
+			name:             "granted by ReferenceGrant",
+			serviceNamespace: "granted",
+			secretNamespace:  "shared-certs",
+			secretName:       "wildcard-tls",
+			permitted:        true,
+		},
+		{
+			name:             "ReferenceGrant for another secret",
+			serviceNamespace: "granted",
+			secretNamespace:  "shared-certs",
+			secretName:       "other-tls",
+			permitted:        false,
+		},
+		{
+			name:             "ReferenceGrant for another namespace",
+			serviceNamespace: "app",
+			secretNamespace:  "shared-certs",
+			secretName:       "wildcard-tls",
+			permitted:        false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: tc.serviceNamespace}}
+			err := lb.checkTLSSecretReference(t.Context(), service, tc.secretNamespace, tc.secretName)
+			if tc.permitted {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "is not permitted to use TLS secret")
+			}
+		})
+	}
+}
+
+func Test_getTLSCertInfoCrossNamespace(t *testing.T) {
+	kubeClient := fake.NewClientset()
+	_, err := kubeClient.CoreV1().Secrets("cert-store").Create(t.Context(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "wildcard-tls", Namespace: "cert-store"},
+		Data: map[string][]byte{
+			v1.TLSCertKey:       []byte(testCert),
+			v1.TLSPrivateKeyKey: []byte(testKey),
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	cert, key, err := getTLSCertInfo(t.Context(), kubeClient, "app", portConfig{Port: 443, TLSSecretName: "cert-store/wildcard-tls"})
+	require.NoError(t, err)
+	assert.Equal(t, testCert, cert)
+	assert.Equal(t, testKey, key)
+}
+
+func Test_getTLSCertInfoCustomFields(t *testing.T) {
+	kubeClient := fake.NewClientset()
+	_, err := kubeClient.CoreV1().Secrets("app").Create(t.Context(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bundle", Namespace: "app"},
+		Type:       v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"fullchain.pem": []byte(testCert),
+			"privkey.pem":   []byte(testKey),
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	cert, key, err := getTLSCertInfo(t.Context(), kubeClient, "app", portConfig{
+		Port: 443, TLSSecretName: "bundle", TLSCertField: "fullchain.pem", TLSKeyField: "privkey.pem",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testCert, cert)
+	assert.Equal(t, testKey, key)
+
+	_, _, err = getTLSCertInfo(t.Context(), kubeClient, "app", portConfig{Port: 443, TLSSecretName: "bundle", TLSCertField: "fullchain.pem"})
+	assert.ErrorContains(t, err, "tls.key does not contain a PEM encoded private key")
+}
+
+func Test_getPortTLSFields(t *testing.T) {
+	testcases := []struct {
+		name          string
+		annotation    portConfigAnnotation
+		certField     string
+		keyField      string
+		expectedError string
+	}{
+		{
+			name:       "defaults",
+			annotation: portConfigAnnotation{TLSSecretName: "tls"},
+		},
+		{
+			name:       "custom keys",
+			annotation: portConfigAnnotation{TLSSecretName: "tls", TLSCertField: "fullchain.pem", TLSKeyField: "privkey.pem"},
+			certField:  "fullchain.pem",
+			keyField:   "privkey.pem",
+		},
+		{
+			name:          "without secret",
+			annotation:    portConfigAnnotation{TLSCertField: "fullchain.pem"},
+			expectedError: "require tls-secret-name",
+		},
+		{
+			name:          "invalid key",
+			annotation:    portConfigAnnotation{TLSSecretName: "tls", TLSKeyField: "priv/key"},
+			expectedError: `invalid TLS secret key "priv/key"`,
+		},
+		{
+			name:          "same key",
+			annotation:    portConfigAnnotation{TLSSecretName: "tls", TLSCertField: "tls.key"},
+			expectedError: "must not be the same key",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			certField, keyField, err := getPortTLSFields(tc.annotation)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.certField, certField)
+			assert.Equal(t, tc.keyField, keyField)
+		})
+	}
+}
+
+func Test_validateTLSCertificate(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "linode.test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, caCert, &leafKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	leafKeyDER, err := x509.MarshalECPrivateKey(leafKey)
+	require.NoError(t, err)
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	require.NoError(t, err)
+
+	encode := func(blockType string, der []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+	}
+	leafPEM := encode("CERTIFICATE", leafDER)
+	caPEM := encode("CERTIFICATE", caDER)
+	leafKeyPEM := encode("EC PRIVATE KEY", leafKeyDER)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "other-ca"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, caTemplate, &otherKey.PublicKey, otherKey)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name          string
+		cert          string
+		key           string
+		expectedCert  string
+		expectedError string
+	}{
+		{
+			name:         "single certificate",
+			cert:         testCert,
+			key:          testKey,
+			expectedCert: testCert,
+		},
+		{
+			name:         "ordered chain",
+			cert:         leafPEM + caPEM,
+			key:          leafKeyPEM,
+			expectedCert: leafPEM + caPEM,
+		},
+		{
+			name:         "reversed chain is reordered",
+			cert:         caPEM + leafPEM,
+			key:          leafKeyPEM,
+			expectedCert: strings.TrimSpace(leafPEM + caPEM),
+		},
+		{
+			name:         "certificate outside of the chain is dropped",
+			cert:         leafPEM + encode("CERTIFICATE", otherDER) + caPEM,
+			key:          leafKeyPEM,
+			expectedCert: strings.TrimSpace(leafPEM + caPEM),
+		},
+		{
+			name:          "key of another certificate",
+			cert:          leafPEM + caPEM,
+			key:           encode("EC PRIVATE KEY", caKeyDER),
+			expectedError: "tls.key is not the private key of the certificate in tls.crt (linode.test)",
+		},
+		{
+			name:          "missing key",
+			cert:          testCert,
+			key:           "",
+			expectedError: "tls.key does not contain a PEM encoded private key",
+		},
+		{
+			name:          "missing certificate",
+			cert:          "",
+			key:           testKey,
+			expectedError: "tls.crt does not contain a PEM encoded certificate",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cert, err := validateTLSCertificate(tc.cert, tc.key, v1.TLSCertKey, v1.TLSPrivateKeyKey)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedCert, cert)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -34,6 +34,8 @@ import (
 	"k8s.io/kubernetes/pkg/controller/nodeipam/ipam/cidrset"
 
 	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
 )
 
 // CIDRAllocatorType is the type of the allocator to use.
@@ -94,6 +96,11 @@ type CIDRAllocatorParams struct {
 	// NodeCIDRMaskSizes is list of node cidr mask sizes.
 	NodeCIDRMaskSizes             []int
 	DisableIPv6NodeCIDRAllocation bool
+	// Options is the configuration of the cloud provider, its VPC and subnet
+	// names decide whether the final IPv4 block of the cluster cidr is reserved.
+	Options *options.Config
+	// VPCs resolves the configured VPC and subnet names to their IDs.
+	VPCs *services.VPCCache
 }
 
 // New creates a new CIDR range allocator.
@@ -106,7 +106,7 @@ func NewLinodeCIDRAllocator(ctx context.Context, linodeClient linode.Client, cli
 	// Using Linode API, check if we need to reserve the final block in the cluster CIDR.
 	// Reserve when cluster CIDR last IP is the same as the VPC subnet last IP.
 	// We cannot reserve that block since the last IP is a reserved IP for VPC functionality.
-	reserveFinalIPv4Block, err := shouldReserveFinalIPv4Block(ctx, linodeClient, allocatorParams.ClusterCIDRs[0])
+	reserveFinalIPv4Block, err := shouldReserveFinalIPv4Block(ctx, linodeClient, allocatorParams.Options, allocatorParams.VPCs, allocatorParams.ClusterCIDRs[0])
 	if err != nil {
 		return nil, err
 	}
@@ -205,16 +205,16 @@ func NewLinodeCIDRAllocator(ctx context.Context, linodeClient linode.Client, cli
 	return ca, nil
 }
 
-func shouldReserveFinalIPv4Block(ctx context.Context, client linode.Client, clusterCIDR *net.IPNet) (bool, error) {
-	if clusterCIDR == nil || len(options.Options.VPCNames) == 0 || len(options.Options.SubnetNames) == 0 {
+func shouldReserveFinalIPv4Block(ctx context.Context, client linode.Client, opts *options.Config, vpcs *services.VPCCache, clusterCIDR *net.IPNet) (bool, error) {
+	if clusterCIDR == nil || opts == nil || len(opts.VPCNames) == 0 || len(opts.SubnetNames) == 0 {
 		return false, nil
 	}
 
-	vpcID, err := services.GetVPCID(ctx, client, options.Options.VPCNames[0])
+	vpcID, err := vpcs.GetVPCID(ctx, client, opts.VPCNames[0])
 	if err != nil {
 		return false, err
 	}
-	subnetID, err := services.GetSubnetID(ctx, client, vpcID, options.Options.SubnetNames[0])
+	subnetID, err := vpcs.GetSubnetID(ctx, client, vpcID, opts.SubnetNames[0])
 	if err != nil {
 		return false, err
 	}
@@ -127,7 +127,7 @@ func TestNewLinodeCIDRAllocatorFinalCIDRReservation(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			setNodeIPAMSubnetOptionsForTest(t, tc.vpcNames, tc.subnetNames)
+			opts, vpcs := newNodeIPAMSubnetOptionsForTest(tc.vpcNames, tc.subnetNames)
 
 			var client *mocks.MockClient
 			if tc.subnetCIDR != "" {
@@ -142,6 +142,8 @@ func TestNewLinodeCIDRAllocatorFinalCIDRReservation(t *testing.T) {
 				ClusterCIDRs:      []*net.IPNet{clusterCIDR},
 				ServiceCIDR:       nil,
 				NodeCIDRMaskSizes: []int{30, 112},
+				Options:           opts,
+				VPCs:              vpcs,
 			}
 
 			allocator, err := NewLinodeCIDRAllocator(tCtx, client, fakeNodeHandler, test.FakeNodeInformer(fakeNodeHandler), allocatorParams, nil)
@@ -205,7 +207,7 @@ func TestShouldReserveFinalIPv4Block(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			setNodeIPAMSubnetOptionsForTest(t, tc.vpcNames, tc.subnetNames)
+			opts, vpcs := newNodeIPAMSubnetOptionsForTest(tc.vpcNames, tc.subnetNames)
 
 			var client *mocks.MockClient
 			if tc.subnetCIDR != "" {
@@ -214,7 +216,7 @@ func TestShouldReserveFinalIPv4Block(t *testing.T) {
 				expectNodeIPAMSubnetLookup(client, tc.subnetCIDR)
 			}
 
-			reserve, err := shouldReserveFinalIPv4Block(context.Background(), client, clusterCIDR)
+			reserve, err := shouldReserveFinalIPv4Block(context.Background(), client, opts, vpcs, clusterCIDR)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -231,25 +233,9 @@ func expectNodeIPAMSubnetLookup(client *mocks.MockClient, subnetCIDR string) {
 	client.EXPECT().GetVPCSubnet(gomock.Any(), 1, 2).Return(&linodego.VPCSubnet{ID: 2, Label: "default", IPv4: subnetCIDR}, nil)
 }
 
-func setNodeIPAMSubnetOptionsForTest(t *testing.T, vpcNames, subnetNames []string) {
-	t.Helper()
-
-	prevVPCNames := options.Options.VPCNames
-	prevSubnetNames := options.Options.SubnetNames
-	prevVpcIDs := services.VpcIDs
-	prevSubnetIDs := services.SubnetIDs
-
-	options.Options.VPCNames = vpcNames
-	options.Options.SubnetNames = subnetNames
-	services.VpcIDs = map[string]int{}
-	services.SubnetIDs = map[string]int{}
-
-	t.Cleanup(func() {
-		options.Options.VPCNames = prevVPCNames
-		options.Options.SubnetNames = prevSubnetNames
-		services.VpcIDs = prevVpcIDs
-		services.SubnetIDs = prevSubnetIDs
-	})
+func newNodeIPAMSubnetOptionsForTest(vpcNames, subnetNames []string) (*options.Config, *services.VPCCache) {
+	opts := &options.Config{VPCNames: vpcNames, SubnetNames: subnetNames}
+	return opts, services.NewVPCCache(opts)
 }
 
 func TestGetIPv6RangeFromLinodeInterface(t *testing.T) {
@@ -33,6 +33,8 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/linode/linode-cloud-controller-manager/cloud/linode/client"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/options"
+	"github.com/linode/linode-cloud-controller-manager/cloud/linode/services"
 	"github.com/linode/linode-cloud-controller-manager/cloud/nodeipam/ipam"
 )
 
@@ -70,7 +72,8 @@ func NewNodeIpamController(
 	secondaryServiceCIDR *net.IPNet,
 	nodeCIDRMaskSizes []int,
 	allocatorType ipam.CIDRAllocatorType,
-	disableIPv6NodeCIDRAllocation bool,
+	opts *options.Config,
+	vpcs *services.VPCCache,
 ) (*Controller, error) {
 	if kubeClient == nil {
 		return nil, fmt.Errorf("kubeClient is nil when starting Controller")
@@ -105,7 +108,9 @@ func NewNodeIpamController(
 		ServiceCIDR:                   ic.serviceCIDR,
 		SecondaryServiceCIDR:          ic.secondaryServiceCIDR,
 		NodeCIDRMaskSizes:             nodeCIDRMaskSizes,
-		DisableIPv6NodeCIDRAllocation: disableIPv6NodeCIDRAllocation,
+		DisableIPv6NodeCIDRAllocation: opts.DisableIPv6NodeCIDRAllocation,
+		Options:                       opts,
+		VPCs:                          vpcs,
 	}
 
 	ic.cidrAllocator, err = ipam.New(ctx, ic.linodeClient, kubeClient, cloud, nodeInformer, ic.allocatorType, allocatorParams)
@@ -4,8 +4,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"net"
 	"os"
+	"time"
 
 	"github.com/linode/linodego/v2"
 	"github.com/spf13/pflag"
@@ -27,10 +27,9 @@ import (
 )
 
 const (
-	sentryDSNVariable            = "SENTRY_DSN"
-	sentryEnvironmentVariable    = "SENTRY_ENVIRONMENT"
-	sentryReleaseVariable        = "SENTRY_RELEASE"
-	linodeExternalSubnetVariable = "LINODE_EXTERNAL_SUBNET"
+	sentryDSNVariable         = "SENTRY_DSN"
+	sentryEnvironmentVariable = "SENTRY_ENVIRONMENT"
+	sentryReleaseVariable     = "SENTRY_RELEASE"
 )
 
 func initializeSentry() {
@@ -78,32 +77,59 @@ func main() {
 	fss := utilflag.NamedFlagSets{}
 	controllerAliases := names.CCMControllerAliases()
 	stopCh := make(chan struct{})
-	command := app.NewCloudControllerManagerCommand(opts, cloudInitializer, app.DefaultInitFuncConstructors, controllerAliases, fss, stopCh)
+	linodeOptions := &ccmOptions.Config{}
+	command := app.NewCloudControllerManagerCommand(opts, newCloudInitializer(linodeOptions), app.DefaultInitFuncConstructors, controllerAliases, fss, stopCh)
 
 	// Add Linode-specific flags
-	command.Flags().BoolVar(&ccmOptions.Options.LinodeGoDebug, "linodego-debug", false, "enables debug output for the LinodeAPI wrapper")
-	command.Flags().BoolVar(&ccmOptions.Options.EnableRouteController, "enable-route-controller", false, "enables route_controller for ccm")
-	command.Flags().BoolVar(&ccmOptions.Options.EnableTokenHealthChecker, "enable-token-health-checker", false, "enables Linode API token health checker")
-	command.Flags().StringSliceVar(&ccmOptions.Options.VPCNames, "vpc-names", nil, "comma separated vpc names whose routes will be managed by route-controller")
-	command.Flags().StringSliceVar(&ccmOptions.Options.SubnetNames, "subnet-names", []string{"default"}, "comma separated subnet names whose routes will be managed by route-controller (requires vpc-names flag to also be set)")
-	command.Flags().IntSliceVar(&ccmOptions.Options.VPCIDs, "vpc-ids", nil, "comma separated vpc ids whose routes will be managed by route-controller")
-	command.Flags().IntSliceVar(&ccmOptions.Options.SubnetIDs, "subnet-ids", nil, "comma separated subnet ids whose routes will be managed by route-controller (requires vpc-ids flag to also be set)")
-	command.Flags().StringVar(&ccmOptions.Options.LoadBalancerType, "load-balancer-type", "nodebalancer", "configures the load-balancing type for LoadBalancer Services; cilium-bgp is deprecated and treated as nodebalancer")
-	command.Flags().StringVar(&ccmOptions.Options.BGPNodeSelector, "bgp-node-selector", "", "DEPRECATED: no effect; retained for backwards compatibility")
-	command.Flags().StringVar(&ccmOptions.Options.IpHolderSuffix, "ip-holder-suffix", "", "DEPRECATED: no effect; retained for backwards compatibility")
-	command.Flags().StringVar(&ccmOptions.Options.DefaultNBType, "default-nodebalancer-type", string(linodego.NBTypeCommon), "default type of NodeBalancer to create (options: common, premium, premium_40GB)")
-	command.Flags().StringVar(&ccmOptions.Options.NodeBalancerBackendIPv4Subnet, "nodebalancer-backend-ipv4-subnet", "", "ipv4 subnet to use for NodeBalancer backends")
-	command.Flags().StringSliceVar(&ccmOptions.Options.NodeBalancerTags, "nodebalancer-tags", []string{}, "Linode tags to apply to all NodeBalancers")
-	command.Flags().BoolVar(&ccmOptions.Options.EnableIPv6ForLoadBalancers, "enable-ipv6-for-loadbalancers", false, "set both IPv4 and IPv6 addresses for all LoadBalancer services (when disabled, only IPv4 is used)")
-	command.Flags().BoolVar(&ccmOptions.Options.EnableIPv6ForNodeBalancerBackends, "enable-ipv6-for-nodebalancer-backends", false, "use public IPv6 addresses for NodeBalancer service backends, including VPC-backed NodeBalancers (when enabled, may update existing services during reconciliation and all selected backend nodes must have public IPv6)")
-	command.Flags().IntVar(&ccmOptions.Options.NodeCIDRMaskSizeIPv4, "node-cidr-mask-size-ipv4", 0, "ipv4 cidr mask size for pod cidrs allocated to nodes")
-	command.Flags().IntVar(&ccmOptions.Options.NodeCIDRMaskSizeIPv6, "node-cidr-mask-size-ipv6", 0, "ipv6 cidr mask size for pod cidrs allocated to nodes")
-	command.Flags().IntVar(&ccmOptions.Options.NodeBalancerBackendIPv4SubnetID, "nodebalancer-backend-ipv4-subnet-id", 0, "ipv4 subnet id to use for NodeBalancer backends")
-	command.Flags().StringVar(&ccmOptions.Options.NodeBalancerBackendIPv4SubnetName, "nodebalancer-backend-ipv4-subnet-name", "", "ipv4 subnet name to use for NodeBalancer backends")
-	command.Flags().BoolVar(&ccmOptions.Options.DisableNodeBalancerVPCBackends, "disable-nodebalancer-vpc-backends", false, "disables nodebalancer backends in VPCs (when enabled, nodebalancers will only have private IPs as backends for backward compatibility)")
-	command.Flags().StringVar(&ccmOptions.Options.NodeBalancerPrefix, "nodebalancer-prefix", "ccm", fmt.Sprintf("Name prefix for NoadBalancers. (max. %v char.)", linode.NodeBalancerPrefixCharLimit))
-	command.Flags().BoolVar(&ccmOptions.Options.DisableIPv6NodeCIDRAllocation, "disable-ipv6-node-cidr-allocation", false, "disables IPv6 node cidr allocation by ipam controller (when enabled, IPv6 cidr ranges will be allocated to nodes)")
-	command.Flags().StringVar(&ccmOptions.Options.LinodeTagFilter, "linode-tag-filter", "", "tag filter for linodes (e.g. cluster name)")
+	command.Flags().BoolVar(&linodeOptions.LinodeGoDebug, "linodego-debug", false, "enables debug output for the LinodeAPI wrapper")
+	command.Flags().BoolVar(&linodeOptions.EnableRouteController, "enable-route-controller", false, "enables route_controller for ccm")
+	command.Flags().BoolVar(&linodeOptions.EnableTokenHealthChecker, "enable-token-health-checker", false, "enables Linode API token health checker")
+	command.Flags().StringSliceVar(&linodeOptions.VPCNames, "vpc-names", nil, "comma separated vpc names whose routes will be managed by route-controller")
+	command.Flags().StringSliceVar(&linodeOptions.SubnetNames, "subnet-names", []string{"default"}, "comma separated subnet names whose routes will be managed by route-controller (requires vpc-names flag to also be set)")
+	command.Flags().IntSliceVar(&linodeOptions.VPCIDs, "vpc-ids", nil, "comma separated vpc ids whose routes will be managed by route-controller")
+	command.Flags().IntSliceVar(&linodeOptions.SubnetIDs, "subnet-ids", nil, "comma separated subnet ids whose routes will be managed by route-controller (requires vpc-ids flag to also be set)")
+	command.Flags().StringVar(&linodeOptions.LoadBalancerType, "load-balancer-type", "nodebalancer", "configures the load-balancing type for LoadBalancer Services; cilium-bgp is deprecated and treated as nodebalancer")
+	command.Flags().StringVar(&linodeOptions.BGPNodeSelector, "bgp-node-selector", "", "DEPRECATED: no effect; retained for backwards compatibility")
+	command.Flags().StringVar(&linodeOptions.IpHolderSuffix, "ip-holder-suffix", "", "DEPRECATED: no effect; retained for backwards compatibility")
+	command.Flags().StringVar(&linodeOptions.DefaultNBType, "default-nodebalancer-type", string(linodego.NBTypeCommon), "default type of NodeBalancer to create (options: common, premium, premium_40GB)")
+	command.Flags().StringVar(&linodeOptions.NodeBalancerBackendIPv4Subnet, "nodebalancer-backend-ipv4-subnet", "", "ipv4 subnet to use for NodeBalancer backends")
+	command.Flags().StringSliceVar(&linodeOptions.NodeBalancerTags, "nodebalancer-tags", []string{}, "Linode tags to apply to all NodeBalancers")
+	command.Flags().BoolVar(&linodeOptions.EnableIPv6ForLoadBalancers, "enable-ipv6-for-loadbalancers", false, "set both IPv4 and IPv6 addresses for all LoadBalancer services (when disabled, only IPv4 is used)")
+	command.Flags().BoolVar(&linodeOptions.EnableIPv6ForNodeBalancerBackends, "enable-ipv6-for-nodebalancer-backends", false, "use public IPv6 addresses for NodeBalancer service backends, including VPC-backed NodeBalancers (when enabled, may update existing services during reconciliation and all selected backend nodes must have public IPv6)")
+	command.Flags().IntVar(&linodeOptions.NodeCIDRMaskSizeIPv4, "node-cidr-mask-size-ipv4", 0, "ipv4 cidr mask size for pod cidrs allocated to nodes")
+	command.Flags().IntVar(&linodeOptions.NodeCIDRMaskSizeIPv6, "node-cidr-mask-size-ipv6", 0, "ipv6 cidr mask size for pod cidrs allocated to nodes")
+	command.Flags().IntVar(&linodeOptions.NodeBalancerBackendIPv4SubnetID, "nodebalancer-backend-ipv4-subnet-id", 0, "ipv4 subnet id to use for NodeBalancer backends")
+	command.Flags().StringVar(&linodeOptions.NodeBalancerBackendIPv4SubnetName, "nodebalancer-backend-ipv4-subnet-name", "", "ipv4 subnet name to use for NodeBalancer backends")
+	command.Flags().BoolVar(&linodeOptions.DisableNodeBalancerVPCBackends, "disable-nodebalancer-vpc-backends", false, "disables nodebalancer backends in VPCs (when enabled, nodebalancers will only have private IPs as backends for backward compatibility)")
+	command.Flags().StringVar(&linodeOptions.NodeBalancerPrefix, "nodebalancer-prefix", "ccm", fmt.Sprintf("Name prefix for NoadBalancers. (max. %v char.)", linode.NodeBalancerPrefixCharLimit))
+	command.Flags().StringVar(&linodeOptions.NodeBalancerLabelMode, "nodebalancer-label-mode", linode.NodeBalancerLabelModeTimestamp, "how NodeBalancers are labeled (options: timestamp, service); service derives a stable label from the cluster name, namespace and service name and renames existing NodeBalancers")
+	command.Flags().StringVar(&linodeOptions.NodeBalancerLabelTemplate, "nodebalancer-label-template", linode.DefaultNodeBalancerLabelTemplate, "Go template for NodeBalancer labels in service label mode, with .ClusterName, .Namespace and .Name; the label is prefixed with the nodebalancer-prefix and suffixed with a hash")
+	command.Flags().BoolVar(&linodeOptions.DisableIPv6NodeCIDRAllocation, "disable-ipv6-node-cidr-allocation", false, "disables IPv6 node cidr allocation by ipam controller (when enabled, IPv6 cidr ranges will be allocated to nodes)")
+	command.Flags().StringVar(&linodeOptions.LinodeTagFilter, "linode-tag-filter", "", "tag filter for linodes (e.g. cluster name)")
+	command.Flags().BoolVar(&linodeOptions.EnableEndpointAwareBackends, "enable-endpoint-aware-backends", false, "limit NodeBalancer backends to nodes hosting ready endpoints of the service (can be overridden per service)")
+	command.Flags().StringSliceVar(&linodeOptions.TLSSecretNamespaceAllowlist, "tls-secret-namespace-allowlist", []string{}, "namespaces whose TLS secrets can be referenced by services in any namespace")
+	command.Flags().BoolVar(&linodeOptions.EnableServiceWebhook, "enable-service-webhook", false, "serves a validating admission webhook for Linode load balancer annotations on Services")
+	command.Flags().IntVar(&linodeOptions.ServiceWebhookPort, "service-webhook-port", 9443, "port the service admission webhook listens on")
+	command.Flags().BoolVar(&linodeOptions.EnableNodeBalancerGC, "enable-nodebalancer-gc", false, "periodically delete NodeBalancers and CCM-created firewalls of this cluster that are no longer used by any service")
+	command.Flags().DurationVar(&linodeOptions.NodeBalancerGCInterval, "nodebalancer-gc-interval", 10*time.Minute, "how often the NodeBalancer garbage collector looks for orphaned NodeBalancers")
+	command.Flags().DurationVar(&linodeOptions.NodeBalancerGCGracePeriod, "nodebalancer-gc-grace-period", time.Hour, "how long a NodeBalancer must be orphaned before the garbage collector deletes it")
+	command.Flags().BoolVar(&linodeOptions.NodeBalancerGCReportOnly, "nodebalancer-gc-report-only", false, "log and export metrics for orphaned NodeBalancers without deleting them")
+	command.Flags().BoolVar(&linodeOptions.EnableNodeBalancerHealth, "enable-nodebalancer-health", false, "periodically poll the backend health of NodeBalancers and report it as service conditions, events and metrics")
+	command.Flags().DurationVar(&linodeOptions.NodeBalancerHealthInterval, "nodebalancer-health-interval", time.Minute, "how often the backend health of NodeBalancers is polled")
+	command.Flags().BoolVar(&linodeOptions.EnableNodeBalancerStats, "enable-nodebalancer-stats", false, "periodically export the traffic statistics and backend health of NodeBalancers as metrics")
+	command.Flags().DurationVar(&linodeOptions.NodeBalancerStatsInterval, "nodebalancer-stats-interval", 5*time.Minute, "how often NodeBalancer statistics are collected")
+	command.Flags().IntVar(&linodeOptions.NodeBalancerStatsMaxServices, "nodebalancer-stats-max-services", 100, "maximum number of services whose NodeBalancer statistics are collected, 0 for no limit")
+	command.Flags().Float64Var(&linodeOptions.LinodeAPIRequestsPerSecond, "linode-api-requests-per-second", 10, "sustained rate of Linode API calls shared by all controllers, 0 to disable client-side rate limiting")
+	command.Flags().IntVar(&linodeOptions.LinodeAPIBurst, "linode-api-burst", 20, "number of Linode API calls allowed in a burst above linode-api-requests-per-second")
+	command.Flags().StringToStringVar(&linodeOptions.LinodeAPIMethodRequestsPerSecond, "linode-api-method-requests-per-second", nil, "additional per-endpoint budgets in requests per second, keyed by client method (e.g. ListInstances=2,CreateNodeBalancer=0.5)")
+	command.Flags().IntVar(&linodeOptions.LinodeAPIMaxRetries, "linode-api-max-retries", 5, "how many times a Linode API call rejected as rate limited (429) or busy is retried")
+	command.Flags().DurationVar(&linodeOptions.LinodeAPIRetryBaseDelay, "linode-api-retry-base-delay", time.Second, "delay before the first retry of a Linode API call, doubled for every further retry unless the API sends Retry-After")
+	command.Flags().DurationVar(&linodeOptions.LinodeAPIRetryMaxDelay, "linode-api-retry-max-delay", 30*time.Second, "maximum delay between retries of a Linode API call")
+	command.Flags().DurationVar(&linodeOptions.LinodeAPICacheTTL, "linode-api-cache-ttl", 0, "how long NodeBalancer, firewall and VPC reads from the Linode API are cached, 0 to disable caching")
+	command.Flags().BoolVar(&linodeOptions.Plan, "plan", false, "print the NodeBalancer changes that would be made for every LoadBalancer service as JSON and exit, without changing anything")
+	command.Flags().BoolVar(&linodeOptions.PrintEffectiveConfig, "print-effective-config", false, "print the configuration resulting from the cloud-config file, environment variables and flags as YAML and exit")
+	command.Flags().DurationVar(&linodeOptions.ConfigReloadInterval, "cloud-config-reload-interval", 30*time.Second, "how often the cloud-config file is checked for changes of settings that can be applied without a restart, 0 to only read it at startup")
+	command.Flags().StringVar(&linodeOptions.ServiceWebhookCertDir, "service-webhook-cert-dir", "/etc/ccm-linode/webhook-certs", "directory containing tls.crt and tls.key for the service admission webhook")
 
 	// Set static flags
 	command.Flags().VisitAll(func(fl *pflag.Flag) {
@@ -128,27 +154,19 @@ func main() {
 	})
 
 	// Make the Linode-specific CCM bits aware of the kubeconfig flag
-	ccmOptions.Options.KubeconfigFlag = command.Flags().Lookup("kubeconfig")
-	if ccmOptions.Options.KubeconfigFlag == nil {
+	linodeOptions.KubeconfigFlag = command.Flags().Lookup("kubeconfig")
+	if linodeOptions.KubeconfigFlag == nil {
 		msg := "kubeconfig missing from CCM flag set"
 		sentry.CaptureError(ctx, fmt.Errorf("%s", msg))
 		fmt.Fprintf(os.Stderr, "kubeconfig missing from CCM flag set"+"\n")
 		os.Exit(1)
 	}
 
-	if externalSubnet, ok := os.LookupEnv(linodeExternalSubnetVariable); ok && externalSubnet != "" {
-		_, network, err := net.ParseCIDR(externalSubnet)
-		if err != nil {
-			msg := fmt.Sprintf("Unable to parse %s as network subnet: %v", externalSubnet, err)
-			sentry.CaptureError(ctx, fmt.Errorf("%s", msg))
-			fmt.Fprintf(os.Stderr, "%v\n", msg)
-			os.Exit(1)
-		}
-		ccmOptions.Options.LinodeExternalNetwork = network
-	}
+	// Let the cloud-config file of the Linode CCM tell which settings were passed as flags
+	linodeOptions.Flags = command.Flags()
 
 	// Provide stop channel for linode authenticated client healthchecker
-	ccmOptions.Options.GlobalStopChannel = stopCh
+	linodeOptions.GlobalStopChannel = stopCh
 
 	pflag.CommandLine.SetNormalizeFunc(utilflag.WordSepNormalizeFunc)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
@@ -163,17 +181,35 @@ func main() {
 	}
 }
 
-func cloudInitializer(config *config.CompletedConfig) cloudprovider.Interface {
+// newCloudInitializer returns the function initializing the Linode cloud
+// provider with the given options once the CCM configuration is complete.
+func newCloudInitializer(linodeOptions *ccmOptions.Config) func(*config.CompletedConfig) cloudprovider.Interface {
+	return func(config *config.CompletedConfig) cloudprovider.Interface {
+		return cloudInitializer(linodeOptions, config)
+	}
+}
+
+func cloudInitializer(linodeOptions *ccmOptions.Config, config *config.CompletedConfig) cloudprovider.Interface {
 	// initialize cloud provider with the cloud provider name and config file provided
+	linodeOptions.ClusterName = config.ComponentConfig.KubeCloudShared.ClusterName
 	if config.ComponentConfig.KubeCloudShared.AllocateNodeCIDRs {
-		ccmOptions.Options.AllocateNodeCIDRs = true
+		linodeOptions.AllocateNodeCIDRs = true
 		if config.ComponentConfig.KubeCloudShared.ClusterCIDR == "" {
 			fmt.Fprintf(os.Stderr, "--cluster-cidr is not set. This is required if --allocate-node-cidrs is set.\n")
 			os.Exit(1)
 		}
-		ccmOptions.Options.ClusterCIDRIPv4 = config.ComponentConfig.KubeCloudShared.ClusterCIDR
+		linodeOptions.ClusterCIDRIPv4 = config.ComponentConfig.KubeCloudShared.ClusterCIDR
 	}
-	cloud, err := cloudprovider.InitCloudProvider(linode.ProviderName, "")
+	cloudConfigFile := config.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile
+	if linodeOptions.PrintEffectiveConfig {
+		if err := linode.PrintEffectiveConfig(linodeOptions, cloudConfigFile, os.Stdout); err != nil {
+			klog.Fatalf("failed to print effective config: %v", err)
+		}
+		klog.Flush()
+		os.Exit(0)
+	}
+
+	cloud, err := linode.NewCloud(linodeOptions, cloudConfigFile)
 	if err != nil {
 		klog.Fatalf("Cloud provider could not be initialized: %v", err)
 	}
@@ -189,5 +225,13 @@ func cloudInitializer(config *config.CompletedConfig) cloudprovider.Interface {
 		}
 	}
 
+	if linodeOptions.Plan {
+		if err := linode.PlanLoadBalancers(context.Background(), cloud, config.Kubeconfig, os.Stdout); err != nil {
+			klog.Fatalf("failed to plan load balancers: %v", err)
+		}
+		klog.Flush()
+		os.Exit(0)
+	}
+
 	return cloud
 }